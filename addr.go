@@ -0,0 +1,491 @@
+package bytebuilder
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+)
+
+// ErrInvalidAddr is returned when an address can not be encoded in the requested form
+// (eg.: the zero netip.Addr, an IPv6 address with a zone or as 4 bytes, or a MAC address that is not 6 or 8 bytes long)
+// or a decoded prefix length is longer than the address.
+var ErrInvalidAddr = errors.New("invalid address")
+
+// Addresses are written in network byte order, the port of a netip.AddrPort is always big-endian.
+// The formats have no room for the zone of an IPv6 address, so a zoned address is rejected with ErrInvalidAddr.
+
+// ReadAddr4 removes an IPv4 address (4 bytes) from the start of b and returns it.
+// The bool indicates whether the read was successful.
+func ReadAddr4(b *[]byte) (netip.Addr, bool) {
+
+	v, err := ReadAddr4E(b)
+
+	return v, err == nil
+}
+
+// ReadAddr4E is like ReadAddr4, but returns a *DecodeError if the read failed.
+func ReadAddr4E(b *[]byte) (netip.Addr, error) {
+
+	var v netip.Addr
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadAddr4E()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadAddr16 removes an IPv6 address (16 bytes) from the start of b and returns it.
+// An IPv4-mapped address is returned as it is, use Unmap to convert it to IPv4.
+// The bool indicates whether the read was successful.
+func ReadAddr16(b *[]byte) (netip.Addr, bool) {
+
+	v, err := ReadAddr16E(b)
+
+	return v, err == nil
+}
+
+// ReadAddr16E is like ReadAddr16, but returns a *DecodeError if the read failed.
+func ReadAddr16E(b *[]byte) (netip.Addr, error) {
+
+	var v netip.Addr
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadAddr16E()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadAddrPort4 removes an IPv4 address and a port (6 bytes) from the start of b and returns it.
+// The bool indicates whether the read was successful.
+func ReadAddrPort4(b *[]byte) (netip.AddrPort, bool) {
+
+	v, err := ReadAddrPort4E(b)
+
+	return v, err == nil
+}
+
+// ReadAddrPort4E is like ReadAddrPort4, but returns a *DecodeError if the read failed.
+func ReadAddrPort4E(b *[]byte) (netip.AddrPort, error) {
+
+	var v netip.AddrPort
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadAddrPort4E()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadAddrPort16 removes an IPv6 address and a port (18 bytes) from the start of b and returns it.
+// The bool indicates whether the read was successful.
+func ReadAddrPort16(b *[]byte) (netip.AddrPort, bool) {
+
+	v, err := ReadAddrPort16E(b)
+
+	return v, err == nil
+}
+
+// ReadAddrPort16E is like ReadAddrPort16, but returns a *DecodeError if the read failed.
+func ReadAddrPort16E(b *[]byte) (netip.AddrPort, error) {
+
+	var v netip.AddrPort
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadAddrPort16E()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadPrefix4 removes an IPv4 prefix encoded as a length byte and the significant bytes of the address
+// (eg.: BGP NLRI) from the start of b and returns it.
+// The bits after the prefix length are cleared.
+// The bool indicates whether the read was successful.
+func ReadPrefix4(b *[]byte) (netip.Prefix, bool) {
+
+	v, err := ReadPrefix4E(b)
+
+	return v, err == nil
+}
+
+// ReadPrefix4E is like ReadPrefix4, but returns a *DecodeError if the read failed.
+func ReadPrefix4E(b *[]byte) (netip.Prefix, error) {
+
+	var v netip.Prefix
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadPrefix4E()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadPrefix16 removes an IPv6 prefix encoded as a length byte and the significant bytes of the address
+// (eg.: BGP NLRI) from the start of b and returns it.
+// The bits after the prefix length are cleared.
+// The bool indicates whether the read was successful.
+func ReadPrefix16(b *[]byte) (netip.Prefix, bool) {
+
+	v, err := ReadPrefix16E(b)
+
+	return v, err == nil
+}
+
+// ReadPrefix16E is like ReadPrefix16, but returns a *DecodeError if the read failed.
+func ReadPrefix16E(b *[]byte) (netip.Prefix, error) {
+
+	var v netip.Prefix
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadPrefix16E()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadHardwareAddr removes a MAC address of n bytes from the start of b and returns a copy of it.
+// n must be 6 (EUI-48) or 8 (EUI-64).
+// The bool indicates whether the read was successful.
+func ReadHardwareAddr(b *[]byte, n int) (net.HardwareAddr, bool) {
+
+	v, err := ReadHardwareAddrE(b, n)
+
+	return v, err == nil
+}
+
+// ReadHardwareAddrE is like ReadHardwareAddr, but returns a *DecodeError if the read failed.
+// If n is not 6 or 8, the error wraps ErrInvalidLength.
+func ReadHardwareAddrE(b *[]byte, n int) (net.HardwareAddr, error) {
+
+	var v net.HardwareAddr
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadHardwareAddrE(n)
+		return err
+	})
+
+	return v, err
+}
+
+// WriteAddr appends a at the end of b in its natural size: 4 bytes for IPv4, 16 bytes for IPv6 (including IPv4-mapped).
+// Returns an *EncodeError if a is the zero Addr or has a zone.
+func WriteAddr(b *[]byte, a netip.Addr) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteAddr(a)
+	})
+}
+
+// WriteAddr4 appends a at the end of b as 4 bytes. An IPv4-mapped IPv6 address is unmapped.
+// Returns an *EncodeError if a is not an IPv4 or IPv4-mapped address.
+func WriteAddr4(b *[]byte, a netip.Addr) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteAddr4(a)
+	})
+}
+
+// WriteAddr16 appends a at the end of b as 16 bytes. An IPv4 address is written as IPv4-mapped (::ffff:a.b.c.d).
+// Returns an *EncodeError if a is the zero Addr or has a zone.
+func WriteAddr16(b *[]byte, a netip.Addr) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteAddr16(a)
+	})
+}
+
+// WriteAddrPort appends the address of ap in its natural size (see WriteAddr) then the port at the end of b.
+// Returns an *EncodeError if the address is the zero Addr or has a zone.
+func WriteAddrPort(b *[]byte, ap netip.AddrPort) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteAddrPort(ap)
+	})
+}
+
+// WritePrefix appends p at the end of b as the prefix length in a byte and the significant bytes of the masked address.
+// Returns an *EncodeError if p is invalid.
+func WritePrefix(b *[]byte, p netip.Prefix) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WritePrefix(p)
+	})
+}
+
+// WriteHardwareAddr appends a at the end of b.
+// Returns an *EncodeError if a is not 6 or 8 bytes long.
+func WriteHardwareAddr(b *[]byte, a net.HardwareAddr) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteHardwareAddr(a)
+	})
+}
+
+// ReadAddr4 reads an IPv4 address (4 bytes) from b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadAddr4() (netip.Addr, bool) {
+
+	v, err := b.ReadAddr4E()
+
+	return v, err == nil
+}
+
+// ReadAddr4E is like ReadAddr4, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadAddr4E() (netip.Addr, error) {
+
+	v, err := b.readE(4, "ReadAddr4")
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return netip.AddrFrom4([4]byte{v[0], v[1], v[2], v[3]}), nil
+}
+
+// ReadAddr16 reads an IPv6 address (16 bytes) from b.
+// An IPv4-mapped address is returned as it is, use Unmap to convert it to IPv4.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadAddr16() (netip.Addr, bool) {
+
+	v, err := b.ReadAddr16E()
+
+	return v, err == nil
+}
+
+// ReadAddr16E is like ReadAddr16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadAddr16E() (netip.Addr, error) {
+
+	v, err := b.readE(16, "ReadAddr16")
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	var a [16]byte
+	copy(a[:], v)
+
+	return netip.AddrFrom16(a), nil
+}
+
+// ReadAddrPort4 reads an IPv4 address and a port (6 bytes) from b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadAddrPort4() (netip.AddrPort, bool) {
+
+	v, err := b.ReadAddrPort4E()
+
+	return v, err == nil
+}
+
+// ReadAddrPort4E is like ReadAddrPort4, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadAddrPort4E() (netip.AddrPort, error) {
+
+	v, err := b.readE(6, "ReadAddrPort4")
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+
+	a := netip.AddrFrom4([4]byte{v[0], v[1], v[2], v[3]})
+
+	return netip.AddrPortFrom(a, BigEndian.Uint16(v[4:])), nil
+}
+
+// ReadAddrPort16 reads an IPv6 address and a port (18 bytes) from b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadAddrPort16() (netip.AddrPort, bool) {
+
+	v, err := b.ReadAddrPort16E()
+
+	return v, err == nil
+}
+
+// ReadAddrPort16E is like ReadAddrPort16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadAddrPort16E() (netip.AddrPort, error) {
+
+	v, err := b.readE(18, "ReadAddrPort16")
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+
+	var a [16]byte
+	copy(a[:], v)
+
+	return netip.AddrPortFrom(netip.AddrFrom16(a), BigEndian.Uint16(v[16:])), nil
+}
+
+// readPrefix reads a prefix of an address with size bytes.
+// If the read failed, the read cursor is not advanced.
+func (b *Buffer) readPrefix(size int, op string) (netip.Prefix, error) {
+
+	off := b.off
+
+	bits, err := b.readE(1, op)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	n := int(bits[0])
+	if n > size*8 {
+		b.off = off
+		return netip.Prefix{}, &DecodeError{Op: op, Offset: b.base + off, Err: ErrInvalidAddr}
+	}
+
+	v, err := b.readE((n+7)/8, op)
+	if err != nil {
+		b.off = off
+		return netip.Prefix{}, err
+	}
+
+	var a [16]byte
+	copy(a[:], v)
+
+	addr := netip.AddrFrom16(a)
+	if size == 4 {
+		addr = netip.AddrFrom4([4]byte{a[0], a[1], a[2], a[3]})
+	}
+
+	return netip.PrefixFrom(addr, n).Masked(), nil
+}
+
+// ReadPrefix4 reads an IPv4 prefix encoded as a length byte and the significant bytes of the address (eg.: BGP NLRI).
+// The bits after the prefix length are cleared.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadPrefix4() (netip.Prefix, bool) {
+
+	v, err := b.ReadPrefix4E()
+
+	return v, err == nil
+}
+
+// ReadPrefix4E is like ReadPrefix4, but returns a *DecodeError if the read failed.
+// A prefix length longer than 32 is reported with ErrInvalidAddr.
+func (b *Buffer) ReadPrefix4E() (netip.Prefix, error) {
+	return b.readPrefix(4, "ReadPrefix4")
+}
+
+// ReadPrefix16 reads an IPv6 prefix encoded as a length byte and the significant bytes of the address (eg.: BGP NLRI).
+// The bits after the prefix length are cleared.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadPrefix16() (netip.Prefix, bool) {
+
+	v, err := b.ReadPrefix16E()
+
+	return v, err == nil
+}
+
+// ReadPrefix16E is like ReadPrefix16, but returns a *DecodeError if the read failed.
+// A prefix length longer than 128 is reported with ErrInvalidAddr.
+func (b *Buffer) ReadPrefix16E() (netip.Prefix, error) {
+	return b.readPrefix(16, "ReadPrefix16")
+}
+
+// ReadHardwareAddr reads a MAC address of n bytes from b and returns a copy of it.
+// n must be 6 (EUI-48) or 8 (EUI-64).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadHardwareAddr(n int) (net.HardwareAddr, bool) {
+
+	v, err := b.ReadHardwareAddrE(n)
+
+	return v, err == nil
+}
+
+// ReadHardwareAddrE is like ReadHardwareAddr, but returns a *DecodeError if the read failed.
+// If n is not 6 or 8, the error wraps ErrInvalidLength.
+func (b *Buffer) ReadHardwareAddrE(n int) (net.HardwareAddr, error) {
+
+	if n != 6 && n != 8 {
+		return nil, &DecodeError{Op: "ReadHardwareAddr", Offset: b.base + b.off, Want: n, Have: b.Remaining(), Err: ErrInvalidLength}
+	}
+
+	v, err := b.readE(n, "ReadHardwareAddr")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(net.HardwareAddr{}, v...), nil
+}
+
+// WriteAddr appends a at the end of b in its natural size: 4 bytes for IPv4, 16 bytes for IPv6 (including IPv4-mapped).
+// Returns an *EncodeError if a is the zero Addr or has a zone.
+func (b *Buffer) WriteAddr(a netip.Addr) error {
+
+	if !a.IsValid() || a.Zone() != "" {
+		return &EncodeError{Op: "WriteAddr", Err: ErrInvalidAddr}
+	}
+
+	b.b = append(b.b, a.AsSlice()...)
+
+	return nil
+}
+
+// WriteAddr4 appends a at the end of b as 4 bytes. An IPv4-mapped IPv6 address is unmapped.
+// Returns an *EncodeError if a is not an IPv4 or IPv4-mapped address.
+func (b *Buffer) WriteAddr4(a netip.Addr) error {
+
+	if a = a.Unmap(); !a.Is4() {
+		return &EncodeError{Op: "WriteAddr4", Err: ErrInvalidAddr}
+	}
+
+	v := a.As4()
+	b.b = append(b.b, v[:]...)
+
+	return nil
+}
+
+// WriteAddr16 appends a at the end of b as 16 bytes. An IPv4 address is written as IPv4-mapped (::ffff:a.b.c.d).
+// Returns an *EncodeError if a is the zero Addr or has a zone.
+func (b *Buffer) WriteAddr16(a netip.Addr) error {
+
+	if !a.IsValid() || a.Zone() != "" {
+		return &EncodeError{Op: "WriteAddr16", Err: ErrInvalidAddr}
+	}
+
+	v := a.As16()
+	b.b = append(b.b, v[:]...)
+
+	return nil
+}
+
+// WriteAddrPort appends the address of ap in its natural size (see WriteAddr) then the port at the end of b.
+// Returns an *EncodeError if the address is the zero Addr or has a zone.
+func (b *Buffer) WriteAddrPort(ap netip.AddrPort) error {
+
+	if a := ap.Addr(); !a.IsValid() || a.Zone() != "" {
+		return &EncodeError{Op: "WriteAddrPort", Err: ErrInvalidAddr}
+	}
+
+	b.b = append(b.b, ap.Addr().AsSlice()...)
+	b.b = BigEndian.AppendUint16(b.b, ap.Port())
+
+	return nil
+}
+
+// WritePrefix appends p at the end of b as the prefix length in a byte and the significant bytes of the masked address
+// (eg.: BGP NLRI). An IPv4-mapped prefix is written with the 16 bytes address.
+// Returns an *EncodeError if p is invalid.
+func (b *Buffer) WritePrefix(p netip.Prefix) error {
+
+	if !p.IsValid() {
+		return &EncodeError{Op: "WritePrefix", Err: ErrInvalidAddr}
+	}
+
+	p = p.Masked()
+	v := p.Addr().AsSlice()
+
+	b.b = append(b.b, uint8(p.Bits()))
+	b.b = append(b.b, v[:(p.Bits()+7)/8]...)
+
+	return nil
+}
+
+// WriteHardwareAddr appends a at the end of b.
+// Returns an *EncodeError if a is not 6 or 8 bytes long.
+func (b *Buffer) WriteHardwareAddr(a net.HardwareAddr) error {
+
+	if len(a) != 6 && len(a) != 8 {
+		return &EncodeError{Op: "WriteHardwareAddr", Length: len(a), Err: ErrInvalidAddr}
+	}
+
+	b.b = append(b.b, a...)
+
+	return nil
+}
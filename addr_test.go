@@ -0,0 +1,324 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestAddrRoundTrip(t *testing.T) {
+
+	v4 := netip.MustParseAddr("192.0.2.1")
+	v6 := netip.MustParseAddr("2001:db8::1")
+	mapped := netip.MustParseAddr("::ffff:192.0.2.1")
+
+	// Addresses are always in network byte order.
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		b := NewBufferWithEndianness(nil, e)
+
+		for _, err := range []error{
+			b.WriteAddr(v4),
+			b.WriteAddr(v6),
+			b.WriteAddr(mapped),
+			b.WriteAddr4(mapped),
+			b.WriteAddr16(v4),
+			b.WriteAddrPort(netip.AddrPortFrom(v4, 443)),
+			b.WriteAddrPort(netip.AddrPortFrom(v6, 53)),
+			b.WriteHardwareAddr(net.HardwareAddr{0, 1, 2, 3, 4, 5}),
+		} {
+			if err != nil {
+				t.Fatalf("%v: write: %s", e, err)
+			}
+		}
+
+		want := []byte{192, 0, 2, 1}
+		if !bytes.Equal(b.Bytes()[:4], want) || !bytes.Equal(b.Bytes()[len(b.Bytes())-8:len(b.Bytes())-6], []byte{0, 53}) {
+			t.Fatalf("%v: wrote % x", e, b.Bytes())
+		}
+
+		if v, ok := b.ReadAddr4(); !ok || v != v4 {
+			t.Fatalf("%v: ReadAddr4 = %s, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadAddr16(); !ok || v != v6 {
+			t.Fatalf("%v: ReadAddr16 = %s, %v", e, v, ok)
+		}
+
+		// An IPv4-mapped address is kept as it is.
+		if v, ok := b.ReadAddr16(); !ok || v != mapped {
+			t.Fatalf("%v: ReadAddr16 = %s, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadAddr4(); !ok || v != v4 {
+			t.Fatalf("%v: ReadAddr4 = %s, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadAddr16(); !ok || v != mapped {
+			t.Fatalf("%v: ReadAddr16 = %s, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadAddrPort4(); !ok || v != netip.AddrPortFrom(v4, 443) {
+			t.Fatalf("%v: ReadAddrPort4 = %s, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadAddrPort16(); !ok || v != netip.AddrPortFrom(v6, 53) {
+			t.Fatalf("%v: ReadAddrPort16 = %s, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadHardwareAddr(6); !ok || v.String() != "00:01:02:03:04:05" {
+			t.Fatalf("%v: ReadHardwareAddr = %s, %v", e, v, ok)
+		}
+
+		if !b.Empty() {
+			t.Fatalf("%v: %d bytes left", e, b.Remaining())
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+
+	tests := []struct {
+		prefix string
+		data   []byte
+	}{
+		{"0.0.0.0/0", []byte{0}},
+		{"10.0.0.0/7", []byte{7, 10}},
+		{"192.0.2.0/24", []byte{24, 192, 0, 2}},
+		{"192.0.2.128/25", []byte{25, 192, 0, 2, 128}},
+		{"192.0.2.1/32", []byte{32, 192, 0, 2, 1}},
+		{"::/0", []byte{0}},
+		{"2001:db8::/32", []byte{32, 0x20, 0x01, 0x0d, 0xb8}},
+		{"2001:db8::/33", []byte{33, 0x20, 0x01, 0x0d, 0xb8, 0x00}},
+		{"2001:db8::1/128", []byte{128, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+		{"::ffff:10.0.0.0/104", []byte{104, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 10}},
+	}
+
+	for _, tt := range tests {
+
+		p := netip.MustParsePrefix(tt.prefix)
+
+		b := NewEmpty()
+
+		if err := b.WritePrefix(p); err != nil || !bytes.Equal(b.Bytes(), tt.data) {
+			t.Fatalf("%s: WritePrefix = % x, %v, want % x", tt.prefix, b.Bytes(), err, tt.data)
+		}
+
+		read := (*Buffer).ReadPrefix16E
+		if p.Addr().Is4() {
+			read = (*Buffer).ReadPrefix4E
+		}
+
+		if v, err := read(&b); err != nil || v != p {
+			t.Fatalf("%s: read = %s, %v", tt.prefix, v, err)
+		}
+	}
+
+	// The bits after the prefix length are cleared, on both write and read.
+	b := NewEmpty()
+
+	if err := b.WritePrefix(netip.MustParsePrefix("192.0.2.255/20")); err != nil || !bytes.Equal(b.Bytes(), []byte{20, 192, 0, 0}) {
+		t.Fatalf("WritePrefix = % x, %v", b.Bytes(), err)
+	}
+
+	b = NewBuffer([]byte{20, 192, 0, 0xff})
+
+	if v, ok := b.ReadPrefix4(); !ok || v != netip.MustParsePrefix("192.0.240.0/20") {
+		t.Fatalf("ReadPrefix4 = %s, %v", v, ok)
+	}
+}
+
+func TestReadAddrErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		read   func(b *Buffer) error
+		op     string
+		offset int
+		err    error
+	}{
+		{"Addr4/short", []byte{192, 0, 2}, readAddr((*Buffer).ReadAddr4E), "ReadAddr4", 1, ErrShortBuffer},
+		{"Addr16/short", make([]byte, 15), readAddr((*Buffer).ReadAddr16E), "ReadAddr16", 1, ErrShortBuffer},
+		{"AddrPort4/short", make([]byte, 5), readAddrPort((*Buffer).ReadAddrPort4E), "ReadAddrPort4", 1, ErrShortBuffer},
+		{"AddrPort16/short", make([]byte, 17), readAddrPort((*Buffer).ReadAddrPort16E), "ReadAddrPort16", 1, ErrShortBuffer},
+		{"Prefix4/empty", nil, readPrefix((*Buffer).ReadPrefix4E), "ReadPrefix4", 1, ErrShortBuffer},
+		{"Prefix4/33 bits", []byte{33, 192, 0, 2, 1, 0}, readPrefix((*Buffer).ReadPrefix4E), "ReadPrefix4", 1, ErrInvalidAddr},
+		{"Prefix4/255 bits", []byte{255}, readPrefix((*Buffer).ReadPrefix4E), "ReadPrefix4", 1, ErrInvalidAddr},
+		{"Prefix4/short", []byte{24, 192, 0}, readPrefix((*Buffer).ReadPrefix4E), "ReadPrefix4", 2, ErrShortBuffer},
+		{"Prefix16/129 bits", append([]byte{129}, make([]byte, 17)...), readPrefix((*Buffer).ReadPrefix16E), "ReadPrefix16", 1, ErrInvalidAddr},
+		{"Prefix16/short", []byte{64, 0x20, 0x01}, readPrefix((*Buffer).ReadPrefix16E), "ReadPrefix16", 2, ErrShortBuffer},
+		{"HardwareAddr/length", make([]byte, 7), readHardwareAddr(7), "ReadHardwareAddr", 1, ErrInvalidLength},
+		{"HardwareAddr/short", make([]byte, 7), readHardwareAddr(8), "ReadHardwareAddr", 1, ErrShortBuffer},
+	}
+
+	for _, tt := range tests {
+
+		// The address is read from a child, so the offsets include its base.
+		p := NewBuffer(append([]byte{byte(len(tt.data))}, tt.data...))
+
+		var b Buffer
+
+		if err := p.ReadLengthPrefixed(8, &b); err != nil {
+			t.Fatalf("%s: ReadLengthPrefixed: %s", tt.name, err)
+		}
+
+		err := tt.read(&b)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+
+		if b.Offset() != 0 {
+			t.Fatalf("%s: read cursor advanced to %d", tt.name, b.Offset())
+		}
+	}
+}
+
+func readAddr(read func(b *Buffer) (netip.Addr, error)) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := read(b)
+		return err
+	}
+}
+
+func readAddrPort(read func(b *Buffer) (netip.AddrPort, error)) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := read(b)
+		return err
+	}
+}
+
+func readPrefix(read func(b *Buffer) (netip.Prefix, error)) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := read(b)
+		return err
+	}
+}
+
+func readHardwareAddr(n int) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := b.ReadHardwareAddrE(n)
+		return err
+	}
+}
+
+func TestWriteAddrErrors(t *testing.T) {
+
+	zoned := netip.MustParseAddr("fe80::1%eth0")
+	v6 := netip.MustParseAddr("2001:db8::1")
+
+	tests := []struct {
+		name  string
+		write func(b *Buffer) error
+		op    string
+	}{
+		{"Addr/zero", func(b *Buffer) error { return b.WriteAddr(netip.Addr{}) }, "WriteAddr"},
+		{"Addr/zone", func(b *Buffer) error { return b.WriteAddr(zoned) }, "WriteAddr"},
+		{"Addr4/zero", func(b *Buffer) error { return b.WriteAddr4(netip.Addr{}) }, "WriteAddr4"},
+		{"Addr4/IPv6", func(b *Buffer) error { return b.WriteAddr4(v6) }, "WriteAddr4"},
+		{"Addr16/zero", func(b *Buffer) error { return b.WriteAddr16(netip.Addr{}) }, "WriteAddr16"},
+		{"Addr16/zone", func(b *Buffer) error { return b.WriteAddr16(zoned) }, "WriteAddr16"},
+		{"AddrPort/zero", func(b *Buffer) error { return b.WriteAddrPort(netip.AddrPort{}) }, "WriteAddrPort"},
+		{"AddrPort/zone", func(b *Buffer) error { return b.WriteAddrPort(netip.AddrPortFrom(zoned, 80)) }, "WriteAddrPort"},
+		{"Prefix/zero", func(b *Buffer) error { return b.WritePrefix(netip.Prefix{}) }, "WritePrefix"},
+		{"Prefix/33 bits", func(b *Buffer) error { return b.WritePrefix(netip.PrefixFrom(netip.MustParseAddr("192.0.2.1"), 33)) }, "WritePrefix"},
+		{"HardwareAddr/length", func(b *Buffer) error { return b.WriteHardwareAddr(net.HardwareAddr{1, 2, 3, 4, 5}) }, "WriteHardwareAddr"},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBuffer([]byte{0x01})
+
+		err := tt.write(&b)
+
+		var ee *EncodeError
+
+		if !errors.As(err, &ee) || ee.Op != tt.op || !errors.Is(err, ErrInvalidAddr) {
+			t.Fatalf("%s: error = %v, want %s: %v", tt.name, err, tt.op, ErrInvalidAddr)
+		}
+
+		if b.Size() != 1 {
+			t.Fatalf("%s: %d bytes written by a failed write", tt.name, b.Size()-1)
+		}
+	}
+}
+
+func TestAddrSlice(t *testing.T) {
+
+	var b []byte
+
+	ap := netip.MustParseAddrPort("[2001:db8::1]:8080")
+
+	for _, err := range []error{
+		WriteAddr(&b, netip.MustParseAddr("192.0.2.1")),
+		WriteAddr4(&b, netip.MustParseAddr("192.0.2.2")),
+		WriteAddr16(&b, netip.MustParseAddr("2001:db8::2")),
+		WriteAddrPort(&b, netip.MustParseAddrPort("192.0.2.3:80")),
+		WriteAddrPort(&b, ap),
+		WritePrefix(&b, netip.MustParsePrefix("10.0.0.0/8")),
+		WritePrefix(&b, netip.MustParsePrefix("2001:db8::/32")),
+		WriteHardwareAddr(&b, net.HardwareAddr{0, 1, 2, 3, 4, 5, 6, 7}),
+	} {
+		if err != nil {
+			t.Fatalf("write: %s", err)
+		}
+	}
+
+	if err := WriteAddr(&b, netip.MustParseAddr("fe80::1%eth0")); !errors.Is(err, ErrInvalidAddr) {
+		t.Fatalf("WriteAddr with zone = %v, want ErrInvalidAddr", err)
+	}
+
+	if v, ok := ReadAddr4(&b); !ok || v.String() != "192.0.2.1" {
+		t.Fatalf("ReadAddr4 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadAddr4(&b); !ok || v.String() != "192.0.2.2" {
+		t.Fatalf("ReadAddr4 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadAddr16(&b); !ok || v.String() != "2001:db8::2" {
+		t.Fatalf("ReadAddr16 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadAddrPort4(&b); !ok || v.String() != "192.0.2.3:80" {
+		t.Fatalf("ReadAddrPort4 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadAddrPort16(&b); !ok || v != ap {
+		t.Fatalf("ReadAddrPort16 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadPrefix4(&b); !ok || v.String() != "10.0.0.0/8" {
+		t.Fatalf("ReadPrefix4 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadPrefix16(&b); !ok || v.String() != "2001:db8::/32" {
+		t.Fatalf("ReadPrefix16 = %s, %v", v, ok)
+	}
+
+	if v, ok := ReadHardwareAddr(&b, 8); !ok || v.String() != "00:01:02:03:04:05:06:07" {
+		t.Fatalf("ReadHardwareAddr = %s, %v", v, ok)
+	}
+
+	if len(b) != 0 {
+		t.Fatalf("%d bytes left", len(b))
+	}
+
+	// The failed reads do not modify b and report offset -1.
+	b = []byte{33, 192, 0, 2, 1, 0}
+
+	var de *DecodeError
+
+	if _, err := ReadPrefix4E(&b); !errors.As(err, &de) || de.Offset != -1 || !errors.Is(err, ErrInvalidAddr) {
+		t.Fatalf("ReadPrefix4E = %v, want ErrInvalidAddr at offset -1", err)
+	}
+
+	if len(b) != 6 {
+		t.Fatalf("b modified to % x", b)
+	}
+}
@@ -0,0 +1,201 @@
+package bytebuilder
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrASN1Syntax is returned when an ASN.1 element is malformed.
+	ErrASN1Syntax = errors.New("malformed ASN.1 element")
+
+	// ErrASN1Tag is returned when an ASN.1 element has an unexpected tag.
+	ErrASN1Tag = errors.New("unexpected ASN.1 tag")
+
+	// ErrASN1NotDER is returned in strict DER mode when an ASN.1 element is valid BER, but not DER.
+	ErrASN1NotDER = errors.New("ASN.1 element is not DER")
+)
+
+// maxASN1Depth is the maximum nesting depth of indefinite length and constructed string elements.
+const maxASN1Depth = 64
+
+// ASN1Tag is an ASN.1 identifier: the class, the constructed bit and the tag number.
+//
+// The class and the constructed bit are stored in the top byte in the same position as in the identifier octet,
+// the tag number is stored in the low 24 bits, so tag numbers up to 1<<24-1 are supported (high-tag-number form).
+type ASN1Tag uint32
+
+// Classes of ASN1Tag
+const (
+	ASN1ClassUniversal       ASN1Tag = 0x00 << 24
+	ASN1ClassApplication     ASN1Tag = 0x40 << 24
+	ASN1ClassContextSpecific ASN1Tag = 0x80 << 24
+	ASN1ClassPrivate         ASN1Tag = 0xc0 << 24
+)
+
+const (
+	asn1ClassMask      ASN1Tag = 0xc0 << 24
+	asn1ConstructedBit ASN1Tag = 0x20 << 24
+	asn1NumberMask     ASN1Tag = 1<<24 - 1
+)
+
+// Universal tags
+const (
+	ASN1Boolean          ASN1Tag = 1
+	ASN1Integer          ASN1Tag = 2
+	ASN1BitString        ASN1Tag = 3
+	ASN1OctetString      ASN1Tag = 4
+	ASN1Null             ASN1Tag = 5
+	ASN1ObjectIdentifier ASN1Tag = 6
+	ASN1Enumerated       ASN1Tag = 10
+	ASN1UTF8String       ASN1Tag = 12
+	ASN1Sequence         ASN1Tag = 16 | asn1ConstructedBit
+	ASN1Set              ASN1Tag = 17 | asn1ConstructedBit
+	ASN1PrintableString  ASN1Tag = 19
+	ASN1T61String        ASN1Tag = 20
+	ASN1IA5String        ASN1Tag = 22
+	ASN1UTCTime          ASN1Tag = 23
+	ASN1GeneralizedTime  ASN1Tag = 24
+	ASN1BMPString        ASN1Tag = 30
+)
+
+// Constructed returns t with the constructed bit set.
+func (t ASN1Tag) Constructed() ASN1Tag {
+	return t | asn1ConstructedBit
+}
+
+// ContextSpecific returns t with the context-specific class.
+// Eg.: ASN1Tag(0).ContextSpecific().Constructed() is the tag of an explicit [0] field.
+func (t ASN1Tag) ContextSpecific() ASN1Tag {
+	return t&^asn1ClassMask | ASN1ClassContextSpecific
+}
+
+// Class returns the class of t (eg.: ASN1ClassUniversal).
+func (t ASN1Tag) Class() ASN1Tag {
+	return t & asn1ClassMask
+}
+
+// IsConstructed returns whether the constructed bit of t is set.
+func (t ASN1Tag) IsConstructed() bool {
+	return t&asn1ConstructedBit != 0
+}
+
+// Number returns the tag number of t.
+func (t ASN1Tag) Number() uint32 {
+	return uint32(t & asn1NumberMask)
+}
+
+// String returns the class, the number and the constructed bit of t (eg.: "universal 16 constructed").
+func (t ASN1Tag) String() string {
+
+	var s string
+
+	switch t.Class() {
+	case ASN1ClassUniversal:
+		s = "universal"
+	case ASN1ClassApplication:
+		s = "application"
+	case ASN1ClassContextSpecific:
+		s = "context-specific"
+	default:
+		s = "private"
+	}
+
+	s += " " + strconv.FormatUint(uint64(t.Number()), 10)
+
+	if t.IsConstructed() {
+		s += " constructed"
+	}
+
+	return s
+}
+
+// StrictDER returns whether the ASN.1 parser of b accepts only DER.
+func (b *Buffer) StrictDER() bool {
+	return b.der
+}
+
+// SetStrictDER sets whether the ASN.1 parser of b accepts only DER.
+//
+// By default the parser accepts BER: indefinite lengths, constructed OCTET STRINGs,
+// non-minimal lengths, integers and tag numbers and every time format.
+// In strict mode these return an error wrapping ErrASN1NotDER.
+// The mode is inherited by the child Buffers (eg.: ReadASN1, ReadLengthPrefixed).
+func (b *Buffer) SetStrictDER(strict bool) {
+	b.der = strict
+}
+
+// appendASN1Tag appends the identifier octets of t to dst.
+func appendASN1Tag(dst []byte, t ASN1Tag) []byte {
+
+	first := byte(t>>24) & 0xe0
+	n := t.Number()
+
+	if n < 31 {
+		return append(dst, first|byte(n))
+	}
+
+	return appendBase128(append(dst, first|0x1f), uint64(n))
+}
+
+// appendASN1Length appends the minimal definite length octets of n to dst.
+func appendASN1Length(dst []byte, n int) []byte {
+
+	if n < 128 {
+		return append(dst, byte(n))
+	}
+
+	size := 0
+	for v := n; v > 0; v >>= 8 {
+		size++
+	}
+
+	dst = append(dst, 0x80|byte(size))
+
+	for ; size > 0; size-- {
+		dst = append(dst, byte(n>>(8*(size-1))))
+	}
+
+	return dst
+}
+
+// appendBase128 appends v in base 128 with the continuation bit set on every byte but the last.
+func appendBase128(dst []byte, v uint64) []byte {
+
+	size := 1
+	for i := v; i >= 0x80; i >>= 7 {
+		size++
+	}
+
+	for ; size > 1; size-- {
+		dst = append(dst, byte(v>>(7*(size-1)))|0x80)
+	}
+
+	return append(dst, byte(v&0x7f))
+}
+
+// parseBase128 parses a base 128 value at the start of v and returns it with the rest of v.
+func parseBase128(v []byte) (int, []byte, error) {
+
+	// Leading zero
+	if len(v) > 0 && v[0] == 0x80 {
+		return 0, nil, ErrASN1Syntax
+	}
+
+	n := 0
+
+	for i := range v {
+
+		if n > maxInt>>7 {
+			return 0, nil, ErrValueOverflow
+		}
+
+		n = n<<7 | int(v[i]&0x7f)
+
+		if v[i]&0x80 == 0 {
+			return n, v[i+1:], nil
+		}
+	}
+
+	return 0, nil, ErrASN1Syntax
+}
@@ -0,0 +1,219 @@
+package bytebuilder
+
+import (
+	"encoding/asn1"
+	"math/big"
+	"time"
+)
+
+// AddASN1 appends an ASN.1 element with tag whose content is written by f.
+// The length is computed after f returned and encoded in the minimal definite form, as required by DER.
+// Elements can be nested by calling AddASN1 on the child.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddASN1(tag ASN1Tag, f BuilderContinuation) error {
+
+	start := len(b.b)
+
+	b.b = appendASN1Tag(b.b, tag)
+	hdr := len(b.b)
+
+	// The child shares the free capacity and the settings of b, like in AddLengthPrefixed.
+	child := Buffer{b: b.b[hdr:], little: b.little, alloc: b.alloc, der: b.der, utf8: b.utf8, strictVarint: b.strictVarint}
+
+	if err := f(&child); err != nil {
+		b.b = b.b[:start]
+		return err
+	}
+
+	b.b = append(b.b[:hdr], child.b...)
+	b.insert(hdr, appendASN1Length(nil, len(child.b)))
+
+	return nil
+}
+
+// AddASN1Bytes appends an ASN.1 element with tag and content v.
+// It can be used for the string types (eg.: ASN1UTF8String) and for implicitly tagged primitive values.
+func (b *Buffer) AddASN1Bytes(tag ASN1Tag, v []byte) {
+
+	b.b = appendASN1Tag(b.b, tag)
+	b.b = appendASN1Length(b.b, len(v))
+	b.b = append(b.b, v...)
+}
+
+// AddASN1Boolean appends an ASN.1 BOOLEAN. True is encoded as 0xff, as required by DER.
+func (b *Buffer) AddASN1Boolean(v bool) {
+
+	if v {
+		b.AddASN1Bytes(ASN1Boolean, []byte{0xff})
+	} else {
+		b.AddASN1Bytes(ASN1Boolean, []byte{0x00})
+	}
+}
+
+// AddASN1Int64 appends an ASN.1 INTEGER in the minimal two's complement form.
+func (b *Buffer) AddASN1Int64(v int64) {
+	b.AddASN1Bytes(ASN1Integer, appendASN1Int64(nil, v))
+}
+
+// AddASN1Uint64 appends an ASN.1 INTEGER in the minimal two's complement form.
+func (b *Buffer) AddASN1Uint64(v uint64) {
+	b.AddASN1Bytes(ASN1Integer, appendASN1Uint64(nil, v))
+}
+
+// AddASN1Enumerated appends an ASN.1 ENUMERATED.
+func (b *Buffer) AddASN1Enumerated(v int64) {
+	b.AddASN1Bytes(ASN1Enumerated, appendASN1Int64(nil, v))
+}
+
+// AddASN1BigInt appends an ASN.1 INTEGER in the minimal two's complement form. v must not be nil.
+func (b *Buffer) AddASN1BigInt(v *big.Int) {
+
+	var c []byte
+
+	if v.Sign() >= 0 {
+
+		c = v.Bytes()
+
+		if len(c) == 0 || c[0]&0x80 != 0 {
+			c = append([]byte{0x00}, c...)
+		}
+
+	} else {
+
+		// Two's complement of -v: invert the bytes of -v-1.
+		n := new(big.Int).Neg(v)
+		n.Sub(n, big.NewInt(1))
+		c = n.Bytes()
+
+		for i := range c {
+			c[i] = ^c[i]
+		}
+
+		if len(c) == 0 || c[0]&0x80 == 0 {
+			c = append([]byte{0xff}, c...)
+		}
+	}
+
+	b.AddASN1Bytes(ASN1Integer, c)
+}
+
+// AddASN1OctetString appends an ASN.1 OCTET STRING.
+func (b *Buffer) AddASN1OctetString(v []byte) {
+	b.AddASN1Bytes(ASN1OctetString, v)
+}
+
+// AddASN1Null appends an ASN.1 NULL.
+func (b *Buffer) AddASN1Null() {
+	b.AddASN1Bytes(ASN1Null, nil)
+}
+
+// AddASN1BitString appends an ASN.1 BIT STRING.
+// The unused bits of the last byte are written as zero, as required by DER.
+// If BitLength does not match the length of Bytes, returns an *EncodeError wrapping ErrInvalidLength.
+func (b *Buffer) AddASN1BitString(v asn1.BitString) error {
+
+	if v.BitLength < 0 || (v.BitLength+7)/8 != len(v.Bytes) {
+		return &EncodeError{Op: "AddASN1BitString", Length: v.BitLength, Err: ErrInvalidLength}
+	}
+
+	pad := len(v.Bytes)*8 - v.BitLength
+
+	c := make([]byte, 1+len(v.Bytes))
+	c[0] = byte(pad)
+	copy(c[1:], v.Bytes)
+
+	if pad > 0 {
+		c[len(c)-1] &= 0xff << pad
+	}
+
+	b.AddASN1Bytes(ASN1BitString, c)
+
+	return nil
+}
+
+// AddASN1ObjectIdentifier appends an ASN.1 OBJECT IDENTIFIER.
+// If oid is not valid (less than two arcs, negative arcs or the first two arcs out of range),
+// returns an *EncodeError wrapping ErrASN1Syntax.
+func (b *Buffer) AddASN1ObjectIdentifier(oid asn1.ObjectIdentifier) error {
+
+	if len(oid) < 2 || oid[0] < 0 || oid[0] > 2 || oid[1] < 0 || (oid[0] < 2 && oid[1] >= 40) {
+		return &EncodeError{Op: "AddASN1ObjectIdentifier", Err: ErrASN1Syntax}
+	}
+
+	c := appendBase128(nil, uint64(oid[0])*40+uint64(oid[1]))
+
+	for _, v := range oid[2:] {
+
+		if v < 0 {
+			return &EncodeError{Op: "AddASN1ObjectIdentifier", Err: ErrASN1Syntax}
+		}
+
+		c = appendBase128(c, uint64(v))
+	}
+
+	b.AddASN1Bytes(ASN1ObjectIdentifier, c)
+
+	return nil
+}
+
+// AddASN1UTCTime appends t as an ASN.1 UTCTime in the DER form "YYMMDDhhmmssZ".
+// If the year of t is not between 1950 and 2049, returns an *EncodeError wrapping ErrValueOverflow.
+func (b *Buffer) AddASN1UTCTime(t time.Time) error {
+
+	t = t.UTC()
+
+	if t.Year() < 1950 || t.Year() >= 2050 {
+		return &EncodeError{Op: "AddASN1UTCTime", Err: ErrValueOverflow}
+	}
+
+	b.AddASN1Bytes(ASN1UTCTime, []byte(t.Format(utcTimeDER)))
+
+	return nil
+}
+
+// AddASN1GeneralizedTime appends t as an ASN.1 GeneralizedTime in the DER form "YYYYMMDDhhmmss[.f]Z".
+// The fractional seconds are written without trailing zeros.
+// If the year of t is not between 0 and 9999, returns an *EncodeError wrapping ErrValueOverflow.
+func (b *Buffer) AddASN1GeneralizedTime(t time.Time) error {
+
+	t = t.UTC()
+
+	if t.Year() < 0 || t.Year() > 9999 {
+		return &EncodeError{Op: "AddASN1GeneralizedTime", Err: ErrValueOverflow}
+	}
+
+	b.AddASN1Bytes(ASN1GeneralizedTime, []byte(t.Format(generalizedTimeDER)))
+
+	return nil
+}
+
+// appendASN1Int64 appends the minimal two's complement form of v to dst.
+func appendASN1Int64(dst []byte, v int64) []byte {
+
+	size := 1
+	for i := v; i > 127 || i < -128; i >>= 8 {
+		size++
+	}
+
+	for ; size > 0; size-- {
+		dst = append(dst, byte(v>>(8*(size-1))))
+	}
+
+	return dst
+}
+
+// appendASN1Uint64 appends the minimal two's complement form of v to dst.
+func appendASN1Uint64(dst []byte, v uint64) []byte {
+
+	size := 1
+	for i := v; i > 127; i >>= 8 {
+		size++
+	}
+
+	for ; size > 0; size-- {
+		// Shifting an uint64 by 64 gives 0, the leading zero byte of large values.
+		dst = append(dst, byte(v>>(8*(size-1))))
+	}
+
+	return dst
+}
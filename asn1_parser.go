@@ -0,0 +1,704 @@
+package bytebuilder
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Time layouts of the ASN.1 time types
+const (
+	utcTimeDER         = "060102150405Z"
+	generalizedTimeDER = "20060102150405.999999999Z"
+)
+
+// utcTimeBER and generalizedTimeBER are the accepted layouts in BER mode.
+// The seconds of UTCTime are optional, the offset can be "Z" or "+hhmm".
+var (
+	utcTimeBER         = []string{"0601021504Z0700", "060102150405Z0700"}
+	generalizedTimeBER = []string{"20060102150405Z0700"}
+)
+
+// parseASN1Header parses the identifier and length octets at the start of data.
+// Returns the tag, the size of the header and the length of the content (-1 for the indefinite length).
+func parseASN1Header(data []byte, der bool) (ASN1Tag, int, int, error) {
+
+	if len(data) < 2 {
+		return 0, 0, 0, ErrShortBuffer
+	}
+
+	tag := ASN1Tag(data[0]&0xe0) << 24
+	n := ASN1Tag(data[0] & 0x1f)
+	i := 1
+
+	// High-tag-number form
+	if n == 0x1f {
+
+		// Leading zero
+		if data[1] == 0x80 {
+			return 0, 0, 0, ErrASN1Syntax
+		}
+
+		n = 0
+
+		for {
+
+			if i >= len(data) {
+				return 0, 0, 0, ErrShortBuffer
+			}
+
+			if n > asn1NumberMask>>7 {
+				return 0, 0, 0, ErrASN1Syntax
+			}
+
+			n = n<<7 | ASN1Tag(data[i]&0x7f)
+			i++
+
+			if data[i-1]&0x80 == 0 {
+				break
+			}
+		}
+
+		if der && n < 31 {
+			return 0, 0, 0, ErrASN1NotDER
+		}
+	}
+
+	tag |= n
+
+	if i >= len(data) {
+		return 0, 0, 0, ErrShortBuffer
+	}
+
+	c := data[i]
+	i++
+
+	switch {
+	case c < 0x80:
+		return tag, i, int(c), nil
+	case c == 0x80:
+		if !tag.IsConstructed() {
+			return 0, 0, 0, ErrASN1Syntax
+		}
+		if der {
+			return 0, 0, 0, ErrASN1NotDER
+		}
+		return tag, i, -1, nil
+	case c == 0xff:
+		return 0, 0, 0, ErrASN1Syntax
+	}
+
+	size := int(c & 0x7f)
+
+	if len(data)-i < size {
+		return 0, 0, 0, ErrShortBuffer
+	}
+
+	length := 0
+
+	for _, v := range data[i : i+size] {
+
+		if length > maxInt>>8 {
+			return 0, 0, 0, ErrLengthOverflow
+		}
+
+		length = length<<8 | int(v)
+	}
+
+	if der && (data[i] == 0 || length < 128) {
+		return 0, 0, 0, ErrASN1NotDER
+	}
+
+	return tag, i + size, length, nil
+}
+
+// asn1IndefiniteLength returns the length of the content of an indefinite length element starting at data,
+// and the length of the content with the end-of-contents octets.
+func asn1IndefiniteLength(data []byte, depth int) (int, int, error) {
+
+	if depth > maxASN1Depth {
+		return 0, 0, ErrASN1Syntax
+	}
+
+	off := 0
+
+	for {
+
+		// End-of-contents
+		if len(data)-off >= 2 && data[off] == 0 && data[off+1] == 0 {
+			return off, off + 2, nil
+		}
+
+		_, hdr, n, err := parseASN1Header(data[off:], false)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if n < 0 {
+			_, n, err = asn1IndefiniteLength(data[off+hdr:], depth+1)
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+
+		if len(data)-off-hdr < n {
+			return 0, 0, ErrShortBuffer
+		}
+
+		off += hdr + n
+	}
+}
+
+// readASN1 reads the next ASN.1 element from b and returns its tag, the whole element, its content
+// and the offset of the content in b.
+// op is the name of the operation used in the returned *DecodeError.
+// The read cursor is not moved if an error is returned.
+func (b *Buffer) readASN1(op string) (ASN1Tag, []byte, []byte, int, error) {
+
+	data := b.b[b.off:]
+
+	tag, hdr, n, err := parseASN1Header(data, b.der)
+	if err != nil {
+		return 0, nil, nil, 0, &DecodeError{Op: op, Offset: b.base + b.off, Err: err}
+	}
+
+	end := hdr + n
+
+	if n < 0 {
+		n, end, err = asn1IndefiniteLength(data[hdr:], 0)
+		if err != nil {
+			return 0, nil, nil, 0, &DecodeError{Op: op, Offset: b.base + b.off, Err: err}
+		}
+		end += hdr
+	}
+
+	if len(data)-hdr < n {
+		return 0, nil, nil, 0, &DecodeError{Op: op, Offset: b.base + b.off, Want: n, Have: len(data) - hdr, Err: ErrShortBuffer}
+	}
+
+	coff := b.off + hdr
+	b.off += end
+
+	return tag, data[:end:end], data[hdr : hdr+n : hdr+n], coff, nil
+}
+
+// readASN1Tag reads the next ASN.1 element with tag from b and returns the whole element, its content
+// and the offset of the content in b.
+// If the tag does not match, the read cursor is not moved.
+func (b *Buffer) readASN1Tag(tag ASN1Tag, op string) ([]byte, []byte, int, error) {
+
+	off := b.off
+
+	t, elem, content, coff, err := b.readASN1(op)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if t != tag {
+		b.off = off
+		return nil, nil, 0, &DecodeError{Op: op, Offset: b.base + off, Err: fmt.Errorf("%w: have %s, want %s", ErrASN1Tag, t, tag)}
+	}
+
+	return elem, content, coff, nil
+}
+
+// child returns a Buffer over v with the settings of b.
+// off is the offset of v in b, the errors of the child report their offset from the start of b.
+func (b *Buffer) child(v []byte, off int) Buffer {
+	return Buffer{b: v, base: b.base + off, little: b.little, alloc: b.alloc, der: b.der, utf8: b.utf8, strictVarint: b.strictVarint}
+}
+
+// PeekASN1Tag returns the tag of the next ASN.1 element without moving the read cursor.
+// Returns a *DecodeError if the identifier is malformed.
+func (b *Buffer) PeekASN1Tag() (ASN1Tag, error) {
+
+	tag, _, _, err := parseASN1Header(b.b[b.off:], b.der)
+	if err != nil {
+		return 0, &DecodeError{Op: "PeekASN1Tag", Offset: b.base + b.off, Err: err}
+	}
+
+	return tag, nil
+}
+
+// ReadASN1 reads an ASN.1 element with tag and sets child to a Buffer over its content.
+// If the tag does not match, returns a *DecodeError wrapping ErrASN1Tag and the read cursor is not moved.
+func (b *Buffer) ReadASN1(tag ASN1Tag, child *Buffer) error {
+
+	_, content, coff, err := b.readASN1Tag(tag, "ReadASN1")
+	if err != nil {
+		return err
+	}
+
+	*child = b.child(content, coff)
+
+	return nil
+}
+
+// ReadAnyASN1 reads the next ASN.1 element, sets child to a Buffer over its content and returns its tag.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadAnyASN1(child *Buffer) (ASN1Tag, error) {
+
+	tag, _, content, coff, err := b.readASN1("ReadAnyASN1")
+	if err != nil {
+		return 0, err
+	}
+
+	*child = b.child(content, coff)
+
+	return tag, nil
+}
+
+// ReadASN1Element is like ReadASN1, but child includes the identifier and length octets
+// (eg.: to keep the raw bytes of a signed structure).
+func (b *Buffer) ReadASN1Element(tag ASN1Tag, child *Buffer) error {
+
+	elem, _, _, err := b.readASN1Tag(tag, "ReadASN1Element")
+	if err != nil {
+		return err
+	}
+
+	*child = b.child(elem, b.off-len(elem))
+
+	return nil
+}
+
+// ReadAnyASN1Element is like ReadAnyASN1, but child includes the identifier and length octets.
+func (b *Buffer) ReadAnyASN1Element(child *Buffer) (ASN1Tag, error) {
+
+	tag, elem, _, _, err := b.readASN1("ReadAnyASN1Element")
+	if err != nil {
+		return 0, err
+	}
+
+	*child = b.child(elem, b.off-len(elem))
+
+	return tag, nil
+}
+
+// ReadOptionalASN1 reads an ASN.1 element with tag if it is the next element, and sets child to a Buffer over its content.
+// The bool indicates whether the element was present.
+func (b *Buffer) ReadOptionalASN1(tag ASN1Tag, child *Buffer) (bool, error) {
+
+	if b.Empty() {
+		return false, nil
+	}
+
+	t, err := b.PeekASN1Tag()
+	if err != nil || t != tag {
+		return false, err
+	}
+
+	return true, b.ReadASN1(tag, child)
+}
+
+// SkipASN1 skips an ASN.1 element with tag.
+// If the tag does not match, returns a *DecodeError wrapping ErrASN1Tag and the read cursor is not moved.
+func (b *Buffer) SkipASN1(tag ASN1Tag) error {
+
+	_, _, _, err := b.readASN1Tag(tag, "SkipASN1")
+
+	return err
+}
+
+// ReadASN1Bytes reads an ASN.1 element with tag and returns its content.
+// It can be used for the string types (eg.: ASN1UTF8String) and for implicitly tagged primitive values.
+// The returned slice points into b.
+func (b *Buffer) ReadASN1Bytes(tag ASN1Tag) ([]byte, error) {
+
+	_, content, _, err := b.readASN1Tag(tag, "ReadASN1Bytes")
+
+	return content, err
+}
+
+// readASN1Value reads an ASN.1 element with tag and parses its content with parse.
+// If parse fails, the read cursor is not moved.
+func (b *Buffer) readASN1Value(tag ASN1Tag, op string, parse func(v []byte) error) error {
+
+	off := b.off
+
+	_, content, _, err := b.readASN1Tag(tag, op)
+	if err != nil {
+		return err
+	}
+
+	if err := parse(content); err != nil {
+		b.off = off
+		return &DecodeError{Op: op, Offset: b.base + off, Err: err}
+	}
+
+	return nil
+}
+
+// ReadASN1Boolean reads an ASN.1 BOOLEAN.
+// In strict DER mode, true must be encoded as 0xff.
+func (b *Buffer) ReadASN1Boolean() (bool, error) {
+
+	var v bool
+
+	err := b.readASN1Value(ASN1Boolean, "ReadASN1Boolean", func(c []byte) error {
+
+		if len(c) != 1 {
+			return ErrASN1Syntax
+		}
+
+		if b.der && c[0] != 0x00 && c[0] != 0xff {
+			return ErrASN1NotDER
+		}
+
+		v = c[0] != 0
+
+		return nil
+	})
+
+	return v, err
+}
+
+// ReadASN1Int64 reads an ASN.1 INTEGER.
+// If the value does not fit into an int64, returns a *DecodeError wrapping ErrValueOverflow.
+func (b *Buffer) ReadASN1Int64() (int64, error) {
+	return b.readASN1Int64(ASN1Integer, "ReadASN1Int64")
+}
+
+// ReadASN1Enumerated reads an ASN.1 ENUMERATED.
+func (b *Buffer) ReadASN1Enumerated() (int64, error) {
+	return b.readASN1Int64(ASN1Enumerated, "ReadASN1Enumerated")
+}
+
+// readASN1Int64 reads an integer with tag.
+func (b *Buffer) readASN1Int64(tag ASN1Tag, op string) (int64, error) {
+
+	var v int64
+
+	err := b.readASN1Value(tag, op, func(c []byte) error {
+
+		c, err := checkASN1Integer(c, b.der)
+		if err != nil {
+			return err
+		}
+
+		if len(c) > 8 {
+			return ErrValueOverflow
+		}
+
+		// Sign extension
+		if c[0]&0x80 != 0 {
+			v = -1
+		}
+
+		for _, x := range c {
+			v = v<<8 | int64(x)
+		}
+
+		return nil
+	})
+
+	return v, err
+}
+
+// ReadASN1Uint64 reads an ASN.1 INTEGER.
+// If the value is negative or does not fit into an uint64, returns a *DecodeError wrapping ErrValueOverflow.
+func (b *Buffer) ReadASN1Uint64() (uint64, error) {
+
+	var v uint64
+
+	err := b.readASN1Value(ASN1Integer, "ReadASN1Uint64", func(c []byte) error {
+
+		c, err := checkASN1Integer(c, b.der)
+		if err != nil {
+			return err
+		}
+
+		if c[0]&0x80 != 0 {
+			return ErrValueOverflow
+		}
+
+		if len(c) == 9 && c[0] == 0 {
+			c = c[1:]
+		}
+
+		if len(c) > 8 {
+			return ErrValueOverflow
+		}
+
+		for _, x := range c {
+			v = v<<8 | uint64(x)
+		}
+
+		return nil
+	})
+
+	return v, err
+}
+
+// ReadASN1BigInt reads an ASN.1 INTEGER of any size.
+func (b *Buffer) ReadASN1BigInt() (*big.Int, error) {
+
+	v := new(big.Int)
+
+	err := b.readASN1Value(ASN1Integer, "ReadASN1BigInt", func(c []byte) error {
+
+		c, err := checkASN1Integer(c, b.der)
+		if err != nil {
+			return err
+		}
+
+		if c[0]&0x80 == 0 {
+			v.SetBytes(c)
+			return nil
+		}
+
+		// Negative: -(^c + 1)
+		n := make([]byte, len(c))
+		for i := range c {
+			n[i] = ^c[i]
+		}
+
+		v.SetBytes(n)
+		v.Add(v, big.NewInt(1))
+		v.Neg(v)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// checkASN1Integer checks the content of an INTEGER and removes the redundant leading bytes.
+// In strict DER mode, redundant leading bytes return ErrASN1NotDER.
+func checkASN1Integer(c []byte, der bool) ([]byte, error) {
+
+	if len(c) == 0 {
+		return nil, ErrASN1Syntax
+	}
+
+	for len(c) > 1 && (c[0] == 0x00 && c[1]&0x80 == 0 || c[0] == 0xff && c[1]&0x80 != 0) {
+
+		if der {
+			return nil, ErrASN1NotDER
+		}
+
+		c = c[1:]
+	}
+
+	return c, nil
+}
+
+// ReadASN1OctetString reads an ASN.1 OCTET STRING.
+// In BER mode, the segments of a constructed OCTET STRING are concatenated into a new slice,
+// otherwise the returned slice points into b.
+func (b *Buffer) ReadASN1OctetString() ([]byte, error) {
+
+	off := b.off
+
+	tag, _, content, coff, err := b.readASN1("ReadASN1OctetString")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tag == ASN1OctetString:
+		return content, nil
+	case tag == ASN1OctetString.Constructed() && b.der:
+		b.off = off
+		return nil, &DecodeError{Op: "ReadASN1OctetString", Offset: b.base + off, Err: ErrASN1NotDER}
+	case tag == ASN1OctetString.Constructed():
+		c := b.child(content, coff)
+		v, err := c.readASN1Segments(ASN1OctetString, []byte{}, 0)
+		if err != nil {
+			b.off = off
+			return nil, err
+		}
+		return v, nil
+	default:
+		b.off = off
+		return nil, &DecodeError{Op: "ReadASN1OctetString", Offset: b.base + off, Err: fmt.Errorf("%w: have %s, want %s", ErrASN1Tag, tag, ASN1OctetString)}
+	}
+}
+
+// readASN1Segments appends the content of the primitive segments of a constructed string to dst.
+func (b *Buffer) readASN1Segments(tag ASN1Tag, dst []byte, depth int) ([]byte, error) {
+
+	if depth > maxASN1Depth {
+		return nil, &DecodeError{Op: "ReadASN1OctetString", Offset: b.base + b.off, Err: ErrASN1Syntax}
+	}
+
+	for !b.Empty() {
+
+		off := b.off
+
+		t, _, content, coff, err := b.readASN1("ReadASN1OctetString")
+		if err != nil {
+			return nil, err
+		}
+
+		switch t {
+		case tag:
+			dst = append(dst, content...)
+		case tag.Constructed():
+			c := b.child(content, coff)
+			if dst, err = c.readASN1Segments(tag, dst, depth+1); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &DecodeError{Op: "ReadASN1OctetString", Offset: b.base + off, Err: fmt.Errorf("%w: have %s, want %s", ErrASN1Tag, t, tag)}
+		}
+	}
+
+	return dst, nil
+}
+
+// ReadASN1Null reads an ASN.1 NULL.
+func (b *Buffer) ReadASN1Null() error {
+
+	return b.readASN1Value(ASN1Null, "ReadASN1Null", func(c []byte) error {
+
+		if len(c) != 0 {
+			return ErrASN1Syntax
+		}
+
+		return nil
+	})
+}
+
+// ReadASN1BitString reads an ASN.1 BIT STRING. The returned Bytes points into b.
+// In strict DER mode, the unused bits of the last byte must be zero.
+func (b *Buffer) ReadASN1BitString() (asn1.BitString, error) {
+
+	var v asn1.BitString
+
+	err := b.readASN1Value(ASN1BitString, "ReadASN1BitString", func(c []byte) error {
+
+		if len(c) == 0 || c[0] > 7 || (len(c) == 1 && c[0] != 0) {
+			return ErrASN1Syntax
+		}
+
+		pad := int(c[0])
+
+		if b.der && pad > 0 && c[len(c)-1]&(1<<pad-1) != 0 {
+			return ErrASN1NotDER
+		}
+
+		v = asn1.BitString{Bytes: c[1:], BitLength: (len(c)-1)*8 - pad}
+
+		return nil
+	})
+
+	return v, err
+}
+
+// ReadASN1ObjectIdentifier reads an ASN.1 OBJECT IDENTIFIER.
+// If an arc does not fit into an int, returns a *DecodeError wrapping ErrValueOverflow.
+func (b *Buffer) ReadASN1ObjectIdentifier() (asn1.ObjectIdentifier, error) {
+
+	var oid asn1.ObjectIdentifier
+
+	err := b.readASN1Value(ASN1ObjectIdentifier, "ReadASN1ObjectIdentifier", func(c []byte) error {
+
+		if len(c) == 0 {
+			return ErrASN1Syntax
+		}
+
+		oid = make(asn1.ObjectIdentifier, 0, len(c)+1)
+
+		for len(c) > 0 {
+
+			v, rest, err := parseBase128(c)
+			if err != nil {
+				return err
+			}
+
+			// The first subidentifier encodes the first two arcs.
+			switch {
+			case len(oid) > 0:
+				oid = append(oid, v)
+			case v < 80:
+				oid = append(oid, v/40, v%40)
+			default:
+				oid = append(oid, 2, v-80)
+			}
+
+			c = rest
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oid, nil
+}
+
+// ReadASN1UTCTime reads an ASN.1 UTCTime. Two-digit years from 50 are in the 1900s, below 50 in the 2000s.
+// In BER mode, the seconds are optional and the time can have an offset,
+// in strict DER mode only the "YYMMDDhhmmssZ" form is accepted.
+func (b *Buffer) ReadASN1UTCTime() (time.Time, error) {
+
+	var t time.Time
+
+	err := b.readASN1Value(ASN1UTCTime, "ReadASN1UTCTime", func(c []byte) error {
+
+		v, err := parseASN1Time(string(c), utcTimeBER, utcTimeDER, b.der)
+		if err != nil {
+			return err
+		}
+
+		// time.Parse maps the years 69-99 into the 1900s.
+		if v.Year() >= 2050 {
+			v = v.AddDate(-100, 0, 0)
+		}
+
+		t = v
+
+		return nil
+	})
+
+	return t, err
+}
+
+// ReadASN1GeneralizedTime reads an ASN.1 GeneralizedTime.
+// In BER mode, the time can have an offset,
+// in strict DER mode only the "YYYYMMDDhhmmss[.f]Z" form without trailing zeros is accepted.
+func (b *Buffer) ReadASN1GeneralizedTime() (time.Time, error) {
+
+	var t time.Time
+
+	err := b.readASN1Value(ASN1GeneralizedTime, "ReadASN1GeneralizedTime", func(c []byte) error {
+
+		v, err := parseASN1Time(string(c), generalizedTimeBER, generalizedTimeDER, b.der)
+		if err != nil {
+			return err
+		}
+
+		t = v
+
+		return nil
+	})
+
+	return t, err
+}
+
+// parseASN1Time parses s with the first matching layout.
+// In strict DER mode, s must be the der formatting of the parsed time.
+func parseASN1Time(s string, layouts []string, der string, strict bool) (time.Time, error) {
+
+	for _, layout := range layouts {
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+
+		if strict && t.UTC().Format(der) != s {
+			return time.Time{}, ErrASN1NotDER
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, ErrASN1Syntax
+}
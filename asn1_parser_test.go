@@ -0,0 +1,267 @@
+package bytebuilder
+
+import (
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestASN1RoundTrip(t *testing.T) {
+
+	when := time.Date(2024, 2, 29, 12, 30, 45, 0, time.UTC)
+	bigInt, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+
+	tests := []struct {
+		name  string
+		write func(b *Buffer) error
+		read  func(b *Buffer) (any, error)
+		want  any
+	}{
+		{"boolean", func(b *Buffer) error { b.AddASN1Boolean(true); return nil },
+			func(b *Buffer) (any, error) { return b.ReadASN1Boolean() }, true},
+		{"int64", func(b *Buffer) error { b.AddASN1Int64(-129); return nil },
+			func(b *Buffer) (any, error) { return b.ReadASN1Int64() }, int64(-129)},
+		{"uint64", func(b *Buffer) error { b.AddASN1Uint64(1 << 63); return nil },
+			func(b *Buffer) (any, error) { return b.ReadASN1Uint64() }, uint64(1 << 63)},
+		{"enumerated", func(b *Buffer) error { b.AddASN1Enumerated(3); return nil },
+			func(b *Buffer) (any, error) { return b.ReadASN1Enumerated() }, int64(3)},
+		{"big int", func(b *Buffer) error { b.AddASN1BigInt(bigInt); return nil },
+			func(b *Buffer) (any, error) { return b.ReadASN1BigInt() }, bigInt},
+		{"octet string", func(b *Buffer) error { b.AddASN1OctetString([]byte{1, 2}); return nil },
+			func(b *Buffer) (any, error) { return b.ReadASN1OctetString() }, []byte{1, 2}},
+		{"bit string", func(b *Buffer) error { return b.AddASN1BitString(asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3}) },
+			func(b *Buffer) (any, error) { return b.ReadASN1BitString() }, asn1.BitString{Bytes: []byte{0xa0}, BitLength: 3}},
+		{"object identifier", func(b *Buffer) error { return b.AddASN1ObjectIdentifier(asn1.ObjectIdentifier{1, 2, 840, 113549}) },
+			func(b *Buffer) (any, error) { return b.ReadASN1ObjectIdentifier() }, asn1.ObjectIdentifier{1, 2, 840, 113549}},
+		{"utc time", func(b *Buffer) error { return b.AddASN1UTCTime(when) },
+			func(b *Buffer) (any, error) { return b.ReadASN1UTCTime() }, when},
+		{"generalized time", func(b *Buffer) error { return b.AddASN1GeneralizedTime(when) },
+			func(b *Buffer) (any, error) { return b.ReadASN1GeneralizedTime() }, when},
+		{"sequence", func(b *Buffer) error {
+			return b.AddASN1(ASN1Sequence, func(c *Buffer) error {
+				c.AddASN1Int64(1)
+				c.AddASN1Bytes(ASN1UTF8String, []byte("x"))
+				return nil
+			})
+		}, func(b *Buffer) (any, error) {
+			var c Buffer
+			if err := b.ReadASN1(ASN1Sequence, &c); err != nil {
+				return nil, err
+			}
+			n, err := c.ReadASN1Int64()
+			if err != nil {
+				return nil, err
+			}
+			s, err := c.ReadASN1Bytes(ASN1UTF8String)
+			if err != nil {
+				return nil, err
+			}
+			return []any{n, string(s)}, c.ExpectEmpty()
+		}, []any{int64(1), "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			b := NewEmpty()
+
+			if err := tt.write(&b); err != nil {
+				t.Fatalf("write: %s", err)
+			}
+
+			b.SetStrictDER(true)
+
+			got, err := tt.read(&b)
+			if err != nil {
+				t.Fatalf("read: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("read = %v, want %v", got, tt.want)
+			}
+
+			if !b.Empty() {
+				t.Fatalf("%d bytes left", b.Remaining())
+			}
+		})
+	}
+}
+
+func TestASN1Errors(t *testing.T) {
+
+	tests := []struct {
+		name string
+		data []byte
+		der  bool
+		err  error
+	}{
+		{"truncated", []byte{0x02, 0x02, 0x01}, false, ErrShortBuffer},
+		{"wrong tag", []byte{0x04, 0x01, 0x01}, false, ErrASN1Tag},
+		{"empty integer", []byte{0x02, 0x00}, false, ErrASN1Syntax},
+		{"non-minimal integer", []byte{0x02, 0x02, 0x00, 0x01}, true, ErrASN1NotDER},
+		{"long form length", []byte{0x02, 0x81, 0x01, 0x01}, true, ErrASN1NotDER},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			b := NewBuffer(tt.data)
+			b.SetStrictDER(tt.der)
+
+			if _, err := b.ReadASN1Int64(); !errors.Is(err, tt.err) {
+				t.Fatalf("ReadASN1Int64 error = %v, want %v", err, tt.err)
+			}
+
+			if b.Offset() != 0 {
+				t.Fatalf("Offset = %d after error, want 0", b.Offset())
+			}
+		})
+	}
+}
+
+// TestASN1ChildOffset checks that the errors of nested elements report the offset in the outermost Buffer.
+func TestASN1ChildOffset(t *testing.T) {
+
+	// Padding, SEQUENCE { SEQUENCE { INTEGER with a truncated content } }
+	b := NewBuffer([]byte{0x00, 0x30, 0x05, 0x30, 0x03, 0x02, 0x05, 0x01})
+	b.Skip(1)
+
+	var s1, s2 Buffer
+
+	if err := b.ReadASN1(ASN1Sequence, &s1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s1.ReadASN1(ASN1Sequence, &s2); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s2.ReadASN1Int64()
+
+	var de *DecodeError
+
+	if !errors.As(err, &de) || de.Offset != 5 {
+		t.Fatalf("ReadASN1Int64 error = %v, want offset 5", err)
+	}
+}
+
+// walkASN1 reads every element of b recursively, decoding the primitive types it knows.
+func walkASN1(b *Buffer, depth int) error {
+
+	for !b.Empty() {
+
+		var c Buffer
+
+		tag, err := b.ReadAnyASN1(&c)
+		if err != nil {
+			return err
+		}
+
+		if tag&asn1ConstructedBit != 0 {
+			if depth < maxASN1Depth {
+				if err := walkASN1(&c, depth+1); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Decode the content again with its own tag, the result is not checked.
+		e := NewEmpty()
+		e.AddASN1Bytes(tag, c.Bytes())
+		e.SetStrictDER(b.StrictDER())
+
+		switch tag {
+		case ASN1Boolean:
+			e.ReadASN1Boolean()
+		case ASN1Integer:
+			e.ReadASN1BigInt()
+		case ASN1BitString:
+			e.ReadASN1BitString()
+		case ASN1ObjectIdentifier:
+			e.ReadASN1ObjectIdentifier()
+		case ASN1UTCTime:
+			e.ReadASN1UTCTime()
+		case ASN1GeneralizedTime:
+			e.ReadASN1GeneralizedTime()
+		}
+	}
+
+	return nil
+}
+
+func FuzzASN1Parser(f *testing.F) {
+
+	seed := NewEmpty()
+	seed.AddASN1(ASN1Sequence, func(c *Buffer) error {
+		c.AddASN1Int64(-1)
+		c.AddASN1Boolean(true)
+		c.AddASN1ObjectIdentifier(asn1.ObjectIdentifier{2, 5, 4, 3})
+		return c.AddASN1UTCTime(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	})
+
+	f.Add(seed.Bytes(), true)
+	f.Add([]byte{0x30, 0x80, 0x02, 0x01, 0x01, 0x00, 0x00}, false)
+
+	f.Fuzz(func(t *testing.T, data []byte, der bool) {
+
+		b := NewBuffer(data)
+		b.SetStrictDER(der)
+
+		if err := walkASN1(&b, 0); err != nil {
+			return
+		}
+
+		// Every element was read, so the input was consumed exactly.
+		if b.Offset() != len(data) {
+			t.Fatalf("Offset = %d after a successful walk, want %d", b.Offset(), len(data))
+		}
+	})
+}
+
+// TestASN1ChildSettings checks that the strict DER mode is inherited by the child Buffers.
+func TestASN1ChildSettings(t *testing.T) {
+
+	// SEQUENCE { INTEGER with a redundant leading byte }
+	seq := []byte{0x30, 0x04, 0x02, 0x02, 0x00, 0x01}
+
+	b := NewBuffer(seq)
+	b.SetStrictDER(true)
+
+	var c Buffer
+
+	if err := b.ReadASN1(ASN1Sequence, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.ReadASN1Int64(); !errors.Is(err, ErrASN1NotDER) {
+		t.Fatalf("ReadASN1 child: ReadASN1Int64 error = %v, want %v", err, ErrASN1NotDER)
+	}
+
+	b = NewBuffer(append([]byte{byte(len(seq))}, seq...))
+	b.SetStrictDER(true)
+
+	if err := b.ReadLengthPrefixed(8, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.StrictDER() {
+		t.Fatalf("ReadLengthPrefixed child is not in strict DER mode")
+	}
+
+	b = NewEmpty()
+	b.SetStrictDER(true)
+
+	err := b.AddASN1(ASN1Sequence, func(c *Buffer) error {
+		if !c.StrictDER() {
+			t.Errorf("AddASN1 child is not in strict DER mode")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
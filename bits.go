@@ -0,0 +1,297 @@
+package bytebuilder
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// BitOrder is the order of the bits in a byte for BitReader and BitWriter.
+type BitOrder byte
+
+const (
+	// MSBFirst reads and writes the most significant bit of a byte first,
+	// and the first bit of a field is its most significant bit (eg.: IPv4, DNS, H.264).
+	MSBFirst BitOrder = iota
+
+	// LSBFirst reads and writes the least significant bit of a byte first,
+	// and the first bit of a field is its least significant bit (eg.: DEFLATE).
+	LSBFirst
+)
+
+// ErrExpGolombOverflow is returned when an exp-Golomb code does not fit into 64 bits.
+var ErrExpGolombOverflow = errors.New("exp-Golomb code overflows a 64-bit integer")
+
+// BitReader reads bit fields from a Buffer.
+// Bytes are read from the Buffer when needed, so byte-aligned reads on the Buffer can be mixed with
+// bit reads after calling Align.
+type BitReader struct {
+	b     *Buffer
+	order BitOrder
+	cur   byte // the current byte
+	n     uint // number of unread bits in cur
+}
+
+// NewBitReader creates a BitReader that reads from b in bit order order.
+func NewBitReader(b *Buffer, order BitOrder) *BitReader {
+	return &BitReader{b: b, order: order}
+}
+
+// Aligned returns whether the reader is at a byte boundary.
+func (r *BitReader) Aligned() bool {
+	return r.n == 0
+}
+
+// Align discards the unread bits of the current byte, so the next read starts at a byte boundary.
+func (r *BitReader) Align() {
+	r.cur = 0
+	r.n = 0
+}
+
+// offset returns the position of the byte that holds the next bit in the Buffer.
+func (r *BitReader) offset() int {
+
+	if r.n > 0 {
+		return r.b.off - 1
+	}
+
+	return r.b.off
+}
+
+// readBit reads the next bit.
+func (r *BitReader) readBit(op string) (uint64, error) {
+
+	if r.n == 0 {
+
+		v, err := r.b.readE(1, op)
+		if err != nil {
+			return 0, err
+		}
+
+		r.cur = v[0]
+		r.n = 8
+	}
+
+	r.n--
+
+	if r.order == LSBFirst {
+		return uint64(r.cur>>(7-r.n)) & 1, nil
+	}
+
+	return uint64(r.cur>>r.n) & 1, nil
+}
+
+// readBits reads n bits as an unsigned value.
+func (r *BitReader) readBits(n uint, op string) (uint64, error) {
+
+	if n > 64 {
+		return 0, &DecodeError{Op: op, Offset: r.b.base + r.offset(), Err: ErrInvalidBitSize}
+	}
+
+	var v uint64
+
+	for i := uint(0); i < n; i++ {
+
+		bit, err := r.readBit(op)
+		if err != nil {
+			return 0, err
+		}
+
+		if r.order == LSBFirst {
+			v |= bit << i
+		} else {
+			v = v<<1 | bit
+		}
+	}
+
+	return v, nil
+}
+
+// ReadBits reads an n bit unsigned field.
+// n must be between 0 and 64, otherwise ErrInvalidBitSize is returned.
+func (r *BitReader) ReadBits(n uint) (uint64, error) {
+	return r.readBits(n, "ReadBits")
+}
+
+// ReadSignedBits reads an n bit two's complement signed field.
+// n must be between 0 and 64, otherwise ErrInvalidBitSize is returned.
+func (r *BitReader) ReadSignedBits(n uint) (int64, error) {
+
+	v, err := r.readBits(n, "ReadSignedBits")
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 && n < 64 && v>>(n-1)&1 == 1 {
+		v |= math.MaxUint64 << n
+	}
+
+	return int64(v), nil
+}
+
+// ReadBool reads a single bit flag.
+func (r *BitReader) ReadBool() (bool, error) {
+
+	v, err := r.readBit("ReadBool")
+
+	return v == 1, err
+}
+
+// ReadUE reads an unsigned exp-Golomb code (ue(v) in H.264).
+func (r *BitReader) ReadUE() (uint64, error) {
+
+	off := r.offset()
+
+	var lz uint
+
+	for {
+
+		bit, err := r.readBit("ReadUE")
+		if err != nil {
+			return 0, err
+		}
+
+		if bit == 1 {
+			break
+		}
+
+		lz++
+
+		if lz > 63 {
+			return 0, &DecodeError{Op: "ReadUE", Offset: r.b.base + off, Err: ErrExpGolombOverflow}
+		}
+	}
+
+	v, err := r.readBits(lz, "ReadUE")
+	if err != nil {
+		return 0, err
+	}
+
+	return 1<<lz - 1 + v, nil
+}
+
+// ReadSE reads a signed exp-Golomb code (se(v) in H.264).
+func (r *BitReader) ReadSE() (int64, error) {
+
+	k, err := r.ReadUE()
+	if err != nil {
+		return 0, err
+	}
+
+	if k&1 == 1 {
+		return int64(k/2 + 1), nil
+	}
+
+	return -int64(k / 2), nil
+}
+
+// BitWriter writes bit fields to a Buffer.
+// Complete bytes are appended to the Buffer as soon as they are filled,
+// call Align before writing byte-aligned values to the Buffer directly.
+type BitWriter struct {
+	b     *Buffer
+	order BitOrder
+	cur   byte // the current byte
+	n     uint // number of written bits in cur
+}
+
+// NewBitWriter creates a BitWriter that writes to b in bit order order.
+func NewBitWriter(b *Buffer, order BitOrder) *BitWriter {
+	return &BitWriter{b: b, order: order}
+}
+
+// Aligned returns whether the writer is at a byte boundary.
+func (w *BitWriter) Aligned() bool {
+	return w.n == 0
+}
+
+// Align pads the current byte with zero bits and appends it to the Buffer.
+// If the writer is at a byte boundary, Align does nothing.
+func (w *BitWriter) Align() {
+
+	if w.n == 0 {
+		return
+	}
+
+	w.b.WriteUint8(w.cur)
+	w.cur = 0
+	w.n = 0
+}
+
+// writeBit writes the lowest bit of bit.
+func (w *BitWriter) writeBit(bit byte) {
+
+	if w.order == LSBFirst {
+		w.cur |= (bit & 1) << w.n
+	} else {
+		w.cur |= (bit & 1) << (7 - w.n)
+	}
+
+	w.n++
+
+	if w.n == 8 {
+		w.Align()
+	}
+}
+
+// WriteBits writes the lowest n bits of v.
+// If n is greater than 64, this function panics.
+func (w *BitWriter) WriteBits(v uint64, n uint) {
+
+	if n > 64 {
+		panic("invalid bitSize value")
+	}
+
+	for i := uint(0); i < n; i++ {
+		if w.order == LSBFirst {
+			w.writeBit(byte(v >> i))
+		} else {
+			w.writeBit(byte(v >> (n - 1 - i)))
+		}
+	}
+}
+
+// WriteSignedBits writes v as an n bit two's complement field.
+// If n is greater than 64, this function panics.
+func (w *BitWriter) WriteSignedBits(v int64, n uint) {
+	w.WriteBits(uint64(v), n)
+}
+
+// WriteBool writes v as a single bit flag.
+func (w *BitWriter) WriteBool(v bool) {
+
+	if v {
+		w.writeBit(1)
+	} else {
+		w.writeBit(0)
+	}
+}
+
+// WriteUE writes v as an unsigned exp-Golomb code (ue(v) in H.264).
+// If v is math.MaxUint64, this function panics.
+func (w *BitWriter) WriteUE(v uint64) {
+
+	if v == math.MaxUint64 {
+		panic("exp-Golomb value out of range")
+	}
+
+	lz := uint(bits.Len64(v+1)) - 1
+
+	w.WriteBits(0, lz)
+	w.writeBit(1)
+	w.WriteBits(v+1-1<<lz, lz)
+}
+
+// WriteSE writes v as a signed exp-Golomb code (se(v) in H.264).
+// If v is math.MinInt64, this function panics.
+func (w *BitWriter) WriteSE(v int64) {
+
+	switch {
+	case v == math.MinInt64:
+		panic("exp-Golomb value out of range")
+	case v > 0:
+		w.WriteUE(uint64(v)*2 - 1)
+	default:
+		w.WriteUE(uint64(-v) * 2)
+	}
+}
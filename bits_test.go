@@ -0,0 +1,196 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBitOrder(t *testing.T) {
+
+	tests := []struct {
+		order BitOrder
+		want  []byte
+	}{
+		// 101 | 0011 1100 1 | 1 (bool) | 0 (align padding)
+		{MSBFirst, []byte{0b10100111, 0b10011000}},
+		// The fields fill the bytes from the least significant bit: 101 in bits 0-2, 0x079 from bit 3.
+		{LSBFirst, []byte{0b11001101, 0b00010011}},
+	}
+
+	for _, tt := range tests {
+
+		b := NewEmpty()
+
+		w := NewBitWriter(&b, tt.order)
+		w.WriteBits(0b101, 3)
+		w.WriteBits(0x079, 9)
+		w.WriteBool(true)
+
+		if w.Aligned() || b.Size() != 1 {
+			t.Fatalf("%d: Aligned = %t, Size = %d, want a pending byte", tt.order, w.Aligned(), b.Size())
+		}
+
+		w.Align()
+
+		if !bytes.Equal(b.Bytes(), tt.want) {
+			t.Fatalf("%d: wrote %08b, want %08b", tt.order, b.Bytes(), tt.want)
+		}
+
+		r := NewBitReader(&b, tt.order)
+
+		if v, err := r.ReadBits(3); err != nil || v != 0b101 {
+			t.Fatalf("%d: ReadBits(3) = %b, %v", tt.order, v, err)
+		}
+
+		// The field crosses the byte boundary.
+		if v, err := r.ReadBits(9); err != nil || v != 0x079 {
+			t.Fatalf("%d: ReadBits(9) = %#x, %v", tt.order, v, err)
+		}
+
+		if v, err := r.ReadBool(); err != nil || !v {
+			t.Fatalf("%d: ReadBool = %t, %v", tt.order, v, err)
+		}
+
+		r.Align()
+
+		if !r.Aligned() || !b.Empty() {
+			t.Fatalf("%d: Aligned = %t, Remaining = %d", tt.order, r.Aligned(), b.Remaining())
+		}
+	}
+}
+
+func TestBitFields(t *testing.T) {
+
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+
+		b := NewEmpty()
+
+		w := NewBitWriter(&b, order)
+		w.WriteBits(math.MaxUint64, 64)
+		w.WriteSignedBits(-3, 5)
+		w.WriteSignedBits(math.MinInt64, 64)
+		w.WriteBits(0x5, 0)
+		w.Align()
+
+		r := NewBitReader(&b, order)
+
+		if v, err := r.ReadBits(64); err != nil || v != math.MaxUint64 {
+			t.Fatalf("%d: ReadBits(64) = %#x, %v", order, v, err)
+		}
+
+		if v, err := r.ReadSignedBits(5); err != nil || v != -3 {
+			t.Fatalf("%d: ReadSignedBits(5) = %d, %v", order, v, err)
+		}
+
+		if v, err := r.ReadSignedBits(64); err != nil || v != math.MinInt64 {
+			t.Fatalf("%d: ReadSignedBits(64) = %d, %v", order, v, err)
+		}
+
+		if v, err := r.ReadBits(0); err != nil || v != 0 {
+			t.Fatalf("%d: ReadBits(0) = %d, %v", order, v, err)
+		}
+	}
+}
+
+func TestExpGolomb(t *testing.T) {
+
+	// ue(v) codes of H.264 9.1.
+	codes := []struct {
+		v    uint64
+		bits string
+	}{
+		{0, "1"},
+		{1, "010"},
+		{2, "011"},
+		{3, "00100"},
+		{6, "00111"},
+		{7, "0001000"},
+	}
+
+	for _, tt := range codes {
+
+		b := NewEmpty()
+
+		w := NewBitWriter(&b, MSBFirst)
+		w.WriteUE(tt.v)
+
+		for _, c := range tt.bits {
+			if c == '1' {
+				w.writeBit(1)
+			} else {
+				w.writeBit(0)
+			}
+		}
+
+		w.Align()
+
+		r := NewBitReader(&b, MSBFirst)
+
+		// The written code is followed by the same code written bit by bit.
+		for i := 0; i < 2; i++ {
+			if v, err := r.ReadUE(); err != nil || v != tt.v {
+				t.Fatalf("ReadUE of %s = %d, %v, want %d", tt.bits, v, err, tt.v)
+			}
+		}
+	}
+
+	b := NewEmpty()
+
+	w := NewBitWriter(&b, MSBFirst)
+
+	values := []int64{0, 1, -1, 2, -2, math.MaxInt64, math.MinInt64 + 1}
+
+	for _, v := range values {
+		w.WriteSE(v)
+	}
+
+	w.WriteUE(math.MaxUint64 - 1)
+	w.Align()
+
+	r := NewBitReader(&b, MSBFirst)
+
+	for _, want := range values {
+		if v, err := r.ReadSE(); err != nil || v != want {
+			t.Fatalf("ReadSE = %d, %v, want %d", v, err, want)
+		}
+	}
+
+	if v, err := r.ReadUE(); err != nil || v != math.MaxUint64-1 {
+		t.Fatalf("ReadUE = %d, %v, want MaxUint64-1", v, err)
+	}
+}
+
+func TestBitReaderErrors(t *testing.T) {
+
+	// The code starts in the middle of the second byte and has 64 leading zeros.
+	b := NewBuffer([]byte{0xff, 0xf0, 0, 0, 0, 0, 0, 0, 0, 0x0f})
+
+	r := NewBitReader(&b, MSBFirst)
+
+	if v, err := r.ReadBits(12); err != nil || v != 0xfff {
+		t.Fatalf("ReadBits(12) = %#x, %v", v, err)
+	}
+
+	_, err := r.ReadUE()
+
+	var de *DecodeError
+
+	if !errors.As(err, &de) || de.Op != "ReadUE" || de.Offset != 1 || !errors.Is(err, ErrExpGolombOverflow) {
+		t.Fatalf("ReadUE error = %v, want ErrExpGolombOverflow at offset 1", err)
+	}
+
+	b = NewBuffer([]byte{0xff, 0x01})
+
+	r = NewBitReader(&b, MSBFirst)
+	r.ReadBits(4)
+
+	if _, err := r.ReadBits(65); !errors.As(err, &de) || de.Offset != 0 || !errors.Is(err, ErrInvalidBitSize) {
+		t.Fatalf("ReadBits(65) error = %v, want ErrInvalidBitSize at offset 0", err)
+	}
+
+	if _, err := r.ReadBits(13); !errors.As(err, &de) || de.Op != "ReadBits" || de.Offset != 2 || !errors.Is(err, ErrShortBuffer) {
+		t.Fatalf("ReadBits(13) error = %v, want ErrShortBuffer at offset 2", err)
+	}
+}
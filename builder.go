@@ -0,0 +1,144 @@
+package bytebuilder
+
+// BuilderContinuation is called by the Add*LengthPrefixed methods to write the content of a length-prefixed vector.
+// The child writes in place at the end of the parent Buffer with the byte order and the settings of the parent,
+// its read cursor is independent from the parent.
+// A returned error aborts the whole vector.
+type BuilderContinuation func(child *Buffer) error
+
+// AddUint8LengthPrefixed appends an uint8 length-prefixed vector whose content is written by f.
+// If the content is longer than 255 bytes, returns an *EncodeError wrapping ErrLengthOverflow.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddUint8LengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(8, f, "AddUint8LengthPrefixed")
+}
+
+// AddUint16LengthPrefixed appends an uint16 length-prefixed vector whose content is written by f.
+// The length is written in the byte order of b.
+// If the content does not fit into the prefix, returns an *EncodeError wrapping ErrLengthOverflow.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddUint16LengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(16, f, "AddUint16LengthPrefixed")
+}
+
+// AddUint24LengthPrefixed appends a uint24 length-prefixed vector whose content is written by f.
+// The length is written in the byte order of b.
+// If the content does not fit into the prefix, returns an *EncodeError wrapping ErrLengthOverflow.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddUint24LengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(24, f, "AddUint24LengthPrefixed")
+}
+
+// AddUint32LengthPrefixed appends an uint32 length-prefixed vector whose content is written by f.
+// The length is written in the byte order of b.
+// If the content does not fit into the prefix, returns an *EncodeError wrapping ErrLengthOverflow.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddUint32LengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(32, f, "AddUint32LengthPrefixed")
+}
+
+// AddUint64LengthPrefixed appends an uint64 length-prefixed vector whose content is written by f.
+// The length is written in the byte order of b.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddUint64LengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(64, f, "AddUint64LengthPrefixed")
+}
+
+// AddUvarintLengthPrefixed appends an unsigned LEB128 varint length-prefixed vector whose content is written by f.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddUvarintLengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(VarintLength, f, "AddUvarintLengthPrefixed")
+}
+
+// AddQUICVarintLengthPrefixed appends a QUIC variable-length integer length-prefixed vector whose content is written by f.
+// If the content is longer than MaxQUICVarint, returns an *EncodeError wrapping ErrLengthOverflow.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddQUICVarintLengthPrefixed(f BuilderContinuation) error {
+	return b.addLengthPrefixed(QUICVarintLength, f, "AddQUICVarintLengthPrefixed")
+}
+
+// AddLengthPrefixed appends a length-prefixed vector whose content is written by f.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength,
+// otherwise an *EncodeError wrapping ErrInvalidBitSize is returned.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) AddLengthPrefixed(bitSize int, f BuilderContinuation) error {
+	return b.addLengthPrefixed(bitSize, f, "AddLengthPrefixed")
+}
+
+// addLengthPrefixed reserves the fixed size length prefix, lets f write the content in place,
+// then patches the length.
+// Variable size prefixes are inserted before the content after f returned,
+// which moves the content once, so prefer a fixed size prefix for large contents.
+func (b *Buffer) addLengthPrefixed(bitSize int, f BuilderContinuation, op string) error {
+
+	var size int
+
+	switch bitSize {
+	case 8, 16, 24, 32, 64:
+		size = bitSize / 8
+	case VarintLength, QUICVarintLength:
+		size = 0
+	default:
+		return &EncodeError{Op: op, Err: ErrInvalidBitSize}
+	}
+
+	start := len(b.b)
+
+	b.b = append(b.b, make([]byte, size)...)
+
+	// The child shares the free capacity of b, so it writes in place unless it has to grow.
+	child := Buffer{b: b.b[len(b.b):], little: b.little, alloc: b.alloc, der: b.der, utf8: b.utf8, strictVarint: b.strictVarint}
+
+	if err := f(&child); err != nil {
+		b.b = b.b[:start]
+		return err
+	}
+
+	b.b = append(b.b[:start+size], child.b...)
+
+	n := len(child.b)
+
+	switch bitSize {
+	case VarintLength:
+		b.insert(start, AppendUvarint(nil, uint64(n)))
+		return nil
+	case QUICVarintLength:
+		prefix, err := AppendQUICVarint(nil, uint64(n), QUICVarintLen(uint64(n)))
+		if err != nil {
+			b.b = b.b[:start]
+			return &EncodeError{Op: op, Length: n, Err: ErrLengthOverflow}
+		}
+		b.insert(start, prefix)
+		return nil
+	}
+
+	if size < 8 && uint64(n) >= 1<<(8*size) {
+		b.b = b.b[:start]
+		return &EncodeError{Op: op, Length: n, Err: ErrLengthOverflow}
+	}
+
+	putUint(b.b[start:start+size], uint64(n), b.little)
+
+	return nil
+}
+
+// insert inserts v into b at position i.
+func (b *Buffer) insert(i int, v []byte) {
+
+	b.b = append(b.b, v...)
+	copy(b.b[i+len(v):], b.b[i:len(b.b)-len(v)])
+	copy(b.b[i:], v)
+}
+
+// putUint stores v into dst using len(dst) bytes in little-endian or big-endian order.
+func putUint(dst []byte, v uint64, little bool) {
+
+	for i := range dst {
+		if little {
+			dst[i] = byte(v >> (8 * i))
+		} else {
+			dst[len(dst)-1-i] = byte(v >> (8 * i))
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBuilderNested(t *testing.T) {
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		b := NewBufferWithEndianness([]byte{0xaa}, e)
+
+		err := b.AddUint16LengthPrefixed(func(c *Buffer) error {
+
+			c.WriteUint8(1)
+
+			if err := c.AddUint8LengthPrefixed(func(c *Buffer) error {
+				c.WriteUint16(0x0102)
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			return c.AddUvarintLengthPrefixed(func(c *Buffer) error {
+				c.WriteBytes(bytes.Repeat([]byte{0xbb}, 200)...)
+				return nil
+			})
+		})
+		if err != nil {
+			t.Fatalf("%v: %s", e, err)
+		}
+
+		want := NewBufferWithEndianness([]byte{0xaa}, e)
+		want.WriteUint16(1 + 3 + 2 + 200)
+		want.WriteUint8(1)
+		want.WriteUint8(2)
+		want.WriteUint16(0x0102)
+		want.WriteBytes(0xc8, 0x01)
+		want.WriteBytes(bytes.Repeat([]byte{0xbb}, 200)...)
+
+		if !bytes.Equal(b.Bytes(), want.Bytes()) {
+			t.Fatalf("%v: wrote % x, want % x", e, b.Bytes(), want.Bytes())
+		}
+	}
+}
+
+func TestBuilderPrefixes(t *testing.T) {
+
+	content := []byte{1, 2, 3}
+
+	tests := []struct {
+		bitSize int
+		prefix  []byte
+	}{
+		{8, []byte{3}},
+		{16, []byte{0, 3}},
+		{24, []byte{0, 0, 3}},
+		{32, []byte{0, 0, 0, 3}},
+		{64, []byte{0, 0, 0, 0, 0, 0, 0, 3}},
+		{VarintLength, []byte{3}},
+		{QUICVarintLength, []byte{3}},
+	}
+
+	for _, tt := range tests {
+
+		b := NewEmpty()
+
+		if err := b.AddLengthPrefixed(tt.bitSize, func(c *Buffer) error { c.WriteBytes(content...); return nil }); err != nil {
+			t.Fatalf("AddLengthPrefixed(%d): %s", tt.bitSize, err)
+		}
+
+		if want := append(tt.prefix, content...); !bytes.Equal(b.Bytes(), want) {
+			t.Fatalf("AddLengthPrefixed(%d) wrote % x, want % x", tt.bitSize, b.Bytes(), want)
+		}
+
+		if v, err := b.ReadVectorE(tt.bitSize); err != nil || !bytes.Equal(v, content) {
+			t.Fatalf("ReadVectorE(%d) = % x, %v", tt.bitSize, v, err)
+		}
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+
+	errAbort := errors.New("abort")
+
+	tests := []struct {
+		name string
+		add  func(b *Buffer) error
+		op   string
+		err  error
+	}{
+		{
+			name: "overflow",
+			add: func(b *Buffer) error {
+				return b.AddUint8LengthPrefixed(func(c *Buffer) error { c.WriteBytes(make([]byte, 256)...); return nil })
+			},
+			op:  "AddUint8LengthPrefixed",
+			err: ErrLengthOverflow,
+		},
+		{
+			name: "nested overflow",
+			add: func(b *Buffer) error {
+				return b.AddUint16LengthPrefixed(func(c *Buffer) error {
+					c.WriteUint32(1)
+					return c.AddUint8LengthPrefixed(func(c *Buffer) error { c.WriteBytes(make([]byte, 300)...); return nil })
+				})
+			},
+			op:  "AddUint8LengthPrefixed",
+			err: ErrLengthOverflow,
+		},
+		{
+			name: "abort",
+			add: func(b *Buffer) error {
+				return b.AddUvarintLengthPrefixed(func(c *Buffer) error { c.WriteUint64(1); return errAbort })
+			},
+			err: errAbort,
+		},
+		{
+			name: "bitSize",
+			add: func(b *Buffer) error {
+				return b.AddLengthPrefixed(12, func(c *Buffer) error { return nil })
+			},
+			op:  "AddLengthPrefixed",
+			err: ErrInvalidBitSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			b := NewBuffer(make([]byte, 2, 1024))
+			b.Skip(1)
+
+			err := tt.add(&b)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+
+			var ee *EncodeError
+			if tt.op != "" && (!errors.As(err, &ee) || ee.Op != tt.op) {
+				t.Fatalf("error = %v, want Op %s", err, tt.op)
+			}
+
+			// The Buffer is left unchanged.
+			if b.Size() != 2 || b.Offset() != 1 {
+				t.Fatalf("Size = %d, Offset = %d after error, want 2, 1", b.Size(), b.Offset())
+			}
+
+			if err := b.AddUint8LengthPrefixed(func(c *Buffer) error { c.WriteUint8(7); return nil }); err != nil || !bytes.Equal(b.Bytes(), []byte{0, 1, 7}) {
+				t.Fatalf("Add after error = %v, Bytes = % x", err, b.Bytes())
+			}
+		})
+	}
+}
+
+func TestBuilderChildSettings(t *testing.T) {
+
+	b := NewEmpty()
+	b.SetStrictVarint(true)
+
+	b.AddUint8LengthPrefixed(func(c *Buffer) error {
+
+		if !c.StrictVarint() {
+			t.Fatal("the child does not inherit StrictVarint")
+		}
+
+		return nil
+	})
+}
@@ -1,6 +1,9 @@
 package bytebuilder
 
-import "io"
+import (
+	"errors"
+	"io"
+)
 
 // From: https://cs.opensource.google/go/go/+/refs/tags/go1.20.1:src/strconv/atoi.go;drc=cf26fbb1f6d9644f447342f42d2dddcbe9ceda61;l=68
 const intSize = 32 << (^uint(0) >> 63)
@@ -8,14 +11,35 @@ const intSize = 32 << (^uint(0) >> 63)
 // IntSize is the size in bits of an int or uint value.
 const IntSize = intSize
 
+// maxInt is the largest value of an int.
+const maxInt = int(^uint(0) >> 1)
+
+// Buffer is a byte slice with a read cursor.
+// Writes are appended at the end of the slice, reads are started from the cursor.
+// Reading does not discard the bytes, so the cursor can be moved back with Seek, Rewind or Unread.
+//
+// Multi-byte integers are read and written in big-endian order by default,
+// the byte order can be changed with SetEndianness.
 type Buffer struct {
-	b []byte
+	b            []byte
+	off          int        // read offset in b
+	base         int        // offset of b in its parent Buffer, added to the offsets of the errors
+	little       bool       // use little-endian byte order
+	alloc        *allocator // decoding limits, nil if there is no limit
+	der          bool       // strict DER mode of the ASN.1 parser
+	utf8         bool       // validate the UTF-8 encoding of the strings read
+	strictVarint bool       // reject the non-minimal varint encodings
 }
 
 func NewBuffer(bytes []byte) Buffer {
 	return Buffer{b: bytes}
 }
 
+// NewBufferWithEndianness creates a Buffer from bytes that uses byte order e.
+func NewBufferWithEndianness(bytes []byte, e Endianness) Buffer {
+	return Buffer{b: bytes, little: e == LittleEndian}
+}
+
 // NewEmpty creates a Buffer with a zero length byte slice.
 func NewEmpty() Buffer {
 	return Buffer{b: make([]byte, 0)}
@@ -37,22 +61,103 @@ func ReadAll(r io.Reader) (Buffer, error) {
 	return Buffer{b: b}, err
 }
 
-// Empty returns whether b is empty.
+// Endianness returns the byte order used by b.
+func (b *Buffer) Endianness() Endianness {
+
+	if b.little {
+		return LittleEndian
+	}
+
+	return BigEndian
+}
+
+// SetEndianness sets the byte order used by b to e.
+func (b *Buffer) SetEndianness(e Endianness) {
+	b.little = e == LittleEndian
+}
+
+// Empty returns whether b has no unread bytes.
 func (b *Buffer) Empty() bool {
-	return len(b.b) == 0
+	return b.Remaining() == 0
 }
 
-// Size returns the size of the underlying byte slice
+// Size returns the size of the underlying byte slice, including the bytes already read.
+// Use Remaining for the number of unread bytes.
 func (b *Buffer) Size() int {
 	return len(b.b)
 }
 
-// Bytes returns the underlying byte slice.
+// Bytes returns the unread portion of the underlying byte slice.
 func (b *Buffer) Bytes() []byte {
-	return b.b
+	return b.b[b.off:]
 }
 
-// BytesPointer returns a pointer to the underlying byte slice.
+// BytesPointer returns a pointer to the underlying byte slice, including the bytes already read.
+// The read cursor is an offset into this slice, so Rewind should be called if it is shortened through the pointer.
 func (b *Buffer) BytesPointer() *[]byte {
 	return &b.b
 }
+
+// Offset returns the position of the read cursor from the start of the underlying byte slice.
+func (b *Buffer) Offset() int {
+	return b.off
+}
+
+// BaseOffset returns the offset of the start of b in the outermost Buffer it was read from
+// (eg.: with ReadLengthPrefixed or ReadASN1), 0 if b was not read from an other Buffer.
+// The offsets reported in the *DecodeError values of b are relative to the outermost Buffer.
+func (b *Buffer) BaseOffset() int {
+	return b.base
+}
+
+// Remaining returns the number of unread bytes in b.
+func (b *Buffer) Remaining() int {
+	return len(b.b) - b.off
+}
+
+// Seek sets the read cursor for the next read to offset, interpreted according to whence:
+// io.SeekStart means relative to the start of the underlying byte slice,
+// io.SeekCurrent means relative to the current cursor, and io.SeekEnd means relative to the end.
+// Seek returns the new offset relative to the start of the underlying byte slice.
+// Seeking to a position before the start or after the end of the slice is an error.
+func (b *Buffer) Seek(offset int64, whence int) (int64, error) {
+
+	var abs int64
+
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = int64(b.off) + offset
+	case io.SeekEnd:
+		abs = int64(len(b.b)) + offset
+	default:
+		return 0, errors.New("bytebuilder.Buffer.Seek: invalid whence")
+	}
+
+	if abs < 0 || abs > int64(len(b.b)) {
+		return 0, errors.New("bytebuilder.Buffer.Seek: position out of range")
+	}
+
+	b.off = int(abs)
+
+	return abs, nil
+}
+
+// Rewind moves the read cursor back to the start of the underlying byte slice.
+func (b *Buffer) Rewind() {
+	b.off = 0
+}
+
+// Unread moves the read cursor back by n bytes.
+// Returns whether it was successful.
+func (b *Buffer) Unread(n int) bool {
+
+	if n < 0 || n > b.off {
+		return false
+	}
+
+	b.off -= n
+
+	return true
+}
@@ -0,0 +1,79 @@
+package bytebuilder
+
+import (
+	"io"
+	"testing"
+)
+
+func TestBufferCursor(t *testing.T) {
+
+	b := NewBuffer([]byte{1, 2, 3, 4, 5})
+
+	if v, ok := b.ReadUint16(); !ok || v != 0x0102 {
+		t.Fatalf("ReadUint16 = %#x, %t", v, ok)
+	}
+
+	if b.Offset() != 2 || b.Remaining() != 3 || b.Size() != 5 || len(b.Bytes()) != 3 {
+		t.Fatalf("Offset = %d, Remaining = %d, Size = %d, Bytes = % x", b.Offset(), b.Remaining(), b.Size(), b.Bytes())
+	}
+
+	if !b.Unread(1) || b.Offset() != 1 {
+		t.Fatalf("Offset = %d after Unread(1), want 1", b.Offset())
+	}
+
+	if b.Unread(2) || b.Offset() != 1 {
+		t.Fatalf("Unread beyond the start succeeded, Offset = %d", b.Offset())
+	}
+
+	// A failed read does not move the cursor.
+	if _, ok := b.ReadUint64(); ok || b.Offset() != 1 {
+		t.Fatalf("ReadUint64 succeeded or moved the cursor to %d", b.Offset())
+	}
+
+	b.Rewind()
+
+	if v, ok := b.ReadUint8(); !ok || v != 1 {
+		t.Fatalf("ReadUint8 after Rewind = %d, %t", v, ok)
+	}
+
+	// The pointer gives the whole slice, including the bytes already read.
+	if p := b.BytesPointer(); len(*p) != 5 || b.Offset() != 1 {
+		t.Fatalf("BytesPointer has %d bytes, Offset = %d", len(*p), b.Offset())
+	}
+}
+
+func TestBufferSeek(t *testing.T) {
+
+	tests := []struct {
+		offset int64
+		whence int
+		want   int64
+		ok     bool
+	}{
+		{0, io.SeekStart, 0, true},
+		{5, io.SeekStart, 5, true},
+		{6, io.SeekStart, 0, false},
+		{-1, io.SeekStart, 0, false},
+		{1, io.SeekCurrent, 3, true},
+		{-3, io.SeekCurrent, 0, false},
+		{-1, io.SeekEnd, 4, true},
+		{1, io.SeekEnd, 0, false},
+		{0, 3, 0, false},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBuffer([]byte{1, 2, 3, 4, 5})
+		b.Skip(2)
+
+		v, err := b.Seek(tt.offset, tt.whence)
+
+		if tt.ok != (err == nil) || tt.ok && (v != tt.want || int64(b.Offset()) != tt.want) {
+			t.Fatalf("Seek(%d, %d) = %d, %v, want %d", tt.offset, tt.whence, v, err, tt.want)
+		}
+
+		if !tt.ok && b.Offset() != 2 {
+			t.Fatalf("failed Seek(%d, %d) moved the cursor to %d", tt.offset, tt.whence, b.Offset())
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package bytebuilder
+
+import (
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+	"net/netip"
+)
+
+var (
+	// ErrInvalidRange is returned when a checksum region or field is not inside the Buffer.
+	ErrInvalidRange = errors.New("invalid range")
+
+	// ErrChecksumMismatch is returned when a stored checksum does not match the computed one.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// Checksum is a checksum or CRC algorithm.
+type Checksum byte
+
+const (
+	ChecksumInternet    Checksum = iota // RFC 1071 Internet checksum (16 bit, always big-endian)
+	ChecksumCRC16CCITT                  // CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF)
+	ChecksumCRC16Modbus                 // CRC-16/MODBUS (reflected poly 0x8005, init 0xFFFF)
+	ChecksumCRC16X25                    // CRC-16/X-25 (reflected poly 0x1021, init 0xFFFF, xorout 0xFFFF)
+	ChecksumCRC32                       // CRC-32/IEEE as used by Ethernet, zlib and PNG
+	ChecksumCRC32C                      // CRC-32C (Castagnoli) as used by iSCSI and SCTP
+	ChecksumAdler32                     // Adler-32 as used by zlib
+)
+
+// Protocol numbers used by the pseudo-header of the transport checksums.
+const (
+	ProtocolTCP uint8 = 6
+	ProtocolUDP uint8 = 17
+)
+
+var (
+	crc16CCITTTable = makeCRC16Table(0x1021)
+	crc16Table      = makeCRC16ReflectedTable(0xa001)
+	crc16X25Table   = makeCRC16ReflectedTable(0x8408)
+	crc32CTable     = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// Size returns the length of the checksum in bytes, 0 if c is unknown.
+func (c Checksum) Size() int {
+
+	switch c {
+	case ChecksumInternet, ChecksumCRC16CCITT, ChecksumCRC16Modbus, ChecksumCRC16X25:
+		return 2
+	case ChecksumCRC32, ChecksumCRC32C, ChecksumAdler32:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Sum returns the checksum of data.
+// The 16 bit checksums are returned in the low 16 bits. Sum returns 0 if c is unknown.
+func (c Checksum) Sum(data []byte) uint32 {
+
+	switch c {
+	case ChecksumInternet:
+		return uint32(InternetChecksum(data))
+	case ChecksumCRC16CCITT:
+		return uint32(CRC16CCITT(data))
+	case ChecksumCRC16Modbus:
+		return uint32(CRC16Modbus(data))
+	case ChecksumCRC16X25:
+		return uint32(CRC16X25(data))
+	case ChecksumCRC32:
+		return crc32.ChecksumIEEE(data)
+	case ChecksumCRC32C:
+		return crc32.Checksum(data, crc32CTable)
+	case ChecksumAdler32:
+		return adler32.Checksum(data)
+	default:
+		return 0
+	}
+}
+
+func (c Checksum) String() string {
+
+	switch c {
+	case ChecksumInternet:
+		return "Internet"
+	case ChecksumCRC16CCITT:
+		return "CRC-16/CCITT"
+	case ChecksumCRC16Modbus:
+		return "CRC-16/MODBUS"
+	case ChecksumCRC16X25:
+		return "CRC-16/X-25"
+	case ChecksumCRC32:
+		return "CRC-32"
+	case ChecksumCRC32C:
+		return "CRC-32C"
+	case ChecksumAdler32:
+		return "Adler-32"
+	default:
+		return fmt.Sprintf("Checksum(%d)", c)
+	}
+}
+
+// InternetChecksum returns the RFC 1071 Internet checksum of data:
+// the one's complement of the one's complement sum of the big-endian 16 bit words.
+// An odd trailing byte is padded with zero.
+func InternetChecksum(data []byte) uint16 {
+	return ^foldSum(onesSum(0, data))
+}
+
+// InternetChecksumPseudo returns the Internet checksum of data prefixed with the TCP/UDP pseudo-header
+// built from src, dst, proto and the length of data.
+// The IPv4 pseudo-header (RFC 768, RFC 793) is used when both addresses are IPv4,
+// the IPv6 pseudo-header (RFC 8200) is used when both addresses are IPv6 (including IPv4-mapped).
+// Returns an error wrapping ErrInvalidAddr if an address is the zero Addr or the families differ,
+// and ErrLengthOverflow if data is too long for the pseudo-header.
+//
+// The result is returned as it is computed. UDP transmits a zero checksum as 0xFFFF, see Buffer.PutPseudoChecksum.
+func InternetChecksumPseudo(src, dst netip.Addr, proto uint8, data []byte) (uint16, error) {
+
+	sum, err := pseudoSum(src, dst, proto, len(data), "InternetChecksumPseudo")
+	if err != nil {
+		return 0, err
+	}
+
+	return ^foldSum(onesSum(sum, data)), nil
+}
+
+// CRC16CCITT returns the CRC-16/CCITT-FALSE of data (poly 0x1021, init 0xFFFF, not reflected, no final XOR).
+// The check value of "123456789" is 0x29B1.
+func CRC16CCITT(data []byte) uint16 {
+	return crc16Update(crc16CCITTTable, 0xffff, data)
+}
+
+// CRC16Modbus returns the CRC-16/MODBUS of data (poly 0x8005 reflected, init 0xFFFF, no final XOR).
+// The check value of "123456789" is 0x4B37. Modbus RTU transmits the CRC in little-endian order.
+func CRC16Modbus(data []byte) uint16 {
+	return crc16Reflected(crc16Table, 0xffff, data)
+}
+
+// CRC16X25 returns the CRC-16/X-25 of data (poly 0x1021 reflected, init 0xFFFF, final XOR 0xFFFF),
+// the frame check sequence of HDLC and PPP.
+// The check value of "123456789" is 0x906E. X.25 transmits the CRC in little-endian order.
+func CRC16X25(data []byte) uint16 {
+	return ^crc16Reflected(crc16X25Table, 0xffff, data)
+}
+
+// CRC32 returns the CRC-32/IEEE of data.
+func CRC32(data []byte) uint32 {
+	return crc32.ChecksumIEEE(data)
+}
+
+// CRC32C returns the CRC-32C (Castagnoli) of data.
+func CRC32C(data []byte) uint32 {
+	return crc32.Checksum(data, crc32CTable)
+}
+
+// Adler32 returns the Adler-32 checksum of data.
+func Adler32(data []byte) uint32 {
+	return adler32.Checksum(data)
+}
+
+// onesSum adds the big-endian 16 bit words of data to sum.
+// The carries are kept in the high bits and folded by foldSum.
+func onesSum(sum uint64, data []byte) uint64 {
+
+	for len(data) >= 2 {
+		sum += uint64(data[0])<<8 | uint64(data[1])
+		data = data[2:]
+	}
+
+	if len(data) == 1 {
+		sum += uint64(data[0]) << 8
+	}
+
+	return sum
+}
+
+// onesSumAt is like onesSum, but data starts at an odd offset of the summed bytes if odd is true.
+func onesSumAt(sum uint64, data []byte, odd bool) uint64 {
+
+	if odd && len(data) > 0 {
+		sum += uint64(data[0])
+		data = data[1:]
+	}
+
+	return onesSum(sum, data)
+}
+
+// foldSum folds the carries of sum into 16 bits.
+func foldSum(sum uint64) uint16 {
+
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+
+	return uint16(sum)
+}
+
+// pseudoSum returns the one's complement sum of the TCP/UDP pseudo-header.
+func pseudoSum(src, dst netip.Addr, proto uint8, n int, op string) (uint64, error) {
+
+	if !src.IsValid() || !dst.IsValid() || src.Is4() != dst.Is4() {
+		return 0, &EncodeError{Op: op, Err: ErrInvalidAddr}
+	}
+
+	if src.Is4() && n > 0xffff || uint64(n) > 0xffffffff {
+		return 0, &EncodeError{Op: op, Length: n, Err: ErrLengthOverflow}
+	}
+
+	sum := onesSum(0, src.AsSlice())
+	sum = onesSum(sum, dst.AsSlice())
+
+	return sum + uint64(proto) + uint64(n)>>16 + uint64(n)&0xffff, nil
+}
+
+// makeCRC16Table returns the lookup table of the not reflected CRC-16 with polynomial poly.
+func makeCRC16Table(poly uint16) *[256]uint16 {
+
+	t := new([256]uint16)
+
+	for i := range t {
+		crc := uint16(i) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		t[i] = crc
+	}
+
+	return t
+}
+
+// makeCRC16ReflectedTable returns the lookup table of the reflected CRC-16 with the bit reversed polynomial poly.
+func makeCRC16ReflectedTable(poly uint16) *[256]uint16 {
+
+	t := new([256]uint16)
+
+	for i := range t {
+		crc := uint16(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ poly
+			} else {
+				crc >>= 1
+			}
+		}
+		t[i] = crc
+	}
+
+	return t
+}
+
+// crc16Update updates crc with data using the not reflected table t.
+func crc16Update(t *[256]uint16, crc uint16, data []byte) uint16 {
+
+	for _, v := range data {
+		crc = crc<<8 ^ t[byte(crc>>8)^v]
+	}
+
+	return crc
+}
+
+// crc16Reflected updates crc with data using the reflected table t.
+func crc16Reflected(t *[256]uint16, crc uint16, data []byte) uint16 {
+
+	for _, v := range data {
+		crc = crc>>8 ^ t[byte(crc)^v]
+	}
+
+	return crc
+}
+
+// Regions and checksum fields are given as offsets from the start of the underlying byte slice,
+// the same way as Offset and Seek. Mark returns the offset of the next write, so a region
+// is usually marked with Mark before and after writing it.
+// Reading does not move them, but shortening the underlying byte slice through BytesPointer invalidates the offsets after its new end.
+//
+// The checksums are stored in the byte order of the Buffer, except the Internet checksum
+// which is always big-endian.
+// When the checksum field is inside the region, it is treated as zero while the checksum is computed.
+
+// Mark returns the offset of the next write from the start of the underlying byte slice.
+func (b *Buffer) Mark() int {
+	return len(b.b)
+}
+
+// Checksum returns the checksum c of the region b[start:end].
+// Returns a *DecodeError wrapping ErrInvalidRange if the region is not inside b.
+func (b *Buffer) Checksum(c Checksum, start, end int) (uint32, error) {
+
+	if !b.validRegion(c, start, end, start, 0) {
+		return 0, regionError(b, start, end, "Checksum")
+	}
+
+	return c.Sum(b.b[start:end]), nil
+}
+
+// WriteChecksum appends the checksum c of the region from start to the end of b.
+// Returns an *EncodeError wrapping ErrInvalidRange if the region is not inside b.
+func (b *Buffer) WriteChecksum(c Checksum, start int) error {
+
+	end := len(b.b)
+
+	if !b.validRegion(c, start, end, start, 0) {
+		return &EncodeError{Op: "WriteChecksum", Err: ErrInvalidRange}
+	}
+
+	b.b = append(b.b, make([]byte, c.Size())...)
+	b.putChecksum(c, end, c.Sum(b.b[start:end]))
+
+	return nil
+}
+
+// PutChecksum computes the checksum c of the region b[start:end] and stores it at offset at.
+// Returns an *EncodeError wrapping ErrInvalidRange if the region or the field is not inside b.
+func (b *Buffer) PutChecksum(c Checksum, start, end, at int) error {
+
+	if !b.validRegion(c, start, end, at, c.Size()) {
+		return &EncodeError{Op: "PutChecksum", Err: ErrInvalidRange}
+	}
+
+	b.putChecksum(c, at, b.regionSum(c, start, end, at, 0))
+
+	return nil
+}
+
+// VerifyChecksum compares the checksum stored at offset at with the checksum c of the region b[start:end].
+// Returns a *DecodeError wrapping ErrChecksumMismatch if they differ,
+// or ErrInvalidRange if the region or the field is not inside b.
+func (b *Buffer) VerifyChecksum(c Checksum, start, end, at int) error {
+
+	if !b.validRegion(c, start, end, at, c.Size()) {
+		return regionError(b, start, end, "VerifyChecksum")
+	}
+
+	if b.getChecksum(c, at) != b.regionSum(c, start, end, at, 0) {
+		return &DecodeError{Op: "VerifyChecksum", Offset: b.base + at, Err: ErrChecksumMismatch}
+	}
+
+	return nil
+}
+
+// PutPseudoChecksum computes the TCP/UDP checksum of the segment b[start:end]
+// with the pseudo-header built from src, dst and proto, and stores it big-endian at offset at.
+// If proto is ProtocolUDP, a zero checksum is stored as 0xFFFF.
+// Returns an *EncodeError wrapping ErrInvalidRange, ErrInvalidAddr or ErrLengthOverflow.
+func (b *Buffer) PutPseudoChecksum(src, dst netip.Addr, proto uint8, start, end, at int) error {
+
+	if !b.validRegion(ChecksumInternet, start, end, at, 2) {
+		return &EncodeError{Op: "PutPseudoChecksum", Err: ErrInvalidRange}
+	}
+
+	sum, err := pseudoSum(src, dst, proto, end-start, "PutPseudoChecksum")
+	if err != nil {
+		return err
+	}
+
+	v := b.regionSum(ChecksumInternet, start, end, at, sum)
+
+	if v == 0 && proto == ProtocolUDP {
+		v = 0xffff
+	}
+
+	b.putChecksum(ChecksumInternet, at, v)
+
+	return nil
+}
+
+// VerifyPseudoChecksum compares the TCP/UDP checksum stored at offset at with the checksum
+// of the segment b[start:end] with the pseudo-header built from src, dst and proto.
+// A zero UDP checksum over IPv4 means that the sender did not compute it, it is accepted.
+// Returns a *DecodeError wrapping ErrChecksumMismatch if they differ,
+// or ErrInvalidRange if the region or the field is not inside b,
+// or an *EncodeError if the pseudo-header can not be built.
+func (b *Buffer) VerifyPseudoChecksum(src, dst netip.Addr, proto uint8, start, end, at int) error {
+
+	if !b.validRegion(ChecksumInternet, start, end, at, 2) {
+		return regionError(b, start, end, "VerifyPseudoChecksum")
+	}
+
+	sum, err := pseudoSum(src, dst, proto, end-start, "VerifyPseudoChecksum")
+	if err != nil {
+		return err
+	}
+
+	stored := b.getChecksum(ChecksumInternet, at)
+
+	if stored == 0 && proto == ProtocolUDP && src.Is4() {
+		return nil
+	}
+
+	v := b.regionSum(ChecksumInternet, start, end, at, sum)
+
+	if v == 0 && proto == ProtocolUDP {
+		v = 0xffff
+	}
+
+	if stored != v {
+		return &DecodeError{Op: "VerifyPseudoChecksum", Offset: b.base + at, Err: ErrChecksumMismatch}
+	}
+
+	return nil
+}
+
+// validRegion returns whether c is known and b[start:end] and the size bytes long field at offset at are inside b.
+func (b *Buffer) validRegion(c Checksum, start, end, at, size int) bool {
+	return c.Size() > 0 && start >= 0 && start <= end && end <= len(b.b) && at >= 0 && at <= len(b.b)-size
+}
+
+// regionError returns a *DecodeError wrapping ErrInvalidRange for the region b[start:end].
+func regionError(b *Buffer, start, end int, op string) error {
+	return &DecodeError{Op: op, Offset: b.base + start, Want: end - start, Have: len(b.b) - start, Err: ErrInvalidRange}
+}
+
+// regionSum returns the checksum c of b[start:end] as if the field at offset at were zero, without modifying b.
+// sum is the one's complement sum of the pseudo-header, used only by the Internet checksum.
+func (b *Buffer) regionSum(c Checksum, start, end, at int, sum uint64) uint32 {
+
+	// The region is computed in three parts: before the field, the field as zeros and after the field.
+	zs, ze := clip(at, start, end), clip(at+c.Size(), start, end)
+
+	var zero [4]byte
+
+	parts := [3][]byte{b.b[start:zs], zero[:ze-zs], b.b[ze:end]}
+
+	switch c {
+	case ChecksumInternet:
+		n := 0
+		for _, p := range parts {
+			sum = onesSumAt(sum, p, n%2 == 1)
+			n += len(p)
+		}
+		return uint32(^foldSum(sum))
+	case ChecksumCRC16CCITT, ChecksumCRC16Modbus, ChecksumCRC16X25:
+		crc := uint16(0xffff)
+		for _, p := range parts {
+			switch c {
+			case ChecksumCRC16CCITT:
+				crc = crc16Update(crc16CCITTTable, crc, p)
+			case ChecksumCRC16Modbus:
+				crc = crc16Reflected(crc16Table, crc, p)
+			default:
+				crc = crc16Reflected(crc16X25Table, crc, p)
+			}
+		}
+		if c == ChecksumCRC16X25 {
+			crc = ^crc
+		}
+		return uint32(crc)
+	case ChecksumCRC32, ChecksumCRC32C:
+		t := crc32.IEEETable
+		if c == ChecksumCRC32C {
+			t = crc32CTable
+		}
+		var crc uint32
+		for _, p := range parts {
+			crc = crc32.Update(crc, t, p)
+		}
+		return crc
+	default:
+		h := adler32.New()
+		for _, p := range parts {
+			h.Write(p)
+		}
+		return h.Sum32()
+	}
+}
+
+// clip returns v limited to the range [lo, hi].
+func clip(v, lo, hi int) int {
+
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
+
+// putChecksum stores v at offset at in c.Size() bytes.
+func (b *Buffer) putChecksum(c Checksum, at int, v uint32) {
+	putUint(b.b[at:at+c.Size()], uint64(v), b.little && c != ChecksumInternet)
+}
+
+// getChecksum returns the c.Size() bytes long checksum stored at offset at.
+func (b *Buffer) getChecksum(c Checksum, at int) uint32 {
+
+	var v uint32
+
+	field := b.b[at : at+c.Size()]
+
+	for i := range field {
+		if b.little && c != ChecksumInternet {
+			v |= uint32(field[i]) << (8 * i)
+		} else {
+			v = v<<8 | uint32(field[i])
+		}
+	}
+
+	return v
+}
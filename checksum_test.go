@@ -0,0 +1,284 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"sync"
+	"testing"
+)
+
+func TestChecksumCheckValues(t *testing.T) {
+
+	data := []byte("123456789")
+
+	tests := []struct {
+		c    Checksum
+		want uint32
+	}{
+		{ChecksumCRC16CCITT, 0x29b1},
+		{ChecksumCRC16Modbus, 0x4b37},
+		{ChecksumCRC16X25, 0x906e},
+		{ChecksumCRC32, 0xcbf43926},
+		{ChecksumCRC32C, 0xe3069283},
+		{ChecksumAdler32, 0x091e01de},
+	}
+
+	for _, tt := range tests {
+		if v := tt.c.Sum(data); v != tt.want {
+			t.Fatalf("%s = %#x, want %#x", tt.c, v, tt.want)
+		}
+	}
+}
+
+func TestPutChecksum(t *testing.T) {
+
+	// The field is inside the region at every alignment, the result must equal the checksum with a zeroed field.
+	for c := ChecksumInternet; c <= ChecksumAdler32; c++ {
+		for _, e := range []Endianness{BigEndian, LittleEndian} {
+			for at := 0; at+c.Size() <= 11; at++ {
+
+				data := []byte{0x45, 0x00, 0x00, 0x1c, 0xab, 0xcd, 0x40, 0x00, 0x40, 0x11, 0x7f}
+				zeroed := append([]byte(nil), data...)
+
+				for i := 0; i < c.Size(); i++ {
+					zeroed[at+i] = 0
+				}
+
+				b := NewBufferWithEndianness(data, e)
+
+				if err := b.PutChecksum(c, 1, len(data), at); err != nil {
+					t.Fatal(err)
+				}
+
+				z := NewBufferWithEndianness(zeroed, e)
+				want, _ := z.Checksum(c, 1, len(zeroed))
+
+				if v := b.getChecksum(c, at); v != want {
+					t.Fatalf("%s %s at %d: stored %#x, want %#x", c, e, at, v, want)
+				}
+
+				if err := b.VerifyChecksum(c, 1, len(data), at); err != nil {
+					t.Fatalf("%s %s at %d: %s", c, e, at, err)
+				}
+
+				b.b[len(data)-1] ^= 1
+
+				if err := b.VerifyChecksum(c, 1, len(data), at); !errors.Is(err, ErrChecksumMismatch) {
+					t.Fatalf("%s %s at %d: VerifyChecksum error = %v, want %v", c, e, at, err, ErrChecksumMismatch)
+				}
+			}
+		}
+	}
+}
+
+func TestInternetChecksum(t *testing.T) {
+
+	// RFC 1071 section 3 example, the sum is 0xDDF2.
+	if v := InternetChecksum([]byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}); v != 0x220d {
+		t.Fatalf("InternetChecksum = %#x, want 0x220d", v)
+	}
+
+	// An odd trailing byte is padded with zero.
+	if v, w := InternetChecksum([]byte{0x01, 0x02, 0x03}), InternetChecksum([]byte{0x01, 0x02, 0x03, 0x00}); v != w {
+		t.Fatalf("InternetChecksum of odd length = %#x, want %#x", v, w)
+	}
+
+	// The Internet checksum is always big-endian, the others are in the byte order of the Buffer.
+	b := NewBufferWithEndianness([]byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}, LittleEndian)
+
+	if err := b.WriteChecksum(ChecksumInternet, 0); err != nil || b.b[8] != 0x22 || b.b[9] != 0x0d {
+		t.Fatalf("WriteChecksum = % x, %v", b.b[8:], err)
+	}
+
+	if err := b.WriteChecksum(ChecksumCRC32, 0); err != nil || LittleEndian.Uint32(b.b[10:]) != CRC32(b.b[:10]) {
+		t.Fatalf("WriteChecksum = % x, %v", b.b[10:], err)
+	}
+}
+
+func TestPseudoChecksum(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		src, dst netip.Addr
+	}{
+		{"IPv4", netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("198.51.100.2")},
+		{"IPv6", netip.MustParseAddr("2001:db8::1"), netip.MustParseAddr("2001:db8::2")},
+	}
+
+	for _, tt := range tests {
+
+		// UDP header (checksum at offset 6) and payload, the last two bytes are adjusted below.
+		data := []byte{0x04, 0xd2, 0x00, 0x35, 0x00, 0x0c, 0x00, 0x00, 'a', 'b', 0x00, 0x00}
+
+		sum, err := InternetChecksumPseudo(tt.src, tt.dst, ProtocolUDP, data)
+		if err != nil {
+			t.Fatalf("%s: InternetChecksumPseudo: %s", tt.name, err)
+		}
+
+		b := NewBuffer(append([]byte(nil), data...))
+
+		if err := b.PutPseudoChecksum(tt.src, tt.dst, ProtocolUDP, 0, 12, 6); err != nil || BigEndian.Uint16(b.b[6:]) != sum {
+			t.Fatalf("%s: PutPseudoChecksum stored %#x, %v, want %#x", tt.name, BigEndian.Uint16(b.b[6:]), err, sum)
+		}
+
+		// Adjust the payload so the computed checksum is zero, UDP transmits it as 0xFFFF.
+		BigEndian.PutUint16(data[10:], sum)
+
+		if v, _ := InternetChecksumPseudo(tt.src, tt.dst, ProtocolUDP, data); v != 0 {
+			t.Fatalf("%s: adjusted checksum = %#x, want 0", tt.name, v)
+		}
+
+		b = NewBuffer(append([]byte(nil), data...))
+
+		if err := b.PutPseudoChecksum(tt.src, tt.dst, ProtocolUDP, 0, 12, 6); err != nil || BigEndian.Uint16(b.b[6:]) != 0xffff {
+			t.Fatalf("%s: PutPseudoChecksum stored %#x, %v, want 0xffff", tt.name, BigEndian.Uint16(b.b[6:]), err)
+		}
+
+		if err := b.VerifyPseudoChecksum(tt.src, tt.dst, ProtocolUDP, 0, 12, 6); err != nil {
+			t.Fatalf("%s: VerifyPseudoChecksum: %s", tt.name, err)
+		}
+
+		// TCP stores the zero checksum as it is.
+		if err := b.PutPseudoChecksum(tt.src, tt.dst, ProtocolTCP, 0, 12, 6); err != nil {
+			t.Fatalf("%s: PutPseudoChecksum TCP: %s", tt.name, err)
+		}
+
+		if err := b.VerifyPseudoChecksum(tt.src, tt.dst, ProtocolTCP, 0, 12, 6); err != nil {
+			t.Fatalf("%s: VerifyPseudoChecksum TCP: %s", tt.name, err)
+		}
+
+		// A zero UDP checksum means no checksum over IPv4 only.
+		BigEndian.PutUint16(b.b[6:], 0)
+
+		err = b.VerifyPseudoChecksum(tt.src, tt.dst, ProtocolUDP, 0, 12, 6)
+		if tt.src.Is4() != (err == nil) {
+			t.Fatalf("%s: VerifyPseudoChecksum of zero checksum = %v", tt.name, err)
+		}
+	}
+}
+
+func TestChecksumErrors(t *testing.T) {
+
+	src, dst := netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("192.0.2.2")
+
+	tests := []struct {
+		name   string
+		verify func(b *Buffer) error
+		op     string
+		offset int
+		err    error
+	}{
+		{"Checksum/end", func(b *Buffer) error { _, err := b.Checksum(ChecksumCRC32, 2, 9); return err }, "Checksum", 3, ErrInvalidRange},
+		{"Checksum/reversed", func(b *Buffer) error { _, err := b.Checksum(ChecksumCRC32, 4, 2); return err }, "Checksum", 5, ErrInvalidRange},
+		{"Checksum/negative", func(b *Buffer) error { _, err := b.Checksum(ChecksumCRC32, -1, 2); return err }, "Checksum", 0, ErrInvalidRange},
+		{"Checksum/unknown", func(b *Buffer) error { _, err := b.Checksum(ChecksumAdler32+1, 0, 8); return err }, "Checksum", 1, ErrInvalidRange},
+		{"VerifyChecksum/field", func(b *Buffer) error { return b.VerifyChecksum(ChecksumCRC32, 0, 4, 5) }, "VerifyChecksum", 1, ErrInvalidRange},
+		{"VerifyChecksum/mismatch", func(b *Buffer) error { return b.VerifyChecksum(ChecksumCRC32, 0, 4, 4) }, "VerifyChecksum", 5, ErrChecksumMismatch},
+		{"VerifyPseudoChecksum/field", func(b *Buffer) error { return b.VerifyPseudoChecksum(src, dst, ProtocolUDP, 0, 8, 7) }, "VerifyPseudoChecksum", 1, ErrInvalidRange},
+		{"VerifyPseudoChecksum/mismatch", func(b *Buffer) error { return b.VerifyPseudoChecksum(src, dst, ProtocolTCP, 0, 8, 6) }, "VerifyPseudoChecksum", 7, ErrChecksumMismatch},
+	}
+
+	for _, tt := range tests {
+
+		// The region is checked in a child, so the offsets of the errors include its base.
+		p := NewBuffer([]byte{8, 1, 2, 3, 4, 5, 6, 7, 8})
+
+		var b Buffer
+
+		if err := p.ReadLengthPrefixed(8, &b); err != nil {
+			t.Fatalf("%s: ReadLengthPrefixed: %s", tt.name, err)
+		}
+
+		err := tt.verify(&b)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+	}
+}
+
+func TestPutChecksumErrors(t *testing.T) {
+
+	src, dst := netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("192.0.2.2")
+
+	tests := []struct {
+		name string
+		put  func(b *Buffer) error
+		op   string
+		err  error
+	}{
+		{"WriteChecksum/start", func(b *Buffer) error { return b.WriteChecksum(ChecksumCRC32, 9) }, "WriteChecksum", ErrInvalidRange},
+		{"WriteChecksum/unknown", func(b *Buffer) error { return b.WriteChecksum(ChecksumAdler32+1, 0) }, "WriteChecksum", ErrInvalidRange},
+		{"PutChecksum/field", func(b *Buffer) error { return b.PutChecksum(ChecksumCRC32, 0, 8, 5) }, "PutChecksum", ErrInvalidRange},
+		{"PutChecksum/end", func(b *Buffer) error { return b.PutChecksum(ChecksumCRC16CCITT, 0, 9, 0) }, "PutChecksum", ErrInvalidRange},
+		{"PutPseudoChecksum/field", func(b *Buffer) error { return b.PutPseudoChecksum(src, dst, ProtocolUDP, 0, 8, 7) }, "PutPseudoChecksum", ErrInvalidRange},
+		{"PutPseudoChecksum/zero", func(b *Buffer) error { return b.PutPseudoChecksum(netip.Addr{}, dst, ProtocolUDP, 0, 8, 6) }, "PutPseudoChecksum", ErrInvalidAddr},
+		{"PutPseudoChecksum/families", func(b *Buffer) error {
+			return b.PutPseudoChecksum(src, netip.MustParseAddr("2001:db8::1"), ProtocolUDP, 0, 8, 6)
+		}, "PutPseudoChecksum", ErrInvalidAddr},
+		{"VerifyPseudoChecksum/families", func(b *Buffer) error {
+			return b.VerifyPseudoChecksum(src, netip.MustParseAddr("::ffff:192.0.2.2"), ProtocolUDP, 0, 8, 6)
+		}, "VerifyPseudoChecksum", ErrInvalidAddr},
+	}
+
+	for _, tt := range tests {
+
+		data := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+		b := NewBuffer(append([]byte(nil), data...))
+
+		err := tt.put(&b)
+
+		var ee *EncodeError
+
+		if !errors.As(err, &ee) || ee.Op != tt.op || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s: %v", tt.name, err, tt.op, tt.err)
+		}
+
+		if !bytes.Equal(b.b, data) {
+			t.Fatalf("%s: b modified to % x", tt.name, b.b)
+		}
+	}
+
+	// The IPv4 pseudo-header has a 16-bit length.
+	b := NewBuffer(make([]byte, 0x10000))
+
+	if err := b.PutPseudoChecksum(src, dst, ProtocolUDP, 0, 0x10000, 6); !errors.Is(err, ErrLengthOverflow) {
+		t.Fatalf("PutPseudoChecksum of 64 KiB = %v, want ErrLengthOverflow", err)
+	}
+
+	if err := b.PutPseudoChecksum(netip.MustParseAddr("2001:db8::1"), netip.MustParseAddr("2001:db8::2"), ProtocolUDP, 0, 0x10000, 6); err != nil {
+		t.Fatalf("PutPseudoChecksum of 64 KiB over IPv6: %s", err)
+	}
+}
+
+// TestVerifyChecksumConcurrent verifies the same Buffer from several goroutines.
+// The verification must not write into the Buffer, the race detector reports it otherwise.
+
+func TestVerifyChecksumConcurrent(t *testing.T) {
+
+	src, dst := netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("192.0.2.2")
+
+	// UDP header (checksum at offset 6) and payload
+	b := NewBuffer([]byte{0x04, 0xd2, 0x00, 0x35, 0x00, 0x0b, 0x00, 0x00, 'a', 'b', 'c'})
+
+	if err := b.PutPseudoChecksum(src, dst, ProtocolUDP, 0, 11, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.VerifyPseudoChecksum(src, dst, ProtocolUDP, 0, 11, 6); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,1148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// kind is the encoding class of a Go type.
+type kind int
+
+const (
+	kindBool kind = iota
+	kindInt
+	kindUint
+	kindFloat
+	kindString
+	kindBytes     // []byte
+	kindByteArray // [N]byte
+	kindSlice
+	kindArray
+	kindPointer
+	kindCustom // Implements EncodeTo, DecodeFrom and EncodedSize
+)
+
+// fieldType is the resolved type of a field.
+type fieldType struct {
+	kind kind
+	name string   // Go type expression used in conversions
+	size int      // Size of integers and floats in bytes
+	elem ast.Expr // Element type of slices, arrays and pointers
+	len  string   // Length of arrays
+}
+
+// basicTypes maps the predeclared types to their encoding.
+var basicTypes = map[string]fieldType{
+	"bool":    {kind: kindBool, size: 1},
+	"int8":    {kind: kindInt, size: 1},
+	"int16":   {kind: kindInt, size: 2},
+	"int32":   {kind: kindInt, size: 4},
+	"rune":    {kind: kindInt, size: 4},
+	"int64":   {kind: kindInt, size: 8},
+	"int":     {kind: kindInt, size: 8},
+	"uint8":   {kind: kindUint, size: 1},
+	"byte":    {kind: kindUint, size: 1},
+	"uint16":  {kind: kindUint, size: 2},
+	"uint32":  {kind: kindUint, size: 4},
+	"uint64":  {kind: kindUint, size: 8},
+	"uint":    {kind: kindUint, size: 8},
+	"float32": {kind: kindFloat, size: 4},
+	"float64": {kind: kindFloat, size: 8},
+	"string":  {kind: kindString},
+}
+
+// bytebuilderPath is the import path of the bytebuilder package.
+const bytebuilderPath = "github.com/g0rbe/go-bytebuilder"
+
+// generator collects the generated methods.
+type generator struct {
+	types    map[string]ast.Expr // Type declarations of the package
+	methods  map[string]bool     // Types with an EncodeTo method
+	names    map[string]bool     // Types to generate
+	imports  map[string]bool     // Imports used by the generated code
+	aliases  map[string]string   // Import paths of the named imports by name, empty if the name is ambiguous
+	paths    []string            // Import paths of the imports without a name
+	refs     map[string]pkgRef   // Imported packages referenced by the field types by path
+	importer types.ImporterFrom  // Type checks the imported packages
+	info     *types.Info         // Types of the expressions of the package
+	sizes    types.Sizes         // Memory sizes of the decoded values
+	dir      string              // Directory of the package
+	w        *bytes.Buffer
+	tmp      int
+}
+
+func newGenerator() *generator {
+	return &generator{
+		types:   make(map[string]ast.Expr),
+		methods: make(map[string]bool),
+		names:   make(map[string]bool),
+		imports: map[string]bool{bytebuilderPath: true},
+		aliases: make(map[string]string),
+		refs:    make(map[string]pkgRef),
+		info: &types.Info{
+			Types: make(map[ast.Expr]types.TypeAndValue),
+			Defs:  make(map[*ast.Ident]types.Object),
+			Uses:  make(map[*ast.Ident]types.Object),
+		},
+		sizes: types.SizesFor("gc", "amd64"),
+		w:     new(bytes.Buffer),
+	}
+}
+
+// pkgRef is an imported package referenced by a field type.
+type pkgRef struct {
+	name  string // Name used in the package files
+	alias bool   // The name differs from the package name
+}
+
+// p prints a line of generated code.
+func (g *generator) p(format string, args ...any) {
+	fmt.Fprintf(g.w, format, args...)
+	g.w.WriteByte('\n')
+}
+
+// temp returns a new variable name with prefix.
+func (g *generator) temp(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp-1)
+}
+
+// capture returns the code printed by f instead of appending it to the output.
+func (g *generator) capture(f func() (int, error)) (string, int, error) {
+
+	w := g.w
+	g.w = new(bytes.Buffer)
+
+	n, err := f()
+
+	code := g.w.String()
+	g.w = w
+
+	return code, n, err
+}
+
+// source returns the unformatted source of the generated file.
+func (g *generator) source(pkg string) []byte {
+
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Code generated by bytebuildergen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+
+	// The packages of the field types are imported only if the generated code refers to them
+	// (eg.: in conversions), a field that is encoded by its own methods does not need it.
+	aliases := make(map[string]string)
+
+	for path, ref := range g.refs {
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(ref.name) + `\.`).Match(g.w.Bytes()) {
+			g.imports[path] = true
+			if ref.alias {
+				aliases[path] = ref.name
+			}
+		}
+	}
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		if (imports[i] == bytebuilderPath) != (imports[j] == bytebuilderPath) {
+			return imports[j] == bytebuilderPath
+		}
+		return imports[i] < imports[j]
+	})
+
+	// Standard library imports first, then the bytebuilder package.
+	for _, imp := range imports {
+		if imp == bytebuilderPath {
+			out.WriteString("\n")
+		}
+		if alias, ok := aliases[imp]; ok {
+			fmt.Fprintf(&out, "\t%s %q\n", alias, imp)
+			continue
+		}
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+
+	out.WriteString(")\n")
+	out.Write(g.w.Bytes())
+
+	return out.Bytes()
+}
+
+// field is an encoded field of a struct.
+type field struct {
+	name string
+	typ  ast.Expr
+	tag  bytebuilder.FieldTag
+}
+
+// structFields returns the encoded fields of st in declaration order.
+// Unexported fields and fields tagged with "-" are skipped, like in bytebuilder.Marshal.
+func structFields(name string, st *ast.StructType) ([]field, error) {
+
+	var fields []field
+
+	for _, f := range st.Fields.List {
+
+		var tag string
+
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(s).Get("bb")
+		}
+
+		names := make([]string, 0, len(f.Names))
+
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+
+		// Embedded field
+		if len(names) == 0 {
+			t := f.Type
+			if s, ok := t.(*ast.StarExpr); ok {
+				t = s.X
+			}
+			if s, ok := t.(*ast.SelectorExpr); ok {
+				t = s.Sel
+			}
+			names = append(names, types.ExprString(t))
+		}
+
+		for _, n := range names {
+
+			if !ast.IsExported(n) {
+				continue
+			}
+
+			t, err := bytebuilder.ParseFieldTag(tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, n, err)
+			}
+
+			if t.Skip {
+				continue
+			}
+
+			fields = append(fields, field{name: n, typ: f.Type, tag: t})
+		}
+	}
+
+	return fields, nil
+}
+
+// generateStruct appends the EncodeTo, DecodeFrom and EncodedSize methods of the struct type name.
+func (g *generator) generateStruct(name string, st *ast.StructType) error {
+
+	fields, err := structFields(name, st)
+	if err != nil {
+		return err
+	}
+
+	g.tmp = 0
+
+	g.p("")
+	g.p("// EncodeTo appends the encoding of x to b.")
+	g.p("// If an error is returned, b is not modified.")
+	g.p("func (x *%s) EncodeTo(b *bytebuilder.Buffer) (err error) {", name)
+	g.p("")
+	g.p("start := b.Size()")
+	g.p("")
+	g.p("defer func() {")
+	g.p("if err != nil {")
+	g.p("p := b.BytesPointer()")
+	g.p("*p = (*p)[:start]")
+	g.p("}")
+	g.p("}()")
+	g.p("")
+
+	for _, f := range fields {
+		if err := g.encode("x."+f.name, f.typ, f.tag, strconv.Quote(name+"."+f.name)); err != nil {
+			return fmt.Errorf("%s.%s: %w", name, f.name, err)
+		}
+	}
+
+	g.p("")
+	g.p("return nil")
+	g.p("}")
+
+	g.tmp = 0
+
+	g.p("")
+	g.p("// DecodeFrom decodes x from the read cursor of b.")
+	g.p("func (x *%s) DecodeFrom(b *bytebuilder.Buffer) error {", name)
+	g.p("")
+
+	for _, f := range fields {
+		if err := g.decode("x."+f.name, f.typ, f.tag, "b", strconv.Quote(name+"."+f.name)); err != nil {
+			return fmt.Errorf("%s.%s: %w", name, f.name, err)
+		}
+	}
+
+	g.p("")
+	g.p("return nil")
+	g.p("}")
+
+	g.tmp = 0
+
+	code, n, err := g.capture(func() (int, error) {
+
+		total := 0
+
+		for _, f := range fields {
+
+			k, err := g.size("n", "x."+f.name, f.typ, f.tag)
+			if err != nil {
+				return 0, fmt.Errorf("%s.%s: %w", name, f.name, err)
+			}
+
+			total += k
+		}
+
+		return total, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.p("")
+	g.p("// EncodedSize returns the number of bytes written by EncodeTo.")
+	g.p("func (x *%s) EncodedSize() int {", name)
+	g.p("")
+
+	if code == "" {
+		g.p("return %d", n)
+	} else {
+		g.p("n := %d", n)
+		g.w.WriteString(code)
+		g.p("")
+		g.p("return n")
+	}
+
+	g.p("}")
+
+	return nil
+}
+
+// resolve returns the encoding of the type expression t.
+func (g *generator) resolve(t ast.Expr) (fieldType, error) {
+
+	name := types.ExprString(t)
+
+	switch e := t.(type) {
+	case *ast.Ident:
+
+		if ft, ok := basicTypes[e.Name]; ok {
+			ft.name = name
+			return ft, nil
+		}
+
+		if g.names[e.Name] || g.methods[e.Name] {
+			return fieldType{kind: kindCustom, name: name}, nil
+		}
+
+		u, ok := g.types[e.Name]
+		if !ok {
+			return fieldType{}, fmt.Errorf("unsupported type %s", name)
+		}
+
+		if _, ok := u.(*ast.StructType); ok {
+			return fieldType{kind: kindCustom, name: name}, nil
+		}
+
+		ft, err := g.resolve(u)
+		if err != nil {
+			return ft, err
+		}
+
+		ft.name = name
+
+		return ft, nil
+
+	case *ast.SelectorExpr:
+		return g.resolveImported(e, name)
+
+	case *ast.StarExpr:
+		return fieldType{kind: kindPointer, name: name, elem: e.X}, nil
+
+	case *ast.ArrayType:
+
+		if e.Len == nil {
+			if isByte(e.Elt) {
+				return fieldType{kind: kindBytes, name: name}, nil
+			}
+			return fieldType{kind: kindSlice, name: name, elem: e.Elt}, nil
+		}
+
+		if isByte(e.Elt) {
+			return fieldType{kind: kindByteArray, name: name, len: types.ExprString(e.Len)}, nil
+		}
+
+		return fieldType{kind: kindArray, name: name, elem: e.Elt, len: types.ExprString(e.Len)}, nil
+
+	default:
+		return fieldType{}, fmt.Errorf("unsupported type %s", name)
+	}
+}
+
+// resolveImported returns the encoding of the type e of an other package.
+// Types implementing EncodeTo, DecodeFrom and EncodedSize are encoded by calling them,
+// the other types are encoded by their underlying type if it is a basic type or a byte slice or array.
+func (g *generator) resolveImported(e *ast.SelectorExpr, name string) (fieldType, error) {
+
+	id, ok := e.X.(*ast.Ident)
+	if !ok {
+		return fieldType{}, fmt.Errorf("unsupported type %s", name)
+	}
+
+	path, pkg, err := g.importPackage(id.Name)
+	if err != nil {
+		return fieldType{}, fmt.Errorf("type %s: %w", name, err)
+	}
+
+	obj, ok := pkg.Scope().Lookup(e.Sel.Name).(*types.TypeName)
+	if !ok || !obj.Exported() {
+		return fieldType{}, fmt.Errorf("type %s: %s is not an exported type of %s", name, e.Sel.Name, path)
+	}
+
+	g.refs[path] = pkgRef{name: id.Name, alias: id.Name != pkg.Name()}
+
+	if hasCodec(obj) {
+		return fieldType{kind: kindCustom, name: name}, nil
+	}
+
+	switch u := obj.Type().Underlying().(type) {
+	case *types.Basic:
+		if ft, ok := basicTypes[u.Name()]; ok {
+			ft.name = name
+			return ft, nil
+		}
+	case *types.Slice:
+		if isByteType(u.Elem()) {
+			return fieldType{kind: kindBytes, name: name}, nil
+		}
+	case *types.Array:
+		if isByteType(u.Elem()) {
+			return fieldType{kind: kindByteArray, name: name, len: strconv.FormatInt(u.Len(), 10)}, nil
+		}
+	}
+
+	return fieldType{}, fmt.Errorf("unsupported type %s: it does not implement EncodeTo, DecodeFrom and EncodedSize "+
+		"and its underlying type %s is not a basic type or a byte slice or array", name, obj.Type().Underlying())
+}
+
+// importPackage type checks the package imported with name by the package files.
+func (g *generator) importPackage(name string) (string, *types.Package, error) {
+
+	if path, ok := g.aliases[name]; ok {
+
+		if path == "" {
+			return "", nil, fmt.Errorf("package %s is imported with different paths", name)
+		}
+
+		pkg, err := g.importer.ImportFrom(path, g.dir, 0)
+
+		return path, pkg, err
+	}
+
+	// The name of a package is not always the last element of its path (eg.: "gopkg.in/yaml.v3"),
+	// so the imports without a name are type checked until one matches.
+	var lastErr error
+
+	for _, path := range g.paths {
+
+		pkg, err := g.importer.ImportFrom(path, g.dir, 0)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if pkg.Name() == name {
+			return path, pkg, nil
+		}
+	}
+
+	if lastErr != nil {
+		return "", nil, fmt.Errorf("package %s is not found: %w", name, lastErr)
+	}
+
+	return "", nil, fmt.Errorf("package %s is not imported", name)
+}
+
+// hasCodec returns whether the pointer to the type obj has the EncodeTo, DecodeFrom and EncodedSize methods.
+func hasCodec(obj *types.TypeName) bool {
+
+	ptr := types.NewPointer(obj.Type())
+
+	for _, m := range []string{"EncodeTo", "DecodeFrom", "EncodedSize"} {
+		f, _, _ := types.LookupFieldOrMethod(ptr, true, obj.Pkg(), m)
+		if _, ok := f.(*types.Func); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isByteType returns whether t is the byte type.
+func isByteType(t types.Type) bool {
+
+	b, ok := t.(*types.Basic)
+
+	return ok && b.Kind() == types.Byte
+}
+
+// isByte returns whether t is the byte or uint8 type.
+func isByte(t ast.Expr) bool {
+
+	id, ok := t.(*ast.Ident)
+
+	return ok && (id.Name == "byte" || id.Name == "uint8")
+}
+
+// checkArray returns an error if the tag of an array has a vector option or a different fixed length.
+func checkArray(ft fieldType, tag bytebuilder.FieldTag) error {
+
+	if tag.Vector != 0 {
+		return fmt.Errorf("vector option on array %s", ft.name)
+	}
+
+	if n, err := strconv.Atoi(ft.len); err == nil && tag.Fixed >= 0 && tag.Fixed != n {
+		return fmt.Errorf("fixed option does not match array %s", ft.name)
+	}
+
+	return nil
+}
+
+// fail prints the return of err wrapped with path.
+func (g *generator) fail(path string, err string) {
+	g.p("return bytebuilder.WrapFieldError(%s, %s)", path, err)
+}
+
+// check prints the error check of the call.
+func (g *generator) check(call string, path string) {
+	g.p("if err := %s; err != nil {", call)
+	g.fail(path, "err")
+	g.p("}")
+}
+
+// index returns path extended with the index variable i.
+func (g *generator) index(path string, i string) string {
+
+	g.imports["strconv"] = true
+
+	return strings.TrimSuffix(path, `"`) + `[" + strconv.Itoa(` + i + `) + "]"`
+}
+
+// order returns the byte order part of the method names of tag.
+func order(tag bytebuilder.FieldTag) string {
+
+	switch tag.Order {
+	case bytebuilder.OrderBig:
+		return "Big"
+	case bytebuilder.OrderLittle:
+		return "Little"
+	default:
+		return ""
+	}
+}
+
+// intType returns the Go type used by the methods for integers of size bytes.
+func intType(size int, signed bool) string {
+
+	bits := size * 8
+	if size == 3 {
+		bits = 32
+	}
+
+	if signed {
+		return "int" + strconv.Itoa(bits)
+	}
+
+	return "uint" + strconv.Itoa(bits)
+}
+
+// intMethod returns the name of the method for integers of size bytes without the Read/Write prefix.
+func intMethod(tag bytebuilder.FieldTag, size int, signed bool) string {
+
+	name := "Uint"
+	if signed {
+		name = "Int"
+	}
+
+	if size == 1 {
+		return name + "8"
+	}
+
+	return order(tag) + name + strconv.Itoa(size*8)
+}
+
+// conv returns the conversion of x to typ, or x if it is already typ.
+func conv(typ string, ft fieldType, x string) string {
+
+	if ft.name == typ {
+		return x
+	}
+
+	return typ + "(" + x + ")"
+}
+
+// intSize returns the encoded size of an integer, 0 for varints.
+func intSize(ft fieldType, tag bytebuilder.FieldTag) int {
+
+	if tag.Varint != 0 {
+		return 0
+	}
+
+	if tag.Size > 0 {
+		return tag.Size
+	}
+
+	return ft.size
+}
+
+// bitSize returns the bitSize argument of the vector methods.
+func bitSize(n int) string {
+
+	switch n {
+	case bytebuilder.VarintLength:
+		return "bytebuilder.VarintLength"
+	case bytebuilder.QUICVarintLength:
+		return "bytebuilder.QUICVarintLength"
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// prefixSize returns the size of the length prefix of a vector with bitSize and length n.
+// The returned string is empty for fixed size prefixes.
+func prefixSize(bitSize int, n string) (int, string) {
+
+	switch bitSize {
+	case bytebuilder.VarintLength:
+		return 0, "bytebuilder.UvarintLen(uint64(" + n + "))"
+	case bytebuilder.QUICVarintLength:
+		return 0, "bytebuilder.QUICVarintLen(uint64(" + n + "))"
+	default:
+		return bitSize / 8, ""
+	}
+}
+
+// encode prints the encoding of the expression x with type t.
+func (g *generator) encode(x string, t ast.Expr, tag bytebuilder.FieldTag, path string) error {
+
+	ft, err := g.resolve(t)
+	if err != nil {
+		return err
+	}
+
+	switch ft.kind {
+	case kindBool:
+		g.p("if %s {", x)
+		g.p("b.WriteUint8(1)")
+		g.p("} else {")
+		g.p("b.WriteUint8(0)")
+		g.p("}")
+
+	case kindInt, kindUint:
+		return g.encodeInt(x, ft, tag, path)
+
+	case kindFloat:
+		g.p("b.Write%sFloat%d(%s)", order(tag), ft.size*8, conv("float"+strconv.Itoa(ft.size*8), ft, x))
+
+	case kindString, kindBytes:
+
+		v := x
+		if ft.kind == kindString {
+			v = "[]byte(" + x + ")"
+		}
+
+		switch {
+		case tag.Vector != 0:
+			g.check(fmt.Sprintf("b.WriteVectorE(%s, %s)", v, bitSize(tag.Vector)), path)
+		case tag.Fixed >= 0:
+			g.p("if len(%s) > %d {", x, tag.Fixed)
+			g.fail(path, fmt.Sprintf("&bytebuilder.EncodeError{Op: \"EncodeTo\", Length: len(%s), Err: bytebuilder.ErrLengthOverflow}", x))
+			g.p("}")
+			if ft.kind == kindString {
+				g.p("b.WriteString(%s)", conv("string", ft, x))
+			} else {
+				g.p("b.WriteBytes(%s...)", x)
+			}
+			g.p("b.WriteBytes(make([]byte, %d-len(%s))...)", tag.Fixed, x)
+		default:
+			return fmt.Errorf("strings and slices require a vector or fixed option")
+		}
+
+	case kindByteArray:
+		if err := checkArray(ft, tag); err != nil {
+			return err
+		}
+		g.p("b.WriteBytes(%s[:]...)", x)
+
+	case kindArray:
+		if err := checkArray(ft, tag); err != nil {
+			return err
+		}
+		return g.encodeElems(x, ft, tag, path)
+
+	case kindSlice:
+		switch {
+		case tag.Vector != 0:
+			g.p("if err := b.AddLengthPrefixed(%s, func(b *bytebuilder.Buffer) error {", bitSize(tag.Vector))
+			if err := g.encodeElems(x, ft, tag, path); err != nil {
+				return err
+			}
+			g.p("return nil")
+			g.p("}); err != nil {")
+			g.fail(path, "err")
+			g.p("}")
+		case tag.Fixed >= 0:
+			g.imports["fmt"] = true
+			g.p("if len(%s) != %d {", x, tag.Fixed)
+			g.fail(path, fmt.Sprintf("fmt.Errorf(\"%%w: have %%d elements, want %d\", bytebuilder.ErrInvalidLength, len(%s))", tag.Fixed, x))
+			g.p("}")
+			return g.encodeElems(x, ft, tag, path)
+		default:
+			return fmt.Errorf("strings and slices require a vector or fixed option")
+		}
+
+	case kindPointer:
+		g.p("if %s == nil {", x)
+		g.fail(path, "bytebuilder.ErrNilPointer")
+		g.p("}")
+		return g.encode("(*"+x+")", ft.elem, tag, path)
+
+	case kindCustom:
+		g.check(x+".EncodeTo(b)", path)
+	}
+
+	return nil
+}
+
+// encodeElems prints the loop encoding the elements of the slice or array x.
+func (g *generator) encodeElems(x string, ft fieldType, tag bytebuilder.FieldTag, path string) error {
+
+	i := g.temp("i")
+
+	g.p("for %s := range %s {", i, x)
+
+	if err := g.encode(x+"["+i+"]", ft.elem, tag.ElemTag(), g.index(path, i)); err != nil {
+		return err
+	}
+
+	g.p("}")
+
+	return nil
+}
+
+// encodeInt prints the encoding of the integer x.
+func (g *generator) encodeInt(x string, ft fieldType, tag bytebuilder.FieldTag, path string) error {
+
+	signed := ft.kind == kindInt
+
+	switch tag.Varint {
+	case bytebuilder.VarintLength:
+		if signed {
+			g.p("b.WriteVarint(%s)", conv("int64", ft, x))
+		} else {
+			g.p("b.WriteUvarint(%s)", conv("uint64", ft, x))
+		}
+		return nil
+
+	case bytebuilder.QUICVarintLength:
+		if signed {
+			return fmt.Errorf("quicvarint requires an unsigned integer")
+		}
+		g.check("b.WriteQUICVarint("+conv("uint64", ft, x)+")", path)
+		return nil
+	}
+
+	size := intSize(ft, tag)
+
+	if size < ft.size {
+
+		g.imports["fmt"] = true
+
+		if signed {
+			g.p("if %s < %d || %s > %d {", x, int64(-1)<<(8*size-1), x, int64(1)<<(8*size-1)-1)
+		} else {
+			g.p("if %s > %d {", x, uint64(1)<<(8*size)-1)
+		}
+
+		g.fail(path, fmt.Sprintf("fmt.Errorf(\"%%w: %%d does not fit into %d bytes\", bytebuilder.ErrValueOverflow, %s)", size, x))
+		g.p("}")
+	}
+
+	g.p("b.Write%s(%s)", intMethod(tag, size, signed), conv(intType(size, signed), ft, x))
+
+	return nil
+}
+
+// decode prints the decoding of the expression x with type t from the buffer b.
+// b is the name of a *bytebuilder.Buffer or an addressable bytebuilder.Buffer.
+func (g *generator) decode(x string, t ast.Expr, tag bytebuilder.FieldTag, b string, path string) error {
+
+	ft, err := g.resolve(t)
+	if err != nil {
+		return err
+	}
+
+	bp := b
+	if b != "b" {
+		bp = "&" + b
+	}
+
+	switch ft.kind {
+	case kindBool:
+		v := g.temp("v")
+		g.p("%s, err := %s.ReadUint8E()", v, b)
+		g.p("if err != nil {")
+		g.fail(path, "err")
+		g.p("}")
+		g.p("%s = %s != 0", x, v)
+
+	case kindInt, kindUint:
+		return g.decodeInt(x, ft, tag, b, path)
+
+	case kindFloat:
+		v := g.temp("v")
+		g.p("%s, err := %s.Read%sFloat%dE()", v, b, order(tag), ft.size*8)
+		g.p("if err != nil {")
+		g.fail(path, "err")
+		g.p("}")
+		g.p("%s = %s", x, conv(ft.name, fieldType{name: "float" + strconv.Itoa(ft.size*8)}, v))
+
+	case kindString, kindBytes:
+
+		v := g.temp("v")
+
+		switch {
+		case tag.Vector != 0:
+			g.p("%s, err := %s.ReadVectorE(%s)", v, b, bitSize(tag.Vector))
+		case tag.Fixed >= 0:
+			g.p("%s, err := %s.ReadBytesE(%d)", v, b, tag.Fixed)
+		default:
+			return fmt.Errorf("strings and slices require a vector or fixed option")
+		}
+
+		g.p("if err != nil {")
+		g.fail(path, "err")
+		g.p("}")
+		g.check(fmt.Sprintf("%s.Allocate(len(%s))", b, v), path)
+
+		switch {
+		case ft.kind == kindBytes:
+			g.p("%s = append(%s{}, %s...)", x, ft.name, v)
+		case tag.Fixed >= 0:
+			g.imports["strings"] = true
+			g.p("%s = %s", x, conv(ft.name, fieldType{name: "string"}, "strings.TrimRight(string("+v+"), \"\\x00\")"))
+		default:
+			g.p("%s = %s(%s)", x, ft.name, v)
+		}
+
+	case kindByteArray:
+		if err := checkArray(ft, tag); err != nil {
+			return err
+		}
+		v := g.temp("v")
+		g.p("%s, err := %s.ReadBytesE(len(%s))", v, b, x)
+		g.p("if err != nil {")
+		g.fail(path, "err")
+		g.p("}")
+		g.p("copy(%s[:], %s)", x, v)
+
+	case kindArray:
+		if err := checkArray(ft, tag); err != nil {
+			return err
+		}
+		i := g.temp("i")
+		g.p("for %s := range %s {", i, x)
+		if err := g.decode(x+"["+i+"]", ft.elem, tag.ElemTag(), b, g.index(path, i)); err != nil {
+			return err
+		}
+		g.p("}")
+
+	case kindSlice:
+		switch {
+		case tag.Vector != 0:
+			c := g.temp("c")
+			i := g.temp("i")
+			e := g.temp("e")
+			k, err := g.memSize(ft.elem)
+			if err != nil {
+				return err
+			}
+			g.p("var %s bytebuilder.Buffer", c)
+			g.check(fmt.Sprintf("%s.ReadLengthPrefixed(%s, &%s)", b, bitSize(tag.Vector), c), path)
+			g.p("%s = %s{}", x, ft.name)
+			n := g.temp("n")
+			g.p("for %s := 0; !%s.Empty(); %s++ {", i, c, i)
+			g.p("var %s %s", e, types.ExprString(ft.elem))
+			g.check(fmt.Sprintf("%s.Allocate(%d)", b, k), path)
+			g.p("%s = append(%s, %s)", x, x, e)
+			g.p("%s := %s.Remaining()", n, c)
+			if err := g.decode(x+"["+i+"]", ft.elem, tag.ElemTag(), c, g.index(path, i)); err != nil {
+				return err
+			}
+			// An element that does not consume any byte would be decoded forever (eg.: []struct{}).
+			g.p("if %s.Remaining() == %s {", c, n)
+			g.fail(g.index(path, i), fmt.Sprintf("&bytebuilder.DecodeError{Op: \"DecodeFrom\", Offset: %s.BaseOffset() + %s.Offset(), Have: %s, Err: bytebuilder.ErrEmptyElement}", c, c, n))
+			g.p("}")
+			g.p("}")
+		case tag.Fixed >= 0:
+			k, err := g.memSize(ft.elem)
+			if err != nil {
+				return err
+			}
+			i := g.temp("i")
+			g.p("%s = make(%s, %d)", x, ft.name, tag.Fixed)
+			g.check(fmt.Sprintf("%s.Allocate(%d)", b, tag.Fixed*k), path)
+			g.p("for %s := range %s {", i, x)
+			if err := g.decode(x+"["+i+"]", ft.elem, tag.ElemTag(), b, g.index(path, i)); err != nil {
+				return err
+			}
+			g.p("}")
+		default:
+			return fmt.Errorf("strings and slices require a vector or fixed option")
+		}
+
+	case kindPointer:
+		k, err := g.memSize(ft.elem)
+		if err != nil {
+			return err
+		}
+		g.p("if %s == nil {", x)
+		g.p("%s = new(%s)", x, types.ExprString(ft.elem))
+		g.check(fmt.Sprintf("%s.Allocate(%d)", b, k), path)
+		g.p("}")
+		return g.decode("(*"+x+")", ft.elem, tag, b, path)
+
+	case kindCustom:
+		g.check(x+".DecodeFrom("+bp+")", path)
+	}
+
+	return nil
+}
+
+// memSize returns the size in memory of the type expression t, counted against the MaxAllocation limit of the Buffer.
+// The sizes are those of 64-bit platforms, so the generated code does not depend on the platform of the generator.
+func (g *generator) memSize(t ast.Expr) (int, error) {
+
+	typ := g.info.TypeOf(t)
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		return 0, fmt.Errorf("can not determine the size of %s", types.ExprString(t))
+	}
+
+	return int(g.sizes.Sizeof(typ)), nil
+}
+
+// decodeInt prints the decoding of the integer x from the buffer b.
+func (g *generator) decodeInt(x string, ft fieldType, tag bytebuilder.FieldTag, b string, path string) error {
+
+	signed := ft.kind == kindInt
+	v := g.temp("v")
+
+	// Size of the decoded value
+	size := intSize(ft, tag)
+
+	switch tag.Varint {
+	case bytebuilder.VarintLength:
+		size = 8
+		if signed {
+			g.p("%s, err := %s.ReadVarintE()", v, b)
+		} else {
+			g.p("%s, err := %s.ReadUvarintE()", v, b)
+		}
+
+	case bytebuilder.QUICVarintLength:
+		if signed {
+			return fmt.Errorf("quicvarint requires an unsigned integer")
+		}
+		size = 8
+		g.p("%s, err := %s.ReadQUICVarintE()", v, b)
+
+	default:
+		g.p("%s, err := %s.Read%sE()", v, b, intMethod(tag, size, signed))
+	}
+
+	g.p("if err != nil {")
+	g.fail(path, "err")
+	g.p("}")
+
+	// The 24-bit readers do not extend the sign.
+	if signed && size == 3 && tag.Varint == 0 {
+		g.p("%s = %s << 8 >> 8", v, v)
+	}
+
+	if size > ft.size {
+
+		g.imports["fmt"] = true
+
+		if signed {
+			g.p("if %s < %d || %s > %d {", v, int64(-1)<<(8*ft.size-1), v, int64(1)<<(8*ft.size-1)-1)
+		} else {
+			g.p("if %s > %d {", v, uint64(1)<<(8*ft.size)-1)
+		}
+
+		g.fail(path, fmt.Sprintf("fmt.Errorf(\"%%w: %%d does not fit into %s\", bytebuilder.ErrValueOverflow, %s)", ft.name, v))
+		g.p("}")
+	}
+
+	g.p("%s = %s", x, conv(ft.name, fieldType{name: intType(size, signed)}, v))
+
+	return nil
+}
+
+// size prints the code adding the encoded size of x with type t to the variable n.
+// The constant part of the size is returned instead of printed.
+func (g *generator) size(n string, x string, t ast.Expr, tag bytebuilder.FieldTag) (int, error) {
+
+	ft, err := g.resolve(t)
+	if err != nil {
+		return 0, err
+	}
+
+	switch ft.kind {
+	case kindBool:
+		return 1, nil
+
+	case kindInt, kindUint:
+		switch tag.Varint {
+		case bytebuilder.VarintLength:
+			if ft.kind == kindInt {
+				g.p("%s += bytebuilder.UvarintLen(bytebuilder.ZigZagEncode(%s))", n, conv("int64", ft, x))
+			} else {
+				g.p("%s += bytebuilder.UvarintLen(%s)", n, conv("uint64", ft, x))
+			}
+			return 0, nil
+		case bytebuilder.QUICVarintLength:
+			g.p("%s += bytebuilder.QUICVarintLen(%s)", n, conv("uint64", ft, x))
+			return 0, nil
+		}
+		return intSize(ft, tag), nil
+
+	case kindFloat:
+		return ft.size, nil
+
+	case kindString, kindBytes:
+		if tag.Vector == 0 {
+			return tag.Fixed, nil
+		}
+		k, s := prefixSize(tag.Vector, "len("+x+")")
+		if s != "" {
+			g.p("%s += %s", n, s)
+		}
+		g.p("%s += len(%s)", n, x)
+		return k, nil
+
+	case kindByteArray:
+		if k, err := strconv.Atoi(ft.len); err == nil {
+			return k, nil
+		}
+		g.p("%s += len(%s)", n, x)
+		return 0, nil
+
+	case kindArray, kindSlice:
+
+		if ft.kind == kindSlice && tag.Vector != 0 {
+
+			s := g.temp("s")
+
+			code, k, err := g.capture(func() (int, error) {
+				return g.sizeElems(s, x, ft, tag)
+			})
+			if err != nil {
+				return 0, err
+			}
+
+			g.p("%s := %d", s, k)
+			g.w.WriteString(code)
+
+			pk, ps := prefixSize(tag.Vector, s)
+			if ps != "" {
+				g.p("%s += %s", n, ps)
+			}
+			g.p("%s += %s", n, s)
+
+			return pk, nil
+		}
+
+		return g.sizeElems(n, x, ft, tag)
+
+	case kindPointer:
+
+		code, k, err := g.capture(func() (int, error) {
+			return g.size(n, "(*"+x+")", ft.elem, tag)
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		g.p("if %s != nil {", x)
+		if k > 0 {
+			g.p("%s += %d", n, k)
+		}
+		g.w.WriteString(code)
+		g.p("}")
+
+		return 0, nil
+
+	case kindCustom:
+		g.p("%s += %s.EncodedSize()", n, x)
+	}
+
+	return 0, nil
+}
+
+// sizeElems prints the code adding the encoded size of the elements of the slice or array x to n.
+func (g *generator) sizeElems(n string, x string, ft fieldType, tag bytebuilder.FieldTag) (int, error) {
+
+	i := g.temp("i")
+
+	code, k, err := g.capture(func() (int, error) {
+		return g.size(n, x+"["+i+"]", ft.elem, tag.ElemTag())
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Fixed size elements
+	if code == "" {
+
+		if l, err := strconv.Atoi(ft.len); err == nil {
+			return l * k, nil
+		}
+
+		switch {
+		case k == 1:
+			g.p("%s += len(%s)", n, x)
+		case k > 1:
+			g.p("%s += len(%s) * %d", n, x, k)
+		}
+
+		return 0, nil
+	}
+
+	g.p("for %s := range %s {", i, x)
+	if k > 0 {
+		g.p("%s += %d", n, k)
+	}
+	g.w.WriteString(code)
+	g.p("}")
+
+	return 0, nil
+}
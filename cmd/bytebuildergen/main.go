@@ -0,0 +1,228 @@
+// Command bytebuildergen generates EncodeTo, DecodeFrom and EncodedSize methods for structs
+// using the typed read/write methods of bytebuilder.Buffer, without reflection.
+//
+// The encoding is the same as bytebuilder.Marshal, controlled by the same `bb:"..."` struct tags.
+// Field types that are not basic types, strings, slices or arrays must implement
+// EncodeTo, DecodeFrom and EncodedSize (eg.: other generated types).
+// The types of other packages are type checked from source: they are encoded by their methods,
+// or by their underlying type if it is a basic type or a byte slice or array (eg.: time.Duration, net.IP).
+// Other types are rejected at generation time.
+//
+// Like Unmarshal, the generated DecodeFrom counts the decoded slices against the MaxAllocation limit of the Buffer.
+//
+// Usage:
+//
+//	//go:generate go run github.com/g0rbe/go-bytebuilder/cmd/bytebuildergen -type=ClientHello,Extension
+//
+// Flags:
+//
+//	-type    comma separated list of struct type names (required)
+//	-output  output file name (default: <first type>_bytebuilder.go)
+//
+// The argument is the directory of the package (default: the current directory).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+
+	typeNames := flag.String("type", "", "comma separated list of struct type names")
+	output := flag.String("output", "", "output file name (default: <first type>_bytebuilder.go)")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: bytebuildergen -type T[,T...] [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	if *output == "" {
+		first := strings.Split(*typeNames, ",")[0]
+		*output = strings.ToLower(first) + "_bytebuilder.go"
+	}
+
+	if !filepath.IsAbs(*output) {
+		*output = filepath.Join(dir, *output)
+	}
+
+	src, err := generate(dir, strings.Split(*typeNames, ","), filepath.Base(*output))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bytebuildergen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "bytebuildergen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate parses the package in dir and returns the formatted source of the methods for the types in names.
+// The output file is excluded from parsing, so a previous version does not conflict.
+func generate(dir string, names []string, output string) ([]byte, error) {
+
+	fset := token.NewFileSet()
+
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}
+
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	g := newGenerator()
+	g.dir = dir
+	g.importer = importer.ForCompiler(fset, "source", nil).(types.ImporterFrom)
+
+	// Sort the file names, so the generated code is deterministic.
+	files := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+
+		for _, spec := range pkg.Files[name].Imports {
+			g.addImport(spec)
+		}
+
+		for _, decl := range pkg.Files[name].Decls {
+
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					g.types[ts.Name.Name] = ts.Type
+				}
+			case *ast.FuncDecl:
+				// Types with handwritten methods are encoded by calling them.
+				if d.Recv != nil && d.Name.Name == "EncodeTo" {
+					g.methods[receiverName(d.Recv.List[0].Type)] = true
+				}
+			}
+		}
+	}
+
+	// The package is type checked for the memory sizes of the decoded values.
+	// The errors are ignored, the generated methods may be missing or outdated.
+	astFiles := make([]*ast.File, 0, len(files))
+	for _, name := range files {
+		astFiles = append(astFiles, pkg.Files[name])
+	}
+
+	conf := types.Config{Importer: g.importer, Error: func(error) {}}
+	conf.Check(pkg.Name, fset, astFiles, g.info)
+
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+		g.names[names[i]] = true
+	}
+
+	for _, name := range names {
+
+		typ, ok := g.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in %s", name, dir)
+		}
+
+		st, ok := typ.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct", name)
+		}
+
+		if err := g.generateStruct(name, st); err != nil {
+			return nil, err
+		}
+	}
+
+	src := g.source(pkg.Name)
+
+	out, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, src)
+	}
+
+	return out, nil
+}
+
+// addImport records an import of the package files.
+// A name imported with different paths in different files is ambiguous, it is recorded with an empty path.
+func (g *generator) addImport(spec *ast.ImportSpec) {
+
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil || path == "C" || path == "unsafe" {
+		return
+	}
+
+	if spec.Name == nil {
+		for _, p := range g.paths {
+			if p == path {
+				return
+			}
+		}
+		g.paths = append(g.paths, path)
+		return
+	}
+
+	name := spec.Name.Name
+
+	if p, ok := g.aliases[name]; ok && p != path {
+		path = ""
+	}
+
+	g.aliases[name] = path
+}
+
+// receiverName returns the name of the type of a method receiver.
+func receiverName(t ast.Expr) string {
+
+	if s, ok := t.(*ast.StarExpr); ok {
+		t = s.X
+	}
+
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+
+	return ""
+}
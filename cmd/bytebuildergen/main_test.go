@@ -0,0 +1,310 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+
+	tests := []struct {
+		name string
+		src  string
+		want []string // substrings of the generated code
+		err  string   // substring of the error, empty if generate must succeed
+	}{
+		{"basic", `package p
+
+type T struct {
+	A uint16
+	B []byte   ` + "`bb:\"vector,8\"`" + `
+	C []uint16 ` + "`bb:\"vector,16\"`" + `
+}
+`, []string{"func (x *T) EncodeTo(", "func (x *T) DecodeFrom(", "func (x *T) EncodedSize(", "Allocate(", "ErrEmptyElement"}, ""},
+		{"imported basic types", `package p
+
+import (
+	"net"
+	"time"
+)
+
+type T struct {
+	D  time.Duration ` + "`bb:\"varint\"`" + `
+	IP net.IP        ` + "`bb:\"vector,8\"`" + `
+}
+`, []string{"int64(", "ReadVarint"}, ""},
+		{"named import", `package p
+
+import tm "time"
+
+type T struct {
+	D tm.Duration
+}
+`, []string{"tm.Duration("}, ""},
+		{"unsupported imported type", `package p
+
+import "sync"
+
+type T struct {
+	M sync.Mutex
+}
+`, nil, "sync.Mutex"},
+		{"unknown package", `package p
+
+type T struct {
+	X foo.Bar
+}
+`, nil, "foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dir := t.TempDir()
+
+			if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(tt.src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := generate(dir, []string{"T"}, "t_bytebuilder.go")
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("generate error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("generate: %s", err)
+			}
+
+			for _, s := range tt.want {
+				if !strings.Contains(string(out), s) {
+					t.Fatalf("generated code does not contain %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
+
+// compareSrc is a package with a representative struct for TestGeneratedMatchesMarshal.
+// RT and RInner have the same fields without the generated methods, so Marshal encodes them with reflection.
+const compareSrc = `package p
+
+import "time"
+
+type Inner struct {
+	ID   uint16
+	Name string "bb:\"vector,8\""
+}
+
+type T struct {
+	Flag   bool
+	U8     uint8
+	I16    int16         "bb:\"le\""
+	U24    uint32        "bb:\"uint24,be\""
+	I24    int32         "bb:\"int24\""
+	U64    uint64
+	Int    int           "bb:\"int32\""
+	V      int64         "bb:\"varint\""
+	UV     uint          "bb:\"varint\""
+	Q      uint64        "bb:\"quicvarint\""
+	F32    float32
+	F64    float64       "bb:\"be\""
+	Str    string        "bb:\"vector,16\""
+	Fixed  string        "bb:\"fixed,6\""
+	Raw    []byte        "bb:\"vector,varint\""
+	Arr    [3]byte
+	Words  [2]uint16     "bb:\"le\""
+	List   []uint32      "bb:\"vector,quicvarint,uint24\""
+	Pair   []int8        "bb:\"fixed,2\""
+	Inners []Inner       "bb:\"vector,24\""
+	Ptr    *Inner
+	PtrInt *uint16       "bb:\"be\""
+	D      time.Duration "bb:\"varint\""
+	Skip   int           "bb:\"-\""
+	hidden int
+}
+
+type RT T
+
+type RInner Inner
+`
+
+// compareTestSrc compares the generated methods with Marshal and Unmarshal in both byte orders.
+const compareTestSrc = `package p
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+func value() T {
+
+	n := uint16(0xbeef)
+
+	return T{
+		Flag: true, U8: 0xfe, I16: -2, U24: 0xabcdef, I24: -70000, U64: 1<<63 + 5, Int: -1 << 20,
+		V: -300, UV: 1 << 40, Q: 1<<62 - 1, F32: 1.5, F64: -2.25,
+		Str: "hello", Fixed: "abc", Raw: []byte{1, 2, 3}, Arr: [3]byte{4, 5, 6}, Words: [2]uint16{7, 0x0809},
+		List: []uint32{1, 0xffffff}, Pair: []int8{-1, 1},
+		Inners: []Inner{{1, "a"}, {2, ""}}, Ptr: &Inner{3, "ptr"}, PtrInt: &n, D: 3 * time.Second,
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	for _, e := range []bytebuilder.Endianness{bytebuilder.BigEndian, bytebuilder.LittleEndian} {
+
+		x := value()
+
+		// A byte before the value checks that EncodeTo appends and DecodeFrom starts at the read cursor.
+		g := bytebuilder.NewBufferWithEndianness([]byte{0xaa}, e)
+		if err := x.EncodeTo(&g); err != nil {
+			t.Fatalf("%v: EncodeTo: %s", e, err)
+		}
+
+		r := bytebuilder.NewBufferWithEndianness([]byte{0xaa}, e)
+		if err := r.Marshal((*RT)(&x)); err != nil {
+			t.Fatalf("%v: Marshal: %s", e, err)
+		}
+
+		enc := g.Bytes()
+
+		if !bytes.Equal(enc, r.Bytes()) {
+			t.Fatalf("%v: EncodeTo = %x, Marshal = %x", e, enc, r.Bytes())
+		}
+
+		if n := x.EncodedSize(); n != len(enc)-1 {
+			t.Fatalf("%v: EncodedSize = %d, want %d", e, n, len(enc)-1)
+		}
+
+		var gx T
+		gb := bytebuilder.NewBufferWithEndianness(enc, e)
+		gb.ReadUint8()
+		if err := gx.DecodeFrom(&gb); err != nil || !gb.Empty() {
+			t.Fatalf("%v: DecodeFrom: %v, %d bytes left", e, err, gb.Remaining())
+		}
+
+		var rx RT
+		rb := bytebuilder.NewBufferWithEndianness(enc, e)
+		rb.ReadUint8()
+		if err := rb.Unmarshal(&rx); err != nil || !rb.Empty() {
+			t.Fatalf("%v: Unmarshal: %v, %d bytes left", e, err, rb.Remaining())
+		}
+
+		x.Skip = 0
+		if !reflect.DeepEqual(gx, x) || !reflect.DeepEqual(gx, T(rx)) {
+			t.Fatalf("%v: DecodeFrom = %+v, Unmarshal = %+v, want %+v", e, gx, rx, x)
+		}
+
+		// Short input fails in both.
+		for n := 1; n < len(enc); n++ {
+
+			gb := bytebuilder.NewBufferWithEndianness(enc[1:n], e)
+			rb := bytebuilder.NewBufferWithEndianness(enc[1:n], e)
+
+			var gx T
+			var rx RT
+
+			if gerr, rerr := gx.DecodeFrom(&gb), rb.Unmarshal(&rx); gerr == nil || rerr == nil {
+				t.Fatalf("%v: %d bytes: DecodeFrom = %v, Unmarshal = %v, want errors", e, n-1, gerr, rerr)
+			}
+		}
+
+		// The allocations are counted the same way.
+		for max := int64(1); max <= 512; max++ {
+
+			gb := bytebuilder.NewBufferWithEndianness(enc[1:], e)
+			gb.SetLimits(bytebuilder.Limits{MaxAllocation: max})
+			rb := bytebuilder.NewBufferWithEndianness(enc[1:], e)
+			rb.SetLimits(bytebuilder.Limits{MaxAllocation: max})
+
+			var gx T
+			var rx RT
+
+			if gerr, rerr := gx.DecodeFrom(&gb), rb.Unmarshal(&rx); (gerr == nil) != (rerr == nil) {
+				t.Fatalf("%v: MaxAllocation %d: DecodeFrom = %v, Unmarshal = %v", e, max, gerr, rerr)
+			}
+		}
+
+		// An error in a late field rolls back the bytes of the earlier fields.
+		x.Pair = []int8{1}
+
+		g = bytebuilder.NewBufferWithEndianness([]byte{0xaa}, e)
+		if err := x.EncodeTo(&g); err == nil {
+			t.Fatalf("%v: EncodeTo with an invalid field succeeded", e)
+		}
+
+		r = bytebuilder.NewBufferWithEndianness([]byte{0xaa}, e)
+		if err := r.Marshal((*RT)(&x)); err == nil {
+			t.Fatalf("%v: Marshal with an invalid field succeeded", e)
+		}
+
+		if !bytes.Equal(g.Bytes(), []byte{0xaa}) || !bytes.Equal(r.Bytes(), []byte{0xaa}) {
+			t.Fatalf("%v: after error EncodeTo = %x, Marshal = %x, want aa", e, g.Bytes(), r.Bytes())
+		}
+	}
+}
+`
+
+// TestGeneratedMatchesMarshal builds the generated code in a temporary module
+// and checks that it encodes and decodes the same way as Marshal and Unmarshal.
+func TestGeneratedMatchesMarshal(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+
+	mod := "module example.com/p\n\ngo 1.19\n\nrequire github.com/g0rbe/go-bytebuilder v0.0.0\n\n" +
+		"replace github.com/g0rbe/go-bytebuilder => " + root + "\n"
+
+	files := map[string]string{"go.mod": mod, "p.go": compareSrc, "p_test.go": compareTestSrc}
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := generate(dir, []string{"T", "Inner"}, "t_bytebuilder.go")
+	if err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	if strings.Contains(string(out), "unsafe") {
+		t.Fatalf("generated code imports unsafe:\n%s", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "t_bytebuilder.go"), out, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goCmd, "test", "-count=1", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test: %s\n%s\n%s", err, b, out)
+	}
+}
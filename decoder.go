@@ -0,0 +1,349 @@
+package bytebuilder
+
+import "time"
+
+// Decoder reads values from a Buffer and records the first error.
+// After a failed read, every subsequent read is a no-op and returns the zero value,
+// so a sequence of reads can be checked once with Err.
+// Multi-byte integers are read in the byte order of the underlying Buffer.
+type Decoder struct {
+	b   *Buffer
+	err *error // shared with the child decoders
+}
+
+// NewDecoder creates a Decoder that reads from b.
+func NewDecoder(b *Buffer) *Decoder {
+	return &Decoder{b: b, err: new(error)}
+}
+
+// Err returns the first error encountered by d or by any of its child decoders, or nil.
+func (d *Decoder) Err() error {
+	return *d.err
+}
+
+// Buffer returns the underlying Buffer of d.
+func (d *Decoder) Buffer() *Buffer {
+	return d.b
+}
+
+// Offset returns the position of the read cursor of the underlying Buffer.
+func (d *Decoder) Offset() int {
+	return d.b.Offset()
+}
+
+// Empty returns whether the underlying Buffer has no unread bytes.
+func (d *Decoder) Empty() bool {
+	return d.b.Empty()
+}
+
+// ReadBytes reads the next n bytes.
+// If the read failed, returns nil.
+func (d *Decoder) ReadBytes(n int) []byte {
+
+	if *d.err != nil {
+		return nil
+	}
+
+	v, err := d.b.ReadBytesE(n)
+	*d.err = err
+
+	return v
+}
+
+// Skip advances the read cursor by n bytes.
+func (d *Decoder) Skip(n int) {
+
+	if *d.err != nil {
+		return
+	}
+
+	*d.err = d.b.SkipE(n)
+}
+
+// ReadUint8 reads the next byte and returns it as an uint8.
+func (d *Decoder) ReadUint8() uint8 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadUint8E()
+	*d.err = err
+
+	return v
+}
+
+// ReadInt8 reads the next byte and returns it as an int8.
+func (d *Decoder) ReadInt8() int8 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadInt8E()
+	*d.err = err
+
+	return v
+}
+
+// ReadUint16 reads the next bytes and returns it as an uint16.
+func (d *Decoder) ReadUint16() uint16 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadUint16E()
+	*d.err = err
+
+	return v
+}
+
+// ReadInt16 reads the next bytes and returns it as an int16.
+func (d *Decoder) ReadInt16() int16 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadInt16E()
+	*d.err = err
+
+	return v
+}
+
+// ReadUint24 reads the next bytes and returns it as a uint32.
+func (d *Decoder) ReadUint24() uint32 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadUint24E()
+	*d.err = err
+
+	return v
+}
+
+// ReadInt24 reads the next bytes and returns it as an int32.
+func (d *Decoder) ReadInt24() int32 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadInt24E()
+	*d.err = err
+
+	return v
+}
+
+// ReadUint32 reads the next bytes and returns it as an uint32.
+func (d *Decoder) ReadUint32() uint32 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadUint32E()
+	*d.err = err
+
+	return v
+}
+
+// ReadInt32 reads the next bytes and returns it as an int32.
+func (d *Decoder) ReadInt32() int32 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadInt32E()
+	*d.err = err
+
+	return v
+}
+
+// ReadUint64 reads the next bytes and returns it as an uint64.
+func (d *Decoder) ReadUint64() uint64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadUint64E()
+	*d.err = err
+
+	return v
+}
+
+// ReadInt64 reads the next bytes and returns it as an int64.
+func (d *Decoder) ReadInt64() int64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadInt64E()
+	*d.err = err
+
+	return v
+}
+
+// ReadInt reads the next bytes (depends on IntSize) and returns it as an int.
+func (d *Decoder) ReadInt() int {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadIntE()
+	*d.err = err
+
+	return v
+}
+
+// ReadGMTUnixTime32 reads the next bytes and returns it as an unix time.
+func (d *Decoder) ReadGMTUnixTime32() time.Time {
+
+	if *d.err != nil {
+		return time.Time{}
+	}
+
+	v, err := d.b.ReadGMTUnixTime32E()
+	*d.err = err
+
+	return v
+}
+
+// ReadVector reads the length of bytes then the bytes itself.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength, otherwise ErrInvalidBitSize is recorded.
+// If the read failed, returns nil.
+func (d *Decoder) ReadVector(bitSize int) []byte {
+
+	if *d.err != nil {
+		return nil
+	}
+
+	v, err := d.b.ReadVectorE(bitSize)
+	*d.err = err
+
+	return v
+}
+
+// ReadFloat32 reads the next bytes and returns it as a float32.
+func (d *Decoder) ReadFloat32() float32 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadFloat32E()
+	*d.err = err
+
+	return v
+}
+
+// ReadFloat64 reads the next bytes and returns it as a float64.
+func (d *Decoder) ReadFloat64() float64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadFloat64E()
+	*d.err = err
+
+	return v
+}
+
+// ReadComplex64 reads the next bytes and returns it as a complex64 (real part first).
+func (d *Decoder) ReadComplex64() complex64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadComplex64E()
+	*d.err = err
+
+	return v
+}
+
+// ReadComplex128 reads the next bytes and returns it as a complex128 (real part first).
+func (d *Decoder) ReadComplex128() complex128 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadComplex128E()
+	*d.err = err
+
+	return v
+}
+
+// ReadUint8LengthPrefixed reads an uint8 length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadUint8LengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(8)
+}
+
+// ReadUint16LengthPrefixed reads an uint16 length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadUint16LengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(16)
+}
+
+// ReadUint24LengthPrefixed reads a uint24 length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadUint24LengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(24)
+}
+
+// ReadUint32LengthPrefixed reads an uint32 length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadUint32LengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(32)
+}
+
+// ReadUint64LengthPrefixed reads an uint64 length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadUint64LengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(64)
+}
+
+// ReadUvarintLengthPrefixed reads an unsigned LEB128 varint length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadUvarintLengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(VarintLength)
+}
+
+// ReadQUICVarintLengthPrefixed reads a QUIC variable-length integer length-prefixed vector and returns a child Decoder over its content.
+func (d *Decoder) ReadQUICVarintLengthPrefixed() *Decoder {
+	return d.ReadLengthPrefixed(QUICVarintLength)
+}
+
+// ReadLengthPrefixed reads a length-prefixed vector and returns a child Decoder over its content.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength, otherwise ErrInvalidBitSize is recorded.
+// The child can not read past the end of the vector and shares the error with d:
+// a failed read in the child is reported by the Err method of d.
+func (d *Decoder) ReadLengthPrefixed(bitSize int) *Decoder {
+
+	child := &Decoder{b: &Buffer{}, err: d.err}
+
+	if *d.err != nil {
+		return child
+	}
+
+	*d.err = d.b.ReadLengthPrefixed(bitSize, child.b)
+
+	return child
+}
+
+// ExpectEmpty records a *DecodeError wrapping ErrTrailingData if there are unread bytes left.
+// It is used to check that the content of a vector was parsed completely.
+func (d *Decoder) ExpectEmpty() {
+
+	if *d.err != nil {
+		return
+	}
+
+	*d.err = d.b.ExpectEmpty()
+}
@@ -0,0 +1,97 @@
+package bytebuilder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+
+	b := NewBuffer([]byte{0x01, 0x02, 0x03, 0xff, 0xff, 0xff, 0xfe, 0x02, 0xaa, 0xbb, 0x00, 0x00, 0x00, 0x2a})
+	d := NewDecoder(&b)
+
+	u8 := d.ReadUint8()
+	u16 := d.ReadUint16()
+	i32 := d.ReadInt32()
+	v := d.ReadVector(8)
+	u32 := d.ReadUint32()
+
+	if err := d.Err(); err != nil {
+		t.Fatalf("Err = %v", err)
+	}
+
+	if u8 != 1 || u16 != 0x0203 || i32 != -2 || string(v) != "\xaa\xbb" || u32 != 42 || !d.Empty() {
+		t.Fatalf("got %d %#x %d % x %d, Empty = %t", u8, u16, i32, v, u32, d.Empty())
+	}
+}
+
+func TestDecoderStickyError(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		read   func(d *Decoder)
+		op     string
+		offset int
+		err    error
+	}{
+		{
+			name:   "short",
+			data:   []byte{0x01, 0x02, 0x03},
+			read:   func(d *Decoder) { d.ReadUint8(); d.ReadUint32(); d.ReadUint8() },
+			op:     "ReadUint32",
+			offset: 1,
+			err:    ErrShortBuffer,
+		},
+		{
+			name:   "vector",
+			data:   []byte{0x00, 0x05, 0x01, 0x02},
+			read:   func(d *Decoder) { d.ReadVector(16); d.ReadUint16() },
+			op:     "ReadVector",
+			offset: 2,
+			err:    ErrShortBuffer,
+		},
+		{
+			name:   "bitSize",
+			data:   []byte{0x01, 0x02},
+			read:   func(d *Decoder) { d.ReadVector(12); d.ReadUint8() },
+			op:     "ReadVector",
+			offset: 0,
+			err:    ErrInvalidBitSize,
+		},
+		{
+			name:   "skip",
+			data:   []byte{0x01},
+			read:   func(d *Decoder) { d.Skip(2); d.ReadUint8() },
+			op:     "Skip",
+			offset: 0,
+			err:    ErrShortBuffer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			b := NewBuffer(tt.data)
+			d := NewDecoder(&b)
+
+			tt.read(d)
+
+			var de *DecodeError
+			if !errors.As(d.Err(), &de) {
+				t.Fatalf("Err = %v, want *DecodeError", d.Err())
+			}
+
+			if de.Op != tt.op || de.Offset != tt.offset || !errors.Is(de, tt.err) {
+				t.Fatalf("Err = %v, want %s at offset %d: %v", de, tt.op, tt.offset, tt.err)
+			}
+
+			// Reads after the first error are no-ops.
+			off := d.Offset()
+
+			if d.ReadUint8() != 0 || d.ReadBytes(1) != nil || d.Offset() != off || d.Err() != error(de) {
+				t.Fatalf("read after error changed the Decoder: Offset = %d, Err = %v", d.Offset(), d.Err())
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package dns
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// maxPointer is the largest offset a compression pointer can hold.
+const maxPointer = 1<<14 - 1
+
+// Builder writes a message into a Buffer.
+// The header, the questions and the records must be written in order and the counts of the header must match.
+type Builder struct {
+	b        *bytebuilder.Buffer
+	start    int            // Offset of the message in the underlying byte slice of b
+	names    map[string]int // Compression table: name -> offset from the start of the message
+	compress bool
+}
+
+// NewBuilder returns a Builder that appends a message to b with name compression enabled.
+// The message starts at the current end of b.
+func NewBuilder(b *bytebuilder.Buffer) *Builder {
+	return &Builder{b: b, start: b.Size(), names: make(map[string]int), compress: true}
+}
+
+// SetCompression sets whether the names written after are compressed.
+func (w *Builder) SetCompression(compress bool) {
+	w.compress = compress
+}
+
+// offset returns the current offset from the start of the message.
+func (w *Builder) offset() int {
+	return w.b.Size() - w.start
+}
+
+// Header writes the header.
+func (w *Builder) Header(h Header) {
+
+	w.b.WriteBigUint16(h.ID)
+	w.b.WriteBigUint16(h.flags())
+	w.b.WriteBigUint16(h.QDCount)
+	w.b.WriteBigUint16(h.ANCount)
+	w.b.WriteBigUint16(h.NSCount)
+	w.b.WriteBigUint16(h.ARCount)
+}
+
+// Question writes a question.
+func (w *Builder) Question(q Question) error {
+
+	if err := w.Name(q.Name); err != nil {
+		return bytebuilder.WrapFieldError("Question.Name", err)
+	}
+
+	w.b.WriteBigUint16(uint16(q.Type))
+	w.b.WriteBigUint16(uint16(q.Class))
+
+	return nil
+}
+
+// Resource writes a resource record.
+// The RDLENGTH is patched after the data was written.
+// If an error is returned, the bytes and the compression table entries of the record are removed.
+func (w *Builder) Resource(r Resource) error {
+
+	if r.Data == nil {
+		return bytebuilder.WrapFieldError("Resource.Data", bytebuilder.ErrNilPointer)
+	}
+
+	size := w.b.Size()
+
+	if err := w.Name(r.Name); err != nil {
+		w.rollback(size)
+		return bytebuilder.WrapFieldError("Resource.Name", err)
+	}
+
+	w.b.WriteBigUint16(uint16(r.Data.Type()))
+	w.b.WriteBigUint16(uint16(r.Class))
+	w.b.WriteBigUint32(r.TTL)
+
+	// RDLENGTH placeholder
+	pos := w.b.Size()
+	w.b.WriteBigUint16(0)
+
+	if err := r.Data.encode(w); err != nil {
+		w.rollback(size)
+		return bytebuilder.WrapFieldError("Resource.Data", err)
+	}
+
+	n := w.b.Size() - pos - 2
+	if n > 1<<16-1 {
+		w.rollback(size)
+		return bytebuilder.WrapFieldError("Resource.Data", ErrMalformed)
+	}
+
+	binary.BigEndian.PutUint16((*w.b.BytesPointer())[pos:], uint16(n))
+
+	return nil
+}
+
+// rollback truncates b to size and removes the names written after it from the compression table.
+func (w *Builder) rollback(size int) {
+
+	p := w.b.BytesPointer()
+	*p = (*p)[:size]
+
+	for name, off := range w.names {
+		if off >= size-w.start {
+			delete(w.names, name)
+		}
+	}
+}
+
+// Name writes a domain name, compressed if compression is enabled.
+func (w *Builder) Name(name string) error {
+	return w.name(name, w.compress)
+}
+
+// name writes a domain name.
+// If compress is true, the longest suffix already written is replaced by a pointer.
+// The suffixes written are added to the compression table either way, so later names can point to them.
+func (w *Builder) name(name string, compress bool) error {
+
+	name = strings.TrimSuffix(name, ".")
+
+	if err := checkName(name); err != nil {
+		return err
+	}
+
+	for name != "" {
+
+		if off, ok := w.names[name]; ok && compress {
+			w.b.WriteBigUint16(0xc000 | uint16(off))
+			return nil
+		}
+
+		// The first occurrence is kept, so the table only grows at the end of the message.
+		if _, ok := w.names[name]; !ok && w.offset() <= maxPointer {
+			w.names[name] = w.offset()
+		}
+
+		label := name
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			label, name = name[:i], name[i+1:]
+		} else {
+			name = ""
+		}
+
+		w.b.WriteUint8(uint8(len(label)))
+		w.b.WriteString(label)
+	}
+
+	w.b.WriteUint8(0)
+
+	return nil
+}
+
+// checkName returns ErrInvalidName if name (without the trailing dot) can not be encoded.
+func checkName(name string) error {
+
+	if name == "" {
+		return nil
+	}
+
+	// Length octets, labels and the root label
+	if len(name)+2 > 255 {
+		return ErrInvalidName
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return ErrInvalidName
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+// Package dns reads and writes DNS messages (RFC 1035) with bytebuilder:
+// header, questions and resource records (A, AAAA, CNAME, MX, TXT, SRV, OPT), other types are kept as raw data.
+//
+// Domain names are written with label compression: the Builder keeps a table of the names written
+// and their offsets from the start of the message in the Buffer.
+// The Parser follows the compression pointers only backwards, so a malicious message can not make it loop.
+//
+// Names are in presentation form without escapes (eg.: "example.com."), the trailing dot is optional when writing.
+// Labels containing a dot can not be written.
+//
+// Messages are always in network (big-endian) byte order, independent of the byte order of the Buffer.
+package dns
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+var (
+	// ErrInvalidName is returned when a domain name has an empty label, a label longer than 63 bytes
+	// or it is longer than 255 bytes.
+	ErrInvalidName = errors.New("dns: invalid domain name")
+
+	// ErrInvalidPointer is returned when a compression pointer does not point backwards (eg.: pointer loop).
+	ErrInvalidPointer = errors.New("dns: invalid compression pointer")
+
+	// ErrMalformed is returned when a message or a resource record data is malformed.
+	ErrMalformed = errors.New("dns: malformed message")
+)
+
+// HeaderLength is the size of the message header.
+const HeaderLength = 12
+
+// Type is the type of a resource record or question.
+type Type uint16
+
+const (
+	TypeA     Type = 1
+	TypeNS    Type = 2
+	TypeCNAME Type = 5
+	TypeSOA   Type = 6
+	TypePTR   Type = 12
+	TypeMX    Type = 15
+	TypeTXT   Type = 16
+	TypeAAAA  Type = 28
+	TypeSRV   Type = 33
+	TypeOPT   Type = 41
+	TypeANY   Type = 255
+)
+
+// Class is the class of a resource record or question.
+type Class uint16
+
+const (
+	ClassINET Class = 1
+	ClassCH   Class = 3
+	ClassANY  Class = 255
+)
+
+// Opcodes
+const (
+	OpcodeQuery  uint8 = 0
+	OpcodeStatus uint8 = 2
+	OpcodeNotify uint8 = 4
+	OpcodeUpdate uint8 = 5
+)
+
+// Response codes
+const (
+	RCodeSuccess        uint8 = 0
+	RCodeFormatError    uint8 = 1
+	RCodeServerFailure  uint8 = 2
+	RCodeNameError      uint8 = 3
+	RCodeNotImplemented uint8 = 4
+	RCodeRefused        uint8 = 5
+)
+
+// Header is the header of a message.
+// The counts are set by Message.EncodeTo from the length of the sections.
+type Header struct {
+	ID                 uint16
+	Response           bool
+	Opcode             uint8
+	Authoritative      bool
+	Truncated          bool
+	RecursionDesired   bool
+	RecursionAvailable bool
+	AuthenticData      bool
+	CheckingDisabled   bool
+	RCode              uint8
+
+	QDCount uint16
+	ANCount uint16
+	NSCount uint16
+	ARCount uint16
+}
+
+// flags returns the second 16 bit word of the header.
+func (h *Header) flags() uint16 {
+
+	v := uint16(h.Opcode&0xf)<<11 | uint16(h.RCode&0xf)
+
+	if h.Response {
+		v |= 1 << 15
+	}
+	if h.Authoritative {
+		v |= 1 << 10
+	}
+	if h.Truncated {
+		v |= 1 << 9
+	}
+	if h.RecursionDesired {
+		v |= 1 << 8
+	}
+	if h.RecursionAvailable {
+		v |= 1 << 7
+	}
+	if h.AuthenticData {
+		v |= 1 << 5
+	}
+	if h.CheckingDisabled {
+		v |= 1 << 4
+	}
+
+	return v
+}
+
+// setFlags sets the fields of h from the second 16 bit word of the header.
+func (h *Header) setFlags(v uint16) {
+
+	h.Response = v&(1<<15) != 0
+	h.Opcode = uint8(v>>11) & 0xf
+	h.Authoritative = v&(1<<10) != 0
+	h.Truncated = v&(1<<9) != 0
+	h.RecursionDesired = v&(1<<8) != 0
+	h.RecursionAvailable = v&(1<<7) != 0
+	h.AuthenticData = v&(1<<5) != 0
+	h.CheckingDisabled = v&(1<<4) != 0
+	h.RCode = uint8(v) & 0xf
+}
+
+// Question is an entry of the question section.
+type Question struct {
+	Name  string
+	Type  Type
+	Class Class
+}
+
+// Resource is a resource record.
+// For the OPT pseudo-record, Class is the UDP payload size and TTL holds the extended RCODE and flags (see NewOPT).
+type Resource struct {
+	Name  string
+	Class Class
+	TTL   uint32
+	Data  RData
+}
+
+// Type returns the type of the record data of r.
+func (r *Resource) Type() Type {
+	return r.Data.Type()
+}
+
+// Message is a DNS message.
+type Message struct {
+	Header
+	Questions   []Question
+	Answers     []Resource
+	Authorities []Resource
+	Additionals []Resource
+}
+
+// EncodeTo appends the message to b with name compression.
+// The counts of the header are set from the length of the sections.
+// If an error is returned, b is left unchanged.
+func (m *Message) EncodeTo(b *bytebuilder.Buffer) error {
+
+	if len(m.Questions) > 1<<16-1 || len(m.Answers) > 1<<16-1 || len(m.Authorities) > 1<<16-1 || len(m.Additionals) > 1<<16-1 {
+		return bytebuilder.WrapFieldError("Message", ErrMalformed)
+	}
+
+	h := m.Header
+	h.QDCount = uint16(len(m.Questions))
+	h.ANCount = uint16(len(m.Answers))
+	h.NSCount = uint16(len(m.Authorities))
+	h.ARCount = uint16(len(m.Additionals))
+
+	// The message is built separately, so b is unchanged if a record fails.
+	msg := bytebuilder.NewEmpty()
+	w := NewBuilder(&msg)
+
+	w.Header(h)
+
+	for i := range m.Questions {
+		if err := w.Question(m.Questions[i]); err != nil {
+			return bytebuilder.WrapFieldError("Message.Questions["+strconv.Itoa(i)+"]", err)
+		}
+	}
+
+	sections := []struct {
+		name string
+		rrs  []Resource
+	}{{"Answers", m.Answers}, {"Authorities", m.Authorities}, {"Additionals", m.Additionals}}
+
+	for _, s := range sections {
+		for i := range s.rrs {
+			if err := w.Resource(s.rrs[i]); err != nil {
+				return bytebuilder.WrapFieldError("Message."+s.name+"["+strconv.Itoa(i)+"]", err)
+			}
+		}
+	}
+
+	b.WriteBytes(msg.Bytes()...)
+
+	return nil
+}
+
+// DecodeFrom reads a message from b. The message must start at the read cursor of b,
+// the compression pointers are relative to it.
+func (m *Message) DecodeFrom(b *bytebuilder.Buffer) error {
+
+	p := NewParser(b)
+
+	h, err := p.Header()
+	if err != nil {
+		return bytebuilder.WrapFieldError("Message.Header", err)
+	}
+
+	v := Message{Header: h}
+
+	for i := 0; i < int(h.QDCount); i++ {
+
+		q, err := p.Question()
+		if err != nil {
+			return bytebuilder.WrapFieldError("Message.Questions["+strconv.Itoa(i)+"]", err)
+		}
+
+		v.Questions = append(v.Questions, q)
+	}
+
+	sections := []struct {
+		name  string
+		count uint16
+		rrs   *[]Resource
+	}{{"Answers", h.ANCount, &v.Answers}, {"Authorities", h.NSCount, &v.Authorities}, {"Additionals", h.ARCount, &v.Additionals}}
+
+	for _, s := range sections {
+		for i := 0; i < int(s.count); i++ {
+
+			r, err := p.Resource()
+			if err != nil {
+				return bytebuilder.WrapFieldError("Message."+s.name+"["+strconv.Itoa(i)+"]", err)
+			}
+
+			*s.rrs = append(*s.rrs, r)
+		}
+	}
+
+	*m = v
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package dns
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		name string
+		m    Message
+	}{
+		{"query", Message{
+			Header:    Header{ID: 0x1234, RecursionDesired: true, QDCount: 1},
+			Questions: []Question{{Name: "example.com.", Type: TypeA, Class: ClassINET}},
+		}},
+		{"response", Message{
+			Header:    Header{ID: 0xbeef, Response: true, Authoritative: true, RCode: RCodeNameError, QDCount: 1, ANCount: 6, ARCount: 1},
+			Questions: []Question{{Name: "example.com.", Type: TypeMX, Class: ClassINET}},
+			Answers: []Resource{
+				{Name: "example.com.", Class: ClassINET, TTL: 60, Data: &A{Addr: netip.MustParseAddr("192.0.2.1")}},
+				{Name: "example.com.", Class: ClassINET, TTL: 60, Data: &AAAA{Addr: netip.MustParseAddr("2001:db8::1")}},
+				{Name: "www.example.com.", Class: ClassINET, TTL: 60, Data: &CNAME{Target: "example.com."}},
+				{Name: "example.com.", Class: ClassINET, TTL: 3600, Data: &MX{Preference: 10, Exchange: "mail.example.com."}},
+				{Name: "example.com.", Class: ClassINET, TTL: 1, Data: &TXT{Texts: []string{"v=spf1 -all", ""}}},
+				{Name: "_sip._tcp.example.com.", Class: ClassINET, TTL: 1, Data: &SRV{Priority: 1, Weight: 2, Port: 5060, Target: "sip.example.com."}},
+			},
+			Additionals: []Resource{
+				{Name: ".", Class: 1232, Data: &OPT{Options: []Option{{Code: 10, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8}}}}},
+			},
+		}},
+		{"unknown type", Message{
+			Header:  Header{ANCount: 1},
+			Answers: []Resource{{Name: "example.com.", Class: ClassINET, Data: &Unknown{RType: 99, Data: []byte{1, 2, 3}}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			big := bytebuilder.NewEmpty()
+
+			if err := tt.m.EncodeTo(&big); err != nil {
+				t.Fatalf("EncodeTo: %s", err)
+			}
+
+			// The byte order of the Buffer must not change the encoding.
+			little := bytebuilder.NewBufferWithEndianness(nil, bytebuilder.LittleEndian)
+
+			if err := tt.m.EncodeTo(&little); err != nil {
+				t.Fatalf("EncodeTo: %s", err)
+			}
+
+			if !bytes.Equal(big.Bytes(), little.Bytes()) {
+				t.Fatalf("little-endian encoding = % x, want % x", little.Bytes(), big.Bytes())
+			}
+
+			var m Message
+
+			if err := m.DecodeFrom(&little); err != nil {
+				t.Fatalf("DecodeFrom: %s", err)
+			}
+
+			if !reflect.DeepEqual(m, tt.m) {
+				t.Fatalf("DecodeFrom = %+v, want %+v", m, tt.m)
+			}
+
+			if !little.Empty() {
+				t.Fatalf("%d bytes left", little.Remaining())
+			}
+		})
+	}
+}
+
+func TestParserName(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		msg    []byte
+		off    int // Offset of the name in msg
+		want   string
+		err    error
+		errOff int // Offset of the error in msg
+	}{
+		{"root", []byte{0}, 0, ".", nil, 0},
+		{"labels", []byte("\x01a\x02bc\x00"), 0, "a.bc.", nil, 0},
+		{"pointer", []byte("\x01a\x00\x01b\xc0\x00"), 3, "b.a.", nil, 0},
+		{"pointer loop", []byte("\x01a\xc0\x00"), 0, "", ErrInvalidPointer, 2},
+		{"forward pointer", []byte("\xc0\x02\x00"), 0, "", ErrInvalidPointer, 0},
+		{"truncated label", []byte("\x03ab"), 0, "", bytebuilder.ErrShortBuffer, 1},
+		{"truncated pointer", []byte("\x01a\xc0"), 0, "", bytebuilder.ErrShortBuffer, 2},
+		{"reserved label type", []byte("\x40"), 0, "", ErrMalformed, 0},
+		{"missing root", []byte("\x01a"), 0, "", bytebuilder.ErrShortBuffer, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// The message starts after a prefix, the offsets of the errors include it.
+			b := bytebuilder.NewBuffer(append([]byte{0xff, 0xff}, tt.msg...))
+			b.Skip(2)
+
+			p := NewParser(&b)
+			b.Skip(tt.off)
+
+			name, err := p.Name()
+
+			if tt.err == nil {
+				if err != nil || name != tt.want {
+					t.Fatalf("Name = %q, %v, want %q", name, err, tt.want)
+				}
+				return
+			}
+
+			var de *bytebuilder.DecodeError
+
+			if !errors.Is(err, tt.err) || !errors.As(err, &de) || de.Offset != 2+tt.errOff {
+				t.Fatalf("Name error = %v, want %v at offset %d", err, tt.err, 2+tt.errOff)
+			}
+		})
+	}
+}
+
+func TestBuilderCompression(t *testing.T) {
+
+	b := bytebuilder.NewEmpty()
+	w := NewBuilder(&b)
+
+	for _, name := range []string{"example.com.", "www.example.com", "example.com."} {
+		if err := w.Name(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []byte("\x07example\x03com\x00\x03www\xc0\x00\xc0\x00")
+
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Fatalf("names = % x, want % x", b.Bytes(), want)
+	}
+
+	for _, name := range []string{"a..b.", string(make([]byte, 64)) + ".com."} {
+		if err := w.Name(name); !errors.Is(err, ErrInvalidName) {
+			t.Fatalf("Name(%q) error = %v, want %v", name, err, ErrInvalidName)
+		}
+	}
+}
+
+// TestBuilderResourceRollback checks that a failed record leaves no bytes and no compression pointers behind.
+func TestBuilderResourceRollback(t *testing.T) {
+
+	// The read prefix checks that the offsets of the Builder do not depend on the read cursor.
+	b := bytebuilder.NewBuffer([]byte{0xff})
+	b.Skip(1)
+
+	w := NewBuilder(&b)
+	w.Header(Header{ANCount: 2})
+
+	a := Resource{Name: "example.com.", Class: ClassINET, TTL: 1, Data: &A{Addr: netip.MustParseAddr("192.0.2.1")}}
+
+	if err := w.Resource(a); err != nil {
+		t.Fatal(err)
+	}
+
+	size := b.Size()
+
+	bad := Resource{Name: "new.example.com.", Class: ClassINET, Data: &TXT{Texts: []string{"ok", string(make([]byte, 256))}}}
+
+	if err := w.Resource(bad); !errors.Is(err, bytebuilder.ErrLengthOverflow) {
+		t.Fatalf("Resource error = %v, want %v", err, bytebuilder.ErrLengthOverflow)
+	}
+
+	if b.Size() != size {
+		t.Fatalf("Size = %d after a failed Resource, want %d", b.Size(), size)
+	}
+
+	// The name of the failed record must not be used as a compression target.
+	good := Resource{Name: "www.new.example.com.", Class: ClassINET, TTL: 2, Data: &TXT{Texts: []string{"ok"}}}
+
+	if err := w.Resource(good); err != nil {
+		t.Fatal(err)
+	}
+
+	var m Message
+
+	if err := m.DecodeFrom(&b); err != nil {
+		t.Fatalf("DecodeFrom: %s", err)
+	}
+
+	if want := []Resource{a, good}; !reflect.DeepEqual(m.Answers, want) {
+		t.Fatalf("Answers = %+v, want %+v", m.Answers, want)
+	}
+}
+
+func FuzzParserName(f *testing.F) {
+
+	f.Add([]byte("\x01a\x02bc\x00"), 0)
+	f.Add([]byte("\x01a\x00\x01b\xc0\x00"), 3)
+	f.Add([]byte("\x01a\xc0\x00"), 0)
+
+	f.Fuzz(func(t *testing.T, msg []byte, off int) {
+
+		if off < 0 || off > len(msg) {
+			return
+		}
+
+		b := bytebuilder.NewBuffer(msg)
+
+		p := NewParser(&b)
+		b.Skip(off)
+
+		name, err := p.Name()
+		if err != nil {
+			return
+		}
+
+		// A name read successfully can be written back and read again.
+		w := bytebuilder.NewEmpty()
+
+		if err := NewBuilder(&w).Name(name); err != nil {
+			if errors.Is(err, ErrInvalidName) {
+				// Labels with a dot or an empty label can not be written.
+				return
+			}
+			t.Fatalf("Name(%q): %s", name, err)
+		}
+
+		again, err := NewParser(&w).Name()
+		if err != nil || again != name {
+			t.Fatalf("Name = %q, %v, want %q", again, err, name)
+		}
+	})
+}
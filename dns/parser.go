@@ -0,0 +1,303 @@
+package dns
+
+import (
+	"encoding/binary"
+	"net/netip"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// Parser reads a message from a Buffer.
+// The header, the questions and the records must be read in order, as many as the counts of the header.
+type Parser struct {
+	b     *bytebuilder.Buffer
+	msg   []byte // The message from its start, the target of the compression pointers
+	start int    // Offset of the message in b
+}
+
+// NewParser returns a Parser that reads a message starting at the read cursor of b.
+func NewParser(b *bytebuilder.Buffer) *Parser {
+	return &Parser{b: b, msg: b.Bytes(), start: b.Offset()}
+}
+
+// offset returns the current offset from the start of the message.
+func (p *Parser) offset() int {
+	return p.b.Offset() - p.start
+}
+
+// Header reads the header.
+func (p *Parser) Header() (Header, error) {
+
+	var h Header
+
+	v, err := p.b.ReadBytesE(HeaderLength)
+	if err != nil {
+		return h, err
+	}
+
+	h.ID = binary.BigEndian.Uint16(v[0:])
+	h.setFlags(binary.BigEndian.Uint16(v[2:]))
+	h.QDCount = binary.BigEndian.Uint16(v[4:])
+	h.ANCount = binary.BigEndian.Uint16(v[6:])
+	h.NSCount = binary.BigEndian.Uint16(v[8:])
+	h.ARCount = binary.BigEndian.Uint16(v[10:])
+
+	return h, nil
+}
+
+// Question reads a question.
+func (p *Parser) Question() (Question, error) {
+
+	var q Question
+
+	name, err := p.Name()
+	if err != nil {
+		return q, bytebuilder.WrapFieldError("Question.Name", err)
+	}
+
+	v, err := p.b.ReadBytesE(4)
+	if err != nil {
+		return q, err
+	}
+
+	q.Name = name
+	q.Type = Type(binary.BigEndian.Uint16(v[0:]))
+	q.Class = Class(binary.BigEndian.Uint16(v[2:]))
+
+	return q, nil
+}
+
+// Resource reads a resource record.
+// The data of the types not implemented by this package is returned as *Unknown.
+func (p *Parser) Resource() (Resource, error) {
+
+	var r Resource
+
+	name, err := p.Name()
+	if err != nil {
+		return r, bytebuilder.WrapFieldError("Resource.Name", err)
+	}
+
+	v, err := p.b.ReadBytesE(10)
+	if err != nil {
+		return r, err
+	}
+
+	r.Name = name
+	typ := Type(binary.BigEndian.Uint16(v[0:]))
+	r.Class = Class(binary.BigEndian.Uint16(v[2:]))
+	r.TTL = binary.BigEndian.Uint32(v[4:])
+	n := int(binary.BigEndian.Uint16(v[8:]))
+
+	if p.b.Remaining() < n {
+		_, err := p.b.ReadBytesE(n)
+		return r, bytebuilder.WrapFieldError("Resource.Data", err)
+	}
+
+	// The data is read from b, not from a child Buffer, so the names can point into the message.
+	end := p.b.Offset() + n
+
+	if r.Data, err = p.rdata(typ, n); err != nil {
+		return r, bytebuilder.WrapFieldError("Resource.Data", err)
+	}
+
+	if p.b.Offset() != end {
+		return r, bytebuilder.WrapFieldError("Resource.Data", ErrMalformed)
+	}
+
+	return r, nil
+}
+
+// rdata reads the record data of typ with length n.
+func (p *Parser) rdata(typ Type, n int) (RData, error) {
+
+	switch typ {
+	case TypeA, TypeAAAA:
+
+		if (typ == TypeA && n != 4) || (typ == TypeAAAA && n != 16) {
+			return nil, ErrMalformed
+		}
+
+		v := p.b.ReadBytes(n)
+		addr, _ := netip.AddrFromSlice(v)
+
+		if typ == TypeA {
+			return &A{Addr: addr}, nil
+		}
+
+		return &AAAA{Addr: addr}, nil
+
+	case TypeCNAME:
+
+		name, err := p.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		return &CNAME{Target: name}, nil
+
+	case TypeMX:
+
+		pref, err := p.b.ReadBigUint16E()
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := p.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		return &MX{Preference: pref, Exchange: name}, nil
+
+	case TypeTXT:
+
+		d := &TXT{}
+		end := p.b.Offset() + n
+
+		for p.b.Offset() < end {
+
+			v, err := p.b.ReadVectorE(8)
+			if err != nil {
+				return nil, err
+			}
+
+			d.Texts = append(d.Texts, string(v))
+		}
+
+		return d, nil
+
+	case TypeSRV:
+
+		v, err := p.b.ReadBytesE(6)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := p.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		return &SRV{
+			Priority: binary.BigEndian.Uint16(v[0:]),
+			Weight:   binary.BigEndian.Uint16(v[2:]),
+			Port:     binary.BigEndian.Uint16(v[4:]),
+			Target:   name,
+		}, nil
+
+	case TypeOPT:
+
+		d := &OPT{}
+		end := p.b.Offset() + n
+
+		for p.b.Offset() < end {
+
+			code, err := p.b.ReadBigUint16E()
+			if err != nil {
+				return nil, err
+			}
+
+			size, err := p.b.ReadBigUint16E()
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := p.b.ReadBytesE(int(size))
+			if err != nil {
+				return nil, err
+			}
+
+			d.Options = append(d.Options, Option{Code: code, Data: append([]byte{}, data...)})
+		}
+
+		return d, nil
+
+	default:
+
+		v := p.b.ReadBytes(n)
+
+		return &Unknown{RType: typ, Data: append([]byte{}, v...)}, nil
+	}
+}
+
+// Name reads a domain name and follows the compression pointers.
+// The name is returned with a trailing dot, the root is ".".
+//
+// Every pointer must point before the previous one (or before itself for the first),
+// so the pointers can not form a loop. Otherwise ErrInvalidPointer is returned.
+func (p *Parser) Name() (string, error) {
+
+	var (
+		name  []byte
+		pos   = p.offset()
+		limit = pos // The next pointer must point before limit
+		end   = -1  // The offset after the name in the message, set by the first pointer
+	)
+
+	for {
+
+		if pos >= len(p.msg) {
+			return "", &bytebuilder.DecodeError{Op: "Name", Offset: p.b.BaseOffset() + p.start + pos, Err: bytebuilder.ErrShortBuffer}
+		}
+
+		c := int(p.msg[pos])
+
+		switch c & 0xc0 {
+		case 0x00:
+
+			pos++
+
+			if c == 0 {
+
+				if end < 0 {
+					end = pos
+				}
+
+				if len(name) == 0 {
+					name = append(name, '.')
+				}
+
+				p.b.Skip(end - p.offset())
+
+				return string(name), nil
+			}
+
+			if len(p.msg)-pos < c {
+				return "", &bytebuilder.DecodeError{Op: "Name", Offset: p.b.BaseOffset() + p.start + pos, Want: c, Have: len(p.msg) - pos, Err: bytebuilder.ErrShortBuffer}
+			}
+
+			name = append(name, p.msg[pos:pos+c]...)
+			name = append(name, '.')
+			pos += c
+
+			// The wire form has one more byte than the presentation form (the root label).
+			if len(name)+1 > 255 {
+				return "", &bytebuilder.DecodeError{Op: "Name", Offset: p.b.BaseOffset() + p.start + pos, Err: ErrInvalidName}
+			}
+
+		case 0xc0:
+
+			if len(p.msg)-pos < 2 {
+				return "", &bytebuilder.DecodeError{Op: "Name", Offset: p.b.BaseOffset() + p.start + pos, Want: 2, Have: len(p.msg) - pos, Err: bytebuilder.ErrShortBuffer}
+			}
+
+			ptr := int(binary.BigEndian.Uint16(p.msg[pos:]) & maxPointer)
+
+			if ptr >= limit {
+				return "", &bytebuilder.DecodeError{Op: "Name", Offset: p.b.BaseOffset() + p.start + pos, Err: ErrInvalidPointer}
+			}
+
+			if end < 0 {
+				end = pos + 2
+			}
+
+			pos = ptr
+			limit = ptr
+
+		default:
+			// The 0x40 and 0x80 label types are reserved.
+			return "", &bytebuilder.DecodeError{Op: "Name", Offset: p.b.BaseOffset() + p.start + pos, Err: ErrMalformed}
+		}
+	}
+}
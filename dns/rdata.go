@@ -0,0 +1,211 @@
+package dns
+
+import (
+	"net/netip"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// RData is the data of a resource record.
+// The types of this package implement it, the other types are read as *Unknown.
+type RData interface {
+	// Type returns the type of the resource record.
+	Type() Type
+
+	// encode writes the data with w.
+	encode(w *Builder) error
+}
+
+// A is the data of an A record.
+type A struct {
+	Addr netip.Addr
+}
+
+// Type returns TypeA.
+func (*A) Type() Type { return TypeA }
+
+func (d *A) encode(w *Builder) error {
+
+	if !d.Addr.Is4() {
+		return ErrMalformed
+	}
+
+	v := d.Addr.As4()
+	w.b.WriteBytes(v[:]...)
+
+	return nil
+}
+
+// AAAA is the data of an AAAA record.
+type AAAA struct {
+	Addr netip.Addr
+}
+
+// Type returns TypeAAAA.
+func (*AAAA) Type() Type { return TypeAAAA }
+
+func (d *AAAA) encode(w *Builder) error {
+
+	if !d.Addr.Is6() {
+		return ErrMalformed
+	}
+
+	v := d.Addr.As16()
+	w.b.WriteBytes(v[:]...)
+
+	return nil
+}
+
+// CNAME is the data of a CNAME record.
+type CNAME struct {
+	Target string
+}
+
+// Type returns TypeCNAME.
+func (*CNAME) Type() Type { return TypeCNAME }
+
+func (d *CNAME) encode(w *Builder) error {
+	return w.Name(d.Target)
+}
+
+// MX is the data of an MX record.
+type MX struct {
+	Preference uint16
+	Exchange   string
+}
+
+// Type returns TypeMX.
+func (*MX) Type() Type { return TypeMX }
+
+func (d *MX) encode(w *Builder) error {
+
+	w.b.WriteBigUint16(d.Preference)
+
+	return w.Name(d.Exchange)
+}
+
+// TXT is the data of a TXT record, each string is at most 255 bytes long.
+type TXT struct {
+	Texts []string
+}
+
+// Type returns TypeTXT.
+func (*TXT) Type() Type { return TypeTXT }
+
+func (d *TXT) encode(w *Builder) error {
+
+	// A TXT record has at least one string.
+	if len(d.Texts) == 0 {
+		w.b.WriteUint8(0)
+		return nil
+	}
+
+	for _, t := range d.Texts {
+		if err := w.b.WriteVectorE([]byte(t), 8); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SRV is the data of an SRV record (RFC 2782). The Target is not compressed.
+type SRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// Type returns TypeSRV.
+func (*SRV) Type() Type { return TypeSRV }
+
+func (d *SRV) encode(w *Builder) error {
+
+	w.b.WriteBigUint16(d.Priority)
+	w.b.WriteBigUint16(d.Weight)
+	w.b.WriteBigUint16(d.Port)
+
+	return w.name(d.Target, false)
+}
+
+// Option is an EDNS0 option.
+type Option struct {
+	Code uint16
+	Data []byte
+}
+
+// OPT is the data of the OPT pseudo-record of EDNS0 (RFC 6891).
+type OPT struct {
+	Options []Option
+}
+
+// Type returns TypeOPT.
+func (*OPT) Type() Type { return TypeOPT }
+
+func (d *OPT) encode(w *Builder) error {
+
+	for _, o := range d.Options {
+
+		if len(o.Data) > 0xffff {
+			return &bytebuilder.EncodeError{Op: "OPT", Length: len(o.Data), Err: bytebuilder.ErrLengthOverflow}
+		}
+
+		w.b.WriteBigUint16(o.Code)
+		w.b.WriteBigUint16(uint16(len(o.Data)))
+		w.b.WriteBytes(o.Data...)
+	}
+
+	return nil
+}
+
+// Unknown is the data of a record with a type not implemented by this package.
+type Unknown struct {
+	RType Type
+	Data  []byte
+}
+
+// Type returns RType.
+func (d *Unknown) Type() Type { return d.RType }
+
+func (d *Unknown) encode(w *Builder) error {
+
+	w.b.WriteBytes(d.Data...)
+
+	return nil
+}
+
+// ednsDO is the DNSSEC OK bit in the TTL of the OPT record.
+const ednsDO = 1 << 15
+
+// NewOPT returns the OPT pseudo-record with the UDP payload size, the DNSSEC OK bit and the options.
+func NewOPT(udpSize uint16, dnssecOK bool, options ...Option) Resource {
+
+	r := Resource{Name: ".", Class: Class(udpSize), Data: &OPT{Options: options}}
+
+	if dnssecOK {
+		r.TTL |= ednsDO
+	}
+
+	return r
+}
+
+// UDPSize returns the UDP payload size of an OPT record.
+func (r *Resource) UDPSize() uint16 {
+	return uint16(r.Class)
+}
+
+// ExtendedRCode returns the upper 8 bits of the extended RCODE of an OPT record.
+func (r *Resource) ExtendedRCode() uint8 {
+	return uint8(r.TTL >> 24)
+}
+
+// EDNSVersion returns the EDNS version of an OPT record.
+func (r *Resource) EDNSVersion() uint8 {
+	return uint8(r.TTL >> 16)
+}
+
+// DNSSECOK returns the DNSSEC OK bit of an OPT record.
+func (r *Resource) DNSSECOK() bool {
+	return r.TTL&ednsDO != 0
+}
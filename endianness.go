@@ -1,8 +1,123 @@
 package bytebuilder
 
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Endianness is a byte order.
+// It implements binary.ByteOrder and binary.AppendByteOrder,
+// so it can be used with encoding/binary and with codecs that expect a binary.ByteOrder.
 type Endianness byte
 
 const (
 	LittleEndian Endianness = iota
 	BigEndian
 )
+
+var (
+	_ binary.ByteOrder       = LittleEndian
+	_ binary.AppendByteOrder = LittleEndian
+)
+
+// byteOrder is implemented by binary.LittleEndian and binary.BigEndian.
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// EndiannessOf returns the Endianness of o (eg.: binary.BigEndian, binary.LittleEndian or binary.NativeEndian).
+func EndiannessOf(o binary.ByteOrder) Endianness {
+
+	var b [2]byte
+
+	o.PutUint16(b[:], 1)
+
+	if b[0] == 1 {
+		return LittleEndian
+	}
+
+	return BigEndian
+}
+
+// order returns the encoding/binary counterpart of e.
+// If e is an invalid value, this function panics.
+func (e Endianness) order() byteOrder {
+
+	switch e {
+	case LittleEndian:
+		return binary.LittleEndian
+	case BigEndian:
+		return binary.BigEndian
+	default:
+		panic(fmt.Sprintf("Invalid Endianness: %d", e))
+	}
+}
+
+// Opposite returns the other byte order.
+func (e Endianness) Opposite() Endianness {
+
+	if e == LittleEndian {
+		return BigEndian
+	}
+
+	return LittleEndian
+}
+
+// String returns the name of e, the same as the String method of the encoding/binary byte orders.
+func (e Endianness) String() string {
+
+	switch e {
+	case LittleEndian:
+		return "LittleEndian"
+	case BigEndian:
+		return "BigEndian"
+	default:
+		return fmt.Sprintf("Endianness(%d)", e)
+	}
+}
+
+// Uint16 returns the first two bytes of b as an uint16 in byte order e.
+func (e Endianness) Uint16(b []byte) uint16 {
+	return e.order().Uint16(b)
+}
+
+// Uint32 returns the first four bytes of b as an uint32 in byte order e.
+func (e Endianness) Uint32(b []byte) uint32 {
+	return e.order().Uint32(b)
+}
+
+// Uint64 returns the first eight bytes of b as an uint64 in byte order e.
+func (e Endianness) Uint64(b []byte) uint64 {
+	return e.order().Uint64(b)
+}
+
+// PutUint16 stores v into the first two bytes of b in byte order e.
+func (e Endianness) PutUint16(b []byte, v uint16) {
+	e.order().PutUint16(b, v)
+}
+
+// PutUint32 stores v into the first four bytes of b in byte order e.
+func (e Endianness) PutUint32(b []byte, v uint32) {
+	e.order().PutUint32(b, v)
+}
+
+// PutUint64 stores v into the first eight bytes of b in byte order e.
+func (e Endianness) PutUint64(b []byte, v uint64) {
+	e.order().PutUint64(b, v)
+}
+
+// AppendUint16 appends v at the end of b in byte order e and returns the extended slice.
+func (e Endianness) AppendUint16(b []byte, v uint16) []byte {
+	return e.order().AppendUint16(b, v)
+}
+
+// AppendUint32 appends v at the end of b in byte order e and returns the extended slice.
+func (e Endianness) AppendUint32(b []byte, v uint32) []byte {
+	return e.order().AppendUint32(b, v)
+}
+
+// AppendUint64 appends v at the end of b in byte order e and returns the extended slice.
+func (e Endianness) AppendUint64(b []byte, v uint64) []byte {
+	return e.order().AppendUint64(b, v)
+}
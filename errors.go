@@ -0,0 +1,95 @@
+package bytebuilder
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrShortBuffer is returned when there is not enough bytes to read.
+	ErrShortBuffer = errors.New("short buffer")
+
+	// ErrInvalidBitSize is returned when bitSize is not a supported value.
+	ErrInvalidBitSize = errors.New("invalid bitSize value")
+
+	// ErrInvalidLength is returned when a negative length is requested.
+	ErrInvalidLength = errors.New("invalid length")
+
+	// ErrLengthOverflow is returned when a length does not fit into the target type.
+	ErrLengthOverflow = errors.New("length overflow")
+
+	// ErrValueOverflow is returned when a value does not fit into the encoded size.
+	ErrValueOverflow = errors.New("value overflow")
+
+	// ErrNilPointer is returned when a nil pointer can not be encoded.
+	ErrNilPointer = errors.New("nil pointer")
+
+	// ErrTrailingData is returned when bytes are left unread after parsing.
+	ErrTrailingData = errors.New("trailing data")
+)
+
+// DecodeError records a failed read and the position where it happened.
+type DecodeError struct {
+	Op     string // The operation that failed (eg.: "ReadUint16")
+	Offset int    // Position of the failed read from the start of the Buffer, -1 if unknown
+	Want   int    // Number of bytes wanted
+	Have   int    // Number of bytes available
+	Err    error  // The underlying error (eg.: ErrShortBuffer)
+}
+
+func (e *DecodeError) Error() string {
+
+	s := "bytebuilder: " + e.Op
+
+	if e.Offset >= 0 {
+		s += fmt.Sprintf(" at offset %d", e.Offset)
+	}
+
+	s += ": " + e.Err.Error()
+
+	switch {
+	case errors.Is(e.Err, ErrTrailingData):
+		s += fmt.Sprintf(" (%d bytes left)", e.Have)
+	case e.Want > 0:
+		s += fmt.Sprintf(" (want %d bytes, have %d)", e.Want, e.Have)
+	}
+
+	return s
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// withOp sets the operation of err to op if err is a *DecodeError.
+// Used by the byte order neutral methods to report their own name instead of the method they dispatch to.
+func withOp(err error, op string) error {
+
+	if e, ok := err.(*DecodeError); ok {
+		e.Op = op
+	}
+
+	return err
+}
+
+// EncodeError records a failed write.
+type EncodeError struct {
+	Op     string // The operation that failed (eg.: "AddUint8LengthPrefixed")
+	Length int    // The length that could not be encoded
+	Err    error  // The underlying error (eg.: ErrLengthOverflow)
+}
+
+func (e *EncodeError) Error() string {
+
+	s := "bytebuilder: " + e.Op + ": " + e.Err.Error()
+
+	if e.Length > 0 {
+		s += fmt.Sprintf(" (length %d)", e.Length)
+	}
+
+	return s
+}
+
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,96 @@
+package bytebuilder
+
+import "errors"
+
+// ErrLimitExceeded is returned when a decoding limit set by Limits is exceeded.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits restricts the resources used while decoding untrusted input,
+// so a hostile length prefix can not make the decoder allocate large amounts of memory.
+// A zero field means no limit.
+type Limits struct {
+	// MaxVectorLength is the maximum length of a single vector (eg.: ReadVector, ReadLengthPrefixed).
+	MaxVectorLength int
+
+	// MaxAllocation is the maximum number of bytes allocated in total by a decoder
+	// (eg.: StreamReader.ReadBytes, Unmarshal).
+	MaxAllocation int64
+}
+
+// checkVector returns an error wrapping ErrLimitExceeded if n exceeds MaxVectorLength.
+func (l *Limits) checkVector(n uint64) error {
+
+	if l.MaxVectorLength > 0 && n > uint64(l.MaxVectorLength) {
+		return ErrLimitExceeded
+	}
+
+	return nil
+}
+
+// allocator counts the allocated bytes against MaxAllocation.
+type allocator struct {
+	limits Limits
+	n      int64 // number of bytes allocated
+}
+
+// alloc records the allocation of n bytes.
+// Returns ErrLimitExceeded if the total exceeds MaxAllocation.
+func (a *allocator) alloc(n int) error {
+
+	if a.limits.MaxAllocation > 0 && a.n+int64(n) > a.limits.MaxAllocation {
+		return ErrLimitExceeded
+	}
+
+	a.n += int64(n)
+
+	return nil
+}
+
+// Limits returns the decoding limits of b.
+func (b *Buffer) Limits() Limits {
+
+	if b.alloc == nil {
+		return Limits{}
+	}
+
+	return b.alloc.limits
+}
+
+// SetLimits sets the decoding limits of b.
+// The limits and the allocation counter are shared with the child Buffers created by the ReadLengthPrefixed methods.
+// Reading from a Buffer does not allocate, so MaxAllocation is applied by the decoders built on it (eg.: Unmarshal).
+func (b *Buffer) SetLimits(l Limits) {
+	b.alloc = &allocator{limits: l}
+}
+
+// Allocate records the allocation of n bytes by a decoder built on b against MaxAllocation.
+// Returns a *DecodeError wrapping ErrLimitExceeded if the total exceeds the limit.
+// It is used by the code generated by cmd/bytebuildergen.
+func (b *Buffer) Allocate(n int) error {
+
+	if b.alloc == nil {
+		return nil
+	}
+
+	if err := b.alloc.alloc(n); err != nil {
+		return &DecodeError{Op: "Allocate", Offset: b.base + b.off, Err: err}
+	}
+
+	return nil
+}
+
+// SetLimits sets the decoding limits of the underlying Buffer.
+func (d *Decoder) SetLimits(l Limits) {
+	d.b.SetLimits(l)
+}
+
+// Limits returns the decoding limits of s.
+func (s *StreamReader) Limits() Limits {
+	return s.alloc.limits
+}
+
+// SetLimits sets the decoding limits of s.
+// MaxAllocation is applied to the total number of bytes returned by ReadBytes and ReadVector.
+func (s *StreamReader) SetLimits(l Limits) {
+	s.alloc = allocator{limits: l}
+}
@@ -0,0 +1,140 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestWriteVectorOverflow(t *testing.T) {
+
+	tests := []struct {
+		n       int
+		bitSize int
+		err     error
+	}{
+		{255, 8, nil},
+		{256, 8, ErrLengthOverflow},
+		{300, 8, ErrLengthOverflow},
+		{1 << 16, 16, ErrLengthOverflow},
+		{1 << 16, 24, nil},
+		{300, VarintLength, nil},
+		{300, QUICVarintLength, nil},
+		{1, 12, ErrInvalidBitSize},
+	}
+
+	for _, tt := range tests {
+
+		v := make([]byte, tt.n)
+
+		b := NewBuffer([]byte{0xaa})
+
+		err := b.WriteVectorE(v, tt.bitSize)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("WriteVectorE(%d bytes, %d) = %v, want %v", tt.n, tt.bitSize, err, tt.err)
+		}
+
+		var ee *EncodeError
+
+		if err != nil && (!errors.As(err, &ee) || ee.Op != "WriteVector" || ee.Length != tt.n || b.Size() != 1) {
+			t.Fatalf("WriteVectorE(%d bytes, %d) = %v, Size = %d", tt.n, tt.bitSize, err, b.Size())
+		}
+
+		s := NewStreamWriter(io.Discard)
+		s.WriteVector(v, tt.bitSize)
+
+		if err := s.Flush(); !errors.Is(err, tt.err) {
+			t.Fatalf("StreamWriter.WriteVector(%d bytes, %d) = %v, want %v", tt.n, tt.bitSize, err, tt.err)
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+
+	b := NewEmpty()
+	b.WriteUint8(0xaa)
+	b.WriteVector(make([]byte, 10), 16)
+	b.WriteVector(make([]byte, 100), 16)
+	b.SetLimits(Limits{MaxVectorLength: 50})
+	b.Skip(1)
+
+	if _, err := b.ReadVectorE(16); err != nil {
+		t.Fatalf("ReadVectorE under the limit: %s", err)
+	}
+
+	var de *DecodeError
+
+	if _, err := b.ReadVectorE(16); !errors.As(err, &de) || de.Offset != 13 || !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("ReadVectorE over the limit = %v, want ErrLimitExceeded at offset 13", err)
+	}
+
+	// The limits apply to the child Buffers too, the vector of the child claims 60 bytes.
+	b = NewBuffer([]byte{0x00, 0x04, 0x00, 0x3c, 0x01, 0x02})
+	b.SetLimits(Limits{MaxVectorLength: 50})
+
+	var c Buffer
+
+	if err := b.ReadUint16LengthPrefixed(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Limits().MaxVectorLength != 50 {
+		t.Fatalf("child Limits = %+v", c.Limits())
+	}
+
+	if err := c.ReadUint16LengthPrefixed(&c); !errors.As(err, &de) || de.Offset != 2 || !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("child ReadUint16LengthPrefixed = %v, want ErrLimitExceeded at offset 2", err)
+	}
+}
+
+func TestStreamLimits(t *testing.T) {
+
+	b := NewEmpty()
+	b.WriteVector(make([]byte, 10), 16)
+	b.WriteVector(make([]byte, 10), 16)
+	b.WriteVector(make([]byte, 100), 32)
+
+	s := NewStreamReader(bytes.NewReader(b.Bytes()))
+	s.SetLimits(Limits{MaxVectorLength: 50, MaxAllocation: 15})
+
+	if _, err := s.ReadVector(16); err != nil {
+		t.Fatalf("ReadVector under the limits: %s", err)
+	}
+
+	var de *DecodeError
+
+	if _, err := s.ReadVector(16); !errors.As(err, &de) || !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("ReadVector over MaxAllocation = %v, want ErrLimitExceeded", err)
+	}
+
+	s = NewStreamReader(bytes.NewReader(b.Bytes()))
+	s.SetLimits(Limits{MaxVectorLength: 50})
+	s.Skip(24)
+
+	if _, err := s.ReadVector(32); !errors.As(err, &de) || de.Offset != 24 || !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("ReadVector over MaxVectorLength = %v, want ErrLimitExceeded at offset 24", err)
+	}
+
+	// A hostile length does not allocate before the data arrives.
+	s = NewStreamReader(bytes.NewReader([]byte{0x7f, 0xff, 0xff, 0xff, 1, 2, 3}))
+
+	if _, err := s.ReadVector(32); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadVector with a hostile length = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestBuilderChildLimits(t *testing.T) {
+
+	b := NewEmpty()
+	b.SetLimits(Limits{MaxVectorLength: 1})
+
+	b.AddUint8LengthPrefixed(func(c *Buffer) error {
+
+		if c.Limits().MaxVectorLength != 1 {
+			t.Fatal("the child does not inherit the Limits")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,702 @@
+package bytebuilder
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Marshaler is implemented by types that can encode themselves into a Buffer.
+// Marshal calls EncodeTo instead of walking the value with reflection.
+type Marshaler interface {
+	EncodeTo(b *Buffer) error
+}
+
+// Unmarshaler is implemented by types that can decode themselves from a Buffer.
+// Unmarshal calls DecodeFrom instead of walking the value with reflection.
+type Unmarshaler interface {
+	DecodeFrom(b *Buffer) error
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// ErrMaxDepth is returned by Marshal and Unmarshal when the value is nested deeper than maxDepth
+// (eg.: a cyclic pointer or a recursive type that is decoded without consuming any byte).
+var ErrMaxDepth = errors.New("maximum nesting depth exceeded")
+
+// maxDepth is the maximum nesting depth of the values encoded by Marshal and decoded by Unmarshal.
+const maxDepth = 1000
+
+// FieldError records an error of Marshal or Unmarshal and the path of the failing field
+// (eg.: "Header.Extensions[2].Length").
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return "bytebuilder: " + e.Path + ": " + strings.TrimPrefix(e.Err.Error(), "bytebuilder: ")
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// WrapFieldError wraps err into a *FieldError with path, unless it is nil or already a *FieldError.
+// It is used by the code generated by cmd/bytebuildergen.
+func WrapFieldError(path string, err error) error {
+	return fieldError(path, err)
+}
+
+// fieldError wraps err into a *FieldError, unless it is already one.
+func fieldError(path string, err error) error {
+
+	var fe *FieldError
+
+	if err == nil || errors.As(err, &fe) {
+		return err
+	}
+
+	return &FieldError{Path: path, Err: err}
+}
+
+// Marshal returns the encoding of v.
+//
+// Structs are encoded field by field in declaration order, unexported fields are skipped.
+// The encoding of a field is controlled by the `bb:"..."` struct tag:
+//
+//	Length   uint32 `bb:"uint24"`      // 3 byte integer
+//	Flags    uint16 `bb:"le"`          // little-endian
+//	Payload  []byte `bb:"vector,16"`   // uint16 length-prefixed bytes
+//	Random   []byte `bb:"fixed,32"`    // exactly 32 bytes
+//	Suites   []uint16 `bb:"vector,16"` // uint16 length (in bytes) then the elements
+//	ID       int64  `bb:"varint"`      // zigzag LEB128 varint
+//	internal int    `bb:"-"`           // skipped
+//
+// Integers without a width option are encoded with the size of their type (int and uint as 64-bit),
+// in the byte order of the Buffer (big-endian by default).
+// Booleans are encoded as a single byte, strings and slices require a vector or fixed option.
+// Types implementing Marshaler encode themselves.
+// Errors are returned as a *FieldError naming the failing field.
+func Marshal(v any) ([]byte, error) {
+
+	b := NewEmpty()
+
+	if err := b.Marshal(v); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Marshal appends the encoding of v at the end of b. See the package level Marshal for the encoding rules.
+// If an error is returned, b is left unchanged.
+func (b *Buffer) Marshal(v any) error {
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return errors.New("bytebuilder: Marshal(nil)")
+	}
+
+	// Make the value addressable, so methods with pointer receivers are found.
+	p := reflect.New(rv.Type())
+	p.Elem().Set(rv)
+
+	n := len(b.b)
+
+	if err := encodeValue(b, p.Elem(), FieldTag{Fixed: -1}, typePath(rv.Type()), 0); err != nil {
+		b.b = b.b[:n]
+		return err
+	}
+
+	return nil
+}
+
+// Unmarshal decodes data into the value pointed to by v. See Marshal for the encoding rules.
+// Every byte of data must be consumed, otherwise ErrTrailingData is returned.
+// Types implementing Unmarshaler decode themselves.
+func Unmarshal(data []byte, v any) error {
+
+	b := NewBuffer(data)
+
+	if err := b.Unmarshal(v); err != nil {
+		return err
+	}
+
+	return b.ExpectEmpty()
+}
+
+// Unmarshal decodes the value pointed to by v from the read cursor of b. See Marshal for the encoding rules.
+// The allocated slices and strings are counted against the MaxAllocation limit of b.
+func (b *Buffer) Unmarshal(v any) error {
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("bytebuilder: Unmarshal(non-pointer %T)", v)
+	}
+
+	return decodeValue(b, rv.Elem(), FieldTag{Fixed: -1}, typePath(rv.Type().Elem()), 0)
+}
+
+// typePath returns the name of t used as the root of the field paths.
+func typePath(t reflect.Type) string {
+
+	if t.Name() != "" {
+		return t.Name()
+	}
+
+	return t.String()
+}
+
+// structField is a field of a struct with its parsed tag.
+type structField struct {
+	index int
+	name  string
+	tag   FieldTag
+}
+
+// cachedStruct is the cached field list of a struct type.
+type cachedStruct struct {
+	fields []structField
+	err    error  // the error of the invalid tag
+	errAt  string // the name of the field with the invalid tag
+}
+
+// structCache maps reflect.Type to cachedStruct.
+var structCache sync.Map
+
+// structFields returns the encoded fields of the struct type t.
+// path is the path of the struct used in the returned error.
+func structFields(t reflect.Type, path string) ([]structField, error) {
+
+	c, ok := structCache.Load(t)
+	if !ok {
+		c = loadStructFields(t)
+		structCache.Store(t, c)
+	}
+
+	if cs := c.(cachedStruct); cs.err != nil {
+		return nil, &FieldError{Path: path + "." + cs.errAt, Err: cs.err}
+	}
+
+	return c.(cachedStruct).fields, nil
+}
+
+// loadStructFields parses the fields of the struct type t.
+func loadStructFields(t reflect.Type) cachedStruct {
+
+	var c cachedStruct
+
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, err := ParseFieldTag(f.Tag.Get("bb"))
+		if err != nil {
+			return cachedStruct{err: err, errAt: f.Name}
+		}
+
+		if tag.Skip {
+			continue
+		}
+
+		c.fields = append(c.fields, structField{index: i, name: f.Name, tag: tag})
+	}
+
+	return c
+}
+
+// intSize returns the size in bytes of an integer of type t with tag.
+func (t FieldTag) intSize(typ reflect.Type) int {
+
+	if t.Size > 0 {
+		return t.Size
+	}
+
+	if typ.Kind() == reflect.Int || typ.Kind() == reflect.Uint {
+		return 8
+	}
+
+	return int(typ.Size())
+}
+
+// isByteType returns whether the elements of t can be copied as raw bytes.
+func isByteType(t reflect.Type) bool {
+	return t.Kind() == reflect.Uint8 && !reflect.PointerTo(t).Implements(marshalerType) && !reflect.PointerTo(t).Implements(unmarshalerType)
+}
+
+// encodeValue appends the encoding of v to b.
+func encodeValue(b *Buffer, v reflect.Value, tag FieldTag, path string, depth int) error {
+
+	if depth > maxDepth {
+		return fieldError(path, ErrMaxDepth)
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return fieldError(path, v.Addr().Interface().(Marshaler).EncodeTo(b))
+	}
+
+	if v.Type().Implements(marshalerType) {
+		return fieldError(path, v.Interface().(Marshaler).EncodeTo(b))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			b.WriteUint8(1)
+		} else {
+			b.WriteUint8(0)
+		}
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fieldError(path, encodeInt(b, v.Int(), tag, tag.intSize(v.Type())))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fieldError(path, encodeUint(b, v.Uint(), tag, tag.intSize(v.Type())))
+
+	case reflect.Float32:
+		b.b = appendUint(b.b, uint64(math.Float32bits(float32(v.Float()))), 4, tag.little(b))
+		return nil
+
+	case reflect.Float64:
+		b.b = appendUint(b.b, math.Float64bits(v.Float()), 8, tag.little(b))
+		return nil
+
+	case reflect.String:
+		return fieldError(path, encodeBytes(b, []byte(v.String()), tag))
+
+	case reflect.Slice:
+		if isByteType(v.Type().Elem()) {
+			return fieldError(path, encodeBytes(b, v.Bytes(), tag))
+		}
+		return encodeSlice(b, v, tag, path, depth)
+
+	case reflect.Array:
+		if tag.Vector != 0 || (tag.Fixed >= 0 && tag.Fixed != v.Len()) {
+			return fieldError(path, fmt.Errorf("vector or fixed option does not match array of length %d", v.Len()))
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(b, v.Index(i), tag.ElemTag(), fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Struct:
+		fields, err := structFields(v.Type(), path)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := encodeValue(b, v.Field(f.index), f.tag, path+"."+f.name, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return fieldError(path, ErrNilPointer)
+		}
+		return encodeValue(b, v.Elem(), tag, path, depth+1)
+
+	default:
+		return fieldError(path, fmt.Errorf("unsupported type %s", v.Type()))
+	}
+}
+
+// encodeInt appends a signed integer of size bytes or as a varint.
+func encodeInt(b *Buffer, v int64, tag FieldTag, size int) error {
+
+	switch tag.Varint {
+	case VarintLength:
+		b.WriteVarint(v)
+		return nil
+	case QUICVarintLength:
+		return errors.New("quicvarint requires an unsigned integer")
+	}
+
+	if size < 8 && (v < -1<<(8*size-1) || v >= 1<<(8*size-1)) {
+		return fmt.Errorf("%w: %d does not fit into %d bytes", ErrValueOverflow, v, size)
+	}
+
+	b.b = appendUint(b.b, uint64(v), size, tag.little(b))
+
+	return nil
+}
+
+// encodeUint appends an unsigned integer of size bytes or as a varint.
+func encodeUint(b *Buffer, v uint64, tag FieldTag, size int) error {
+
+	switch tag.Varint {
+	case VarintLength:
+		b.WriteUvarint(v)
+		return nil
+	case QUICVarintLength:
+		return b.WriteQUICVarint(v)
+	}
+
+	if size < 8 && v >= 1<<(8*size) {
+		return fmt.Errorf("%w: %d does not fit into %d bytes", ErrValueOverflow, v, size)
+	}
+
+	b.b = appendUint(b.b, v, size, tag.little(b))
+
+	return nil
+}
+
+// encodeBytes appends a byte slice or string as a vector or a fixed size field padded with zeros.
+func encodeBytes(b *Buffer, v []byte, tag FieldTag) error {
+
+	switch {
+	case tag.Vector != 0:
+		return b.WriteVectorE(v, tag.Vector)
+	case tag.Fixed >= 0:
+		if len(v) > tag.Fixed {
+			return &EncodeError{Op: "Marshal", Length: len(v), Err: ErrLengthOverflow}
+		}
+		b.WriteBytes(v...)
+		b.WriteBytes(make([]byte, tag.Fixed-len(v))...)
+		return nil
+	default:
+		return errors.New("strings and slices require a vector or fixed option")
+	}
+}
+
+// encodeSlice appends the elements of a slice as a vector or a fixed number of elements.
+func encodeSlice(b *Buffer, v reflect.Value, tag FieldTag, path string, depth int) error {
+
+	elems := func(b *Buffer) error {
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(b, v.Index(i), tag.ElemTag(), fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case tag.Vector != 0:
+		return fieldError(path, b.AddLengthPrefixed(tag.Vector, elems))
+	case tag.Fixed >= 0:
+		if v.Len() != tag.Fixed {
+			return fieldError(path, fmt.Errorf("%w: have %d elements, want %d", ErrInvalidLength, v.Len(), tag.Fixed))
+		}
+		return elems(b)
+	default:
+		return fieldError(path, errors.New("strings and slices require a vector or fixed option"))
+	}
+}
+
+// decodeValue decodes v from b.
+func decodeValue(b *Buffer, v reflect.Value, tag FieldTag, path string, depth int) error {
+
+	if depth > maxDepth {
+		return fieldError(path, ErrMaxDepth)
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return fieldError(path, v.Addr().Interface().(Unmarshaler).DecodeFrom(b))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		c, err := b.readE(1, "Unmarshal")
+		if err != nil {
+			return fieldError(path, err)
+		}
+		v.SetBool(c[0] != 0)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := decodeInt(b, tag, tag.intSize(v.Type()))
+		if err == nil && v.OverflowInt(x) {
+			err = fmt.Errorf("%w: %d does not fit into %s", ErrValueOverflow, x, v.Type())
+		}
+		if err != nil {
+			return fieldError(path, err)
+		}
+		v.SetInt(x)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, err := decodeUint(b, tag, tag.intSize(v.Type()))
+		if err == nil && v.OverflowUint(x) {
+			err = fmt.Errorf("%w: %d does not fit into %s", ErrValueOverflow, x, v.Type())
+		}
+		if err != nil {
+			return fieldError(path, err)
+		}
+		v.SetUint(x)
+		return nil
+
+	case reflect.Float32:
+		c, err := b.readE(4, "Unmarshal")
+		if err != nil {
+			return fieldError(path, err)
+		}
+		v.SetFloat(float64(math.Float32frombits(uint32(getUint(c, tag.little(b))))))
+		return nil
+
+	case reflect.Float64:
+		c, err := b.readE(8, "Unmarshal")
+		if err != nil {
+			return fieldError(path, err)
+		}
+		v.SetFloat(math.Float64frombits(getUint(c, tag.little(b))))
+		return nil
+
+	case reflect.String:
+		c, err := decodeBytes(b, tag)
+		if err != nil {
+			return fieldError(path, err)
+		}
+		if tag.Fixed >= 0 {
+			c = trimZeros(c)
+		}
+		v.SetString(string(c))
+		return nil
+
+	case reflect.Slice:
+		if isByteType(v.Type().Elem()) {
+			c, err := decodeBytes(b, tag)
+			if err != nil {
+				return fieldError(path, err)
+			}
+			v.SetBytes(append([]byte{}, c...))
+			return nil
+		}
+		return decodeSlice(b, v, tag, path, depth)
+
+	case reflect.Array:
+		if tag.Vector != 0 || (tag.Fixed >= 0 && tag.Fixed != v.Len()) {
+			return fieldError(path, fmt.Errorf("vector or fixed option does not match array of length %d", v.Len()))
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := decodeValue(b, v.Index(i), tag.ElemTag(), fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Struct:
+		fields, err := structFields(v.Type(), path)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := decodeValue(b, v.Field(f.index), f.tag, path+"."+f.name, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			if err := b.allocate(int(v.Type().Elem().Size())); err != nil {
+				return fieldError(path, err)
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(b, v.Elem(), tag, path, depth+1)
+
+	default:
+		return fieldError(path, fmt.Errorf("unsupported type %s", v.Type()))
+	}
+}
+
+// decodeInt reads a signed integer of size bytes or a varint.
+func decodeInt(b *Buffer, tag FieldTag, size int) (int64, error) {
+
+	switch tag.Varint {
+	case VarintLength:
+		return b.ReadVarintE()
+	case QUICVarintLength:
+		return 0, errors.New("quicvarint requires an unsigned integer")
+	}
+
+	c, err := b.readE(size, "Unmarshal")
+	if err != nil {
+		return 0, err
+	}
+
+	v := getUint(c, tag.little(b))
+
+	// Sign extension
+	if size < 8 && v>>(8*size-1)&1 == 1 {
+		v |= math.MaxUint64 << (8 * size)
+	}
+
+	return int64(v), nil
+}
+
+// decodeUint reads an unsigned integer of size bytes or a varint.
+func decodeUint(b *Buffer, tag FieldTag, size int) (uint64, error) {
+
+	switch tag.Varint {
+	case VarintLength:
+		return b.ReadUvarintE()
+	case QUICVarintLength:
+		return b.ReadQUICVarintE()
+	}
+
+	c, err := b.readE(size, "Unmarshal")
+	if err != nil {
+		return 0, err
+	}
+
+	return getUint(c, tag.little(b)), nil
+}
+
+// decodeBytes reads a vector or a fixed size field.
+// The returned slice points into b, it must be copied before it is stored.
+func decodeBytes(b *Buffer, tag FieldTag) ([]byte, error) {
+
+	var (
+		v   []byte
+		err error
+	)
+
+	switch {
+	case tag.Vector != 0:
+		v, err = b.ReadVectorE(tag.Vector)
+	case tag.Fixed >= 0:
+		v, err = b.readE(tag.Fixed, "Unmarshal")
+	default:
+		return nil, errors.New("strings and slices require a vector or fixed option")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.allocate(len(v)); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ErrEmptyElement is returned by Unmarshal when an element of a vector is decoded without consuming any byte,
+// so the vector can not be split into elements (eg.: a []struct{} field).
+var ErrEmptyElement = errors.New("vector element with zero length")
+
+// decodeSlice reads the elements of a slice from a vector or a fixed number of elements.
+func decodeSlice(b *Buffer, v reflect.Value, tag FieldTag, path string, depth int) error {
+
+	elemType := v.Type().Elem()
+	s := reflect.MakeSlice(v.Type(), 0, 0)
+
+	switch {
+	case tag.Vector != 0:
+
+		var child Buffer
+
+		if err := b.ReadLengthPrefixed(tag.Vector, &child); err != nil {
+			return fieldError(path, err)
+		}
+
+		for i := 0; !child.Empty(); i++ {
+
+			if err := b.allocate(int(elemType.Size())); err != nil {
+				return fieldError(path, err)
+			}
+
+			s = reflect.Append(s, reflect.Zero(elemType))
+
+			off := child.off
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+
+			if err := decodeValue(&child, s.Index(i), tag.ElemTag(), elemPath, depth+1); err != nil {
+				return err
+			}
+
+			// An element that does not consume any byte would be decoded forever (eg.: []struct{}).
+			if child.off == off {
+				return fieldError(elemPath, &DecodeError{Op: "Unmarshal", Offset: child.base + off, Have: child.Remaining(), Err: ErrEmptyElement})
+			}
+		}
+
+	case tag.Fixed >= 0:
+
+		if err := b.allocate(tag.Fixed * int(elemType.Size())); err != nil {
+			return fieldError(path, err)
+		}
+
+		s = reflect.MakeSlice(v.Type(), tag.Fixed, tag.Fixed)
+
+		for i := 0; i < tag.Fixed; i++ {
+			if err := decodeValue(b, s.Index(i), tag.ElemTag(), fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fieldError(path, errors.New("strings and slices require a vector or fixed option"))
+	}
+
+	v.Set(s)
+
+	return nil
+}
+
+// allocate records the allocation of n bytes against the MaxAllocation limit of b.
+func (b *Buffer) allocate(n int) error {
+
+	if b.alloc == nil {
+		return nil
+	}
+
+	if err := b.alloc.alloc(n); err != nil {
+		return &DecodeError{Op: "Unmarshal", Offset: b.base + b.off, Err: err}
+	}
+
+	return nil
+}
+
+// appendUint appends v to b using size bytes in little-endian or big-endian order.
+func appendUint(b []byte, v uint64, size int, little bool) []byte {
+
+	n := len(b)
+	b = append(b, make([]byte, size)...)
+	putUint(b[n:], v, little)
+
+	return b
+}
+
+// getUint returns the unsigned integer stored in src in little-endian or big-endian order.
+func getUint(src []byte, little bool) uint64 {
+
+	var v uint64
+
+	for i := range src {
+		if little {
+			v |= uint64(src[i]) << (8 * i)
+		} else {
+			v = v<<8 | uint64(src[i])
+		}
+	}
+
+	return v
+}
+
+// trimZeros removes the trailing zero bytes of v.
+func trimZeros(v []byte) []byte {
+
+	for len(v) > 0 && v[len(v)-1] == 0 {
+		v = v[:len(v)-1]
+	}
+
+	return v
+}
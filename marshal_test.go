@@ -0,0 +1,219 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testHeader struct {
+	Version uint16
+	Length  uint32 `bb:"uint24"`
+	Flags   uint16 `bb:"le"`
+	ID      int64  `bb:"varint"`
+	Seq     uint64 `bb:"quicvarint"`
+	Enabled bool
+}
+
+type testMessage struct {
+	Header  testHeader
+	Random  []byte   `bb:"fixed,4"`
+	Name    string   `bb:"vector,8"`
+	Suites  []uint16 `bb:"vector,16"`
+	Payload []byte   `bb:"vector,varint"`
+	Points  [2]int8
+	Next    *testHeader
+	skipped int
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		name string
+		v    any
+		want []byte
+	}{
+		{"uint16", &struct{ V uint16 }{0x0102}, []byte{0x01, 0x02}},
+		{"uint24", &struct {
+			V uint32 `bb:"uint24"`
+		}{0x010203}, []byte{0x01, 0x02, 0x03}},
+		{"le", &struct {
+			V uint32 `bb:"le"`
+		}{0x01020304}, []byte{0x04, 0x03, 0x02, 0x01}},
+		{"varint", &struct {
+			V int64 `bb:"varint"`
+		}{-65}, []byte{0x81, 0x01}},
+		{"quicvarint", &struct {
+			V uint64 `bb:"quicvarint"`
+		}{15293}, []byte{0x7b, 0xbd}},
+		{"string vector", &struct {
+			V string `bb:"vector,8"`
+		}{"abc"}, []byte{3, 'a', 'b', 'c'}},
+		{"fixed string", &struct {
+			V string `bb:"fixed,3"`
+		}{"abc"}, []byte{'a', 'b', 'c'}},
+		{"uint16 vector", &struct {
+			V []uint16 `bb:"vector,16"`
+		}{[]uint16{1, 2}}, []byte{0, 4, 0, 1, 0, 2}},
+		{"skip", &struct {
+			A uint8
+			B uint8 `bb:"-"`
+		}{A: 1}, []byte{1}},
+		{"nested", &testMessage{
+			Header:  testHeader{Version: 0x0303, Length: 7, Flags: 1, ID: -1, Seq: 64, Enabled: true},
+			Random:  []byte{1, 2, 3, 4},
+			Name:    "x",
+			Suites:  []uint16{0x1301},
+			Payload: []byte{0xff},
+			Points:  [2]int8{-1, 1},
+			Next:    &testHeader{Version: 1},
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			data, err := Marshal(reflect.ValueOf(tt.v).Elem().Interface())
+			if err != nil {
+				t.Fatalf("Marshal: %s", err)
+			}
+
+			if tt.want != nil && !bytes.Equal(data, tt.want) {
+				t.Fatalf("Marshal = % x, want % x", data, tt.want)
+			}
+
+			got := reflect.New(reflect.TypeOf(tt.v).Elem())
+
+			if err := Unmarshal(data, got.Interface()); err != nil {
+				t.Fatalf("Unmarshal: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Interface(), tt.v) {
+				t.Fatalf("Unmarshal = %+v, want %+v", got.Elem(), reflect.ValueOf(tt.v).Elem())
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+
+	tests := []struct {
+		name string
+		data []byte
+		v    any
+		err  error
+		path string
+	}{
+		{"short", []byte{1}, &struct{ V uint16 }{}, ErrShortBuffer, "V"},
+		{"trailing", []byte{1, 2, 3}, &struct{ V uint16 }{}, ErrTrailingData, ""},
+		{"short vector", []byte{0, 4, 0, 1}, &struct {
+			V []uint16 `bb:"vector,16"`
+		}{}, ErrShortBuffer, "V"},
+		{"partial element", []byte{3, 0, 1, 2}, &struct {
+			V []uint16 `bb:"vector,8"`
+		}{}, ErrShortBuffer, "V[1]"},
+		{"empty element", []byte{1, 0}, &struct {
+			V []struct{} `bb:"vector,8"`
+		}{}, ErrEmptyElement, "V[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := Unmarshal(tt.data, tt.v)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Unmarshal error = %v, want %v", err, tt.err)
+			}
+
+			var fe *FieldError
+
+			if tt.path != "" && (!errors.As(err, &fe) || !strings.HasSuffix(fe.Path, tt.path)) {
+				t.Fatalf("Unmarshal error = %v, want path %s", err, tt.path)
+			}
+		})
+	}
+}
+
+func TestUnmarshalMaxAllocation(t *testing.T) {
+
+	var v struct {
+		V []byte `bb:"vector,8"`
+	}
+
+	b := NewBuffer([]byte{4, 1, 2, 3, 4})
+	b.SetLimits(Limits{MaxAllocation: 3})
+
+	if err := b.Unmarshal(&v); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("Unmarshal error = %v, want %v", err, ErrLimitExceeded)
+	}
+}
+
+type testNode struct {
+	Value uint8
+	Next  *testNode
+}
+
+type testTree struct {
+	Children []testTree `bb:"fixed,1"`
+}
+
+func TestMarshalMaxDepth(t *testing.T) {
+
+	// A cyclic pointer can not be encoded.
+	n := &testNode{Value: 1}
+	n.Next = n
+
+	if _, err := Marshal(n); !errors.Is(err, ErrMaxDepth) {
+		t.Fatalf("Marshal of a cyclic value = %v, want ErrMaxDepth", err)
+	}
+
+	// Every level consumes a byte, the input is long enough to overflow the stack without the limit.
+	if err := Unmarshal(make([]byte, 10*maxDepth), &testNode{}); !errors.Is(err, ErrMaxDepth) {
+		t.Fatalf("Unmarshal of a deep value = %v, want ErrMaxDepth", err)
+	}
+
+	// The levels do not consume any byte.
+	if err := Unmarshal(nil, &testTree{}); !errors.Is(err, ErrMaxDepth) {
+		t.Fatalf("Unmarshal of a recursive type = %v, want ErrMaxDepth", err)
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+
+	seed, err := Marshal(testMessage{Random: make([]byte, 4), Suites: []uint16{1}, Next: &testHeader{}})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(seed)
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+
+		var v testMessage
+
+		b := NewBuffer(data)
+		b.SetLimits(Limits{MaxAllocation: 1 << 20})
+
+		if err := b.Unmarshal(&v); err != nil {
+			return
+		}
+
+		out, err := Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal of decoded value: %s", err)
+		}
+
+		var w testMessage
+
+		if err := Unmarshal(out, &w); err != nil {
+			t.Fatalf("Unmarshal of encoded value: %s", err)
+		}
+
+		if !reflect.DeepEqual(v, w) {
+			t.Fatalf("round trip = %+v, want %+v", w, v)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package bytebuilder
+
+import (
+	"errors"
+	"io"
+)
+
+// MaxQUICVarint is the largest value that can be encoded as a QUIC variable-length integer (RFC 9000 Section 16).
+const MaxQUICVarint = 1<<62 - 1
+
+// QUICVarintLength can be used as the bitSize of the vector functions (eg.: Buffer.WriteVector, Buffer.ReadVector)
+// to encode the length as a QUIC variable-length integer.
+const QUICVarintLength = -2
+
+// ErrQUICVarintRange is returned when a value can not be encoded as a QUIC variable-length integer
+// (it is larger than MaxQUICVarint or does not fit into the requested length).
+var ErrQUICVarintRange = errors.New("value out of QUIC varint range")
+
+// QUICVarintLen returns the minimal number of bytes (1, 2, 4 or 8) to encode v as a QUIC variable-length integer.
+// If v is larger than MaxQUICVarint, returns 0.
+func QUICVarintLen(v uint64) int {
+
+	switch {
+	case v <= 63:
+		return 1
+	case v <= 16383:
+		return 2
+	case v <= 1073741823:
+		return 4
+	case v <= MaxQUICVarint:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// AppendQUICVarint appends the QUIC variable-length integer encoding of v to b using n bytes
+// and returns the extended slice.
+// n must be 1, 2, 4 or 8, otherwise ErrInvalidLength is returned.
+// If v does not fit into n bytes, ErrQUICVarintRange is returned.
+func AppendQUICVarint(b []byte, v uint64, n int) ([]byte, error) {
+
+	if v > MaxQUICVarint || QUICVarintLen(v) > n {
+		return b, ErrQUICVarintRange
+	}
+
+	switch n {
+	case 1:
+		return append(b, byte(v)), nil
+	case 2:
+		return append(b, byte(v>>8)|0x40, byte(v)), nil
+	case 4:
+		return append(b, byte(v>>24)|0x80, byte(v>>16), byte(v>>8), byte(v)), nil
+	case 8:
+		return append(b, byte(v>>56)|0xc0, byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v)), nil
+	default:
+		return b, ErrInvalidLength
+	}
+}
+
+// decodeQUICVarint decodes a QUIC variable-length integer from the start of b.
+// Returns the value, the number of bytes used and ErrShortBuffer if b is too short.
+func decodeQUICVarint(b []byte) (uint64, int, error) {
+
+	if len(b) < 1 {
+		return 0, 1, ErrShortBuffer
+	}
+
+	n := 1 << (b[0] >> 6)
+
+	if len(b) < n {
+		return 0, n, ErrShortBuffer
+	}
+
+	v := uint64(b[0] & 0x3f)
+
+	for _, c := range b[1:n] {
+		v = v<<8 | uint64(c)
+	}
+
+	return v, n, nil
+}
+
+// ReadQUICVarint removes a QUIC variable-length integer from the start of b and returns it.
+// The bool indicates whether the read was successful.
+func ReadQUICVarint(b *[]byte) (uint64, bool) {
+
+	v, err := ReadQUICVarintE(b)
+
+	return v, err == nil
+}
+
+// ReadQUICVarintE is like ReadQUICVarint, but returns a *DecodeError if the read failed.
+func ReadQUICVarintE(b *[]byte) (uint64, error) {
+
+	v, n, err := decodeQUICVarint(*b)
+	if err != nil {
+		return 0, &DecodeError{Op: "ReadQUICVarint", Offset: -1, Want: n, Have: len(*b), Err: err}
+	}
+
+	*b = (*b)[n:]
+
+	return v, nil
+}
+
+// WriteQUICVarint appends v at the end of b as a QUIC variable-length integer in the minimal length.
+// If v is larger than MaxQUICVarint, ErrQUICVarintRange is returned and b is not modified.
+func WriteQUICVarint(b *[]byte, v uint64) error {
+	return WriteQUICVarintN(b, v, QUICVarintLen(v))
+}
+
+// WriteQUICVarintN appends v at the end of b as a QUIC variable-length integer in exactly n bytes.
+// Forcing the length is useful when the value is patched later (eg.: a packet length).
+// n must be 1, 2, 4 or 8, otherwise ErrInvalidLength is returned.
+// If v does not fit into n bytes, ErrQUICVarintRange is returned.
+// If an error is returned, b is not modified.
+func WriteQUICVarintN(b *[]byte, v uint64, n int) error {
+
+	nb, err := AppendQUICVarint(*b, v, n)
+	if err != nil {
+		return err
+	}
+
+	*b = nb
+
+	return nil
+}
+
+// ReadQUICVarint reads a QUIC variable-length integer from b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadQUICVarint() (uint64, bool) {
+
+	v, err := b.ReadQUICVarintE()
+
+	return v, err == nil
+}
+
+// ReadQUICVarintE is like ReadQUICVarint, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadQUICVarintE() (uint64, error) {
+
+	v, n, err := decodeQUICVarint(b.b[b.off:])
+	if err != nil {
+		return 0, &DecodeError{Op: "ReadQUICVarint", Offset: b.base + b.off, Want: n, Have: b.Remaining(), Err: err}
+	}
+
+	b.off += n
+
+	return v, nil
+}
+
+// WriteQUICVarint appends v at the end of b as a QUIC variable-length integer in the minimal length.
+// If v is larger than MaxQUICVarint, ErrQUICVarintRange is returned and b is not modified.
+func (b *Buffer) WriteQUICVarint(v uint64) error {
+	return WriteQUICVarintN(&b.b, v, QUICVarintLen(v))
+}
+
+// WriteQUICVarintN appends v at the end of b as a QUIC variable-length integer in exactly n bytes.
+// n must be 1, 2, 4 or 8, otherwise ErrInvalidLength is returned.
+// If v does not fit into n bytes, ErrQUICVarintRange is returned.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteQUICVarintN(v uint64, n int) error {
+	return WriteQUICVarintN(&b.b, v, n)
+}
+
+// ReadQUICVarint reads a QUIC variable-length integer.
+func (d *Decoder) ReadQUICVarint() uint64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadQUICVarintE()
+	*d.err = err
+
+	return v
+}
+
+// ReadQUICVarint reads a QUIC variable-length integer from s.
+func (s *StreamReader) ReadQUICVarint() (uint64, error) {
+
+	off := s.n
+
+	c, err := s.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 1 << (c >> 6)
+	v := uint64(c & 0x3f)
+
+	if n == 1 {
+		return v, nil
+	}
+
+	rest, err := s.read(n-1, "ReadQUICVarint")
+	if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, &DecodeError{Op: "ReadQUICVarint", Offset: int(off), Want: n, Have: int(s.n - off), Err: io.ErrUnexpectedEOF}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range rest {
+		v = v<<8 | uint64(c)
+	}
+
+	return v, nil
+}
+
+// WriteQUICVarint writes v to s as a QUIC variable-length integer in the minimal length.
+// If v is larger than MaxQUICVarint, ErrQUICVarintRange is recorded.
+func (s *StreamWriter) WriteQUICVarint(v uint64) {
+	s.WriteQUICVarintN(v, QUICVarintLen(v))
+}
+
+// WriteQUICVarintN writes v to s as a QUIC variable-length integer in exactly n bytes.
+// If n is invalid or v does not fit into n bytes, the error is recorded.
+func (s *StreamWriter) WriteQUICVarintN(v uint64, n int) {
+
+	if s.err != nil {
+		return
+	}
+
+	if err := WriteQUICVarintN(&s.buf, v, n); err != nil {
+		s.err = err
+		return
+	}
+
+	s.advance(n)
+}
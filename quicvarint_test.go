@@ -0,0 +1,157 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestQUICVarintRoundTrip(t *testing.T) {
+
+	// The examples of RFC 9000 Appendix A.1 and the boundaries of the lengths.
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{37, []byte{0x25}},
+		{63, []byte{0x3f}},
+		{64, []byte{0x40, 0x40}},
+		{15293, []byte{0x7b, 0xbd}},
+		{16383, []byte{0x7f, 0xff}},
+		{16384, []byte{0x80, 0x00, 0x40, 0x00}},
+		{494878333, []byte{0x9d, 0x7f, 0x3e, 0x7d}},
+		{1<<30 - 1, []byte{0xbf, 0xff, 0xff, 0xff}},
+		{1 << 30, []byte{0xc0, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00}},
+		{151288809941952652, []byte{0xc2, 0x19, 0x7c, 0x5e, 0xff, 0x14, 0xe8, 0x8c}},
+		{MaxQUICVarint, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+
+	for _, tt := range tests {
+
+		b := NewEmpty()
+
+		if err := b.WriteQUICVarint(tt.v); err != nil || !bytes.Equal(b.Bytes(), tt.want) || QUICVarintLen(tt.v) != len(tt.want) {
+			t.Fatalf("WriteQUICVarint(%d) = % x, %v, want % x", tt.v, b.Bytes(), err, tt.want)
+		}
+
+		s := NewStreamReader(bytes.NewReader(tt.want))
+
+		if v, err := b.ReadQUICVarintE(); err != nil || v != tt.v || !b.Empty() {
+			t.Fatalf("Buffer.ReadQUICVarintE(% x) = %d, %v", tt.want, v, err)
+		}
+
+		if v, err := s.ReadQUICVarint(); err != nil || v != tt.v {
+			t.Fatalf("StreamReader.ReadQUICVarint(% x) = %d, %v", tt.want, v, err)
+		}
+	}
+}
+
+func TestQUICVarintWriteErrors(t *testing.T) {
+
+	tests := []struct {
+		v   uint64
+		n   int
+		err error
+	}{
+		{MaxQUICVarint + 1, 8, ErrQUICVarintRange},
+		{1<<64 - 1, 8, ErrQUICVarintRange},
+		{64, 1, ErrQUICVarintRange},
+		{16384, 2, ErrQUICVarintRange},
+		{1, 3, ErrInvalidLength},
+		{1, 16, ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBuffer([]byte{0xaa})
+
+		if err := b.WriteQUICVarintN(tt.v, tt.n); !errors.Is(err, tt.err) || b.Size() != 1 {
+			t.Fatalf("WriteQUICVarintN(%d, %d) = %v, Size = %d, want %v", tt.v, tt.n, err, b.Size(), tt.err)
+		}
+	}
+
+	b := NewEmpty()
+
+	if err := b.WriteQUICVarint(MaxQUICVarint + 1); !errors.Is(err, ErrQUICVarintRange) || b.Size() != 0 {
+		t.Fatalf("WriteQUICVarint(MaxQUICVarint+1) = %v, want ErrQUICVarintRange", err)
+	}
+
+	s := NewStreamWriter(io.Discard)
+	s.WriteQUICVarint(MaxQUICVarint + 1)
+
+	if err := s.Flush(); !errors.Is(err, ErrQUICVarintRange) || s.Written() != 0 {
+		t.Fatalf("StreamWriter.WriteQUICVarint(MaxQUICVarint+1): Flush = %v, Written = %d", err, s.Written())
+	}
+}
+
+func TestQUICVarintNonMinimal(t *testing.T) {
+
+	// A forced length is accepted on read, QUIC does not require the minimal encoding.
+	for _, n := range []int{1, 2, 4, 8} {
+
+		b := NewEmpty()
+
+		if err := b.WriteQUICVarintN(37, n); err != nil || b.Size() != n {
+			t.Fatalf("WriteQUICVarintN(37, %d) = %v, wrote %d bytes", n, err, b.Size())
+		}
+
+		if v, err := b.ReadQUICVarintE(); err != nil || v != 37 {
+			t.Fatalf("ReadQUICVarintE of %d bytes = %d, %v", n, v, err)
+		}
+	}
+}
+
+func TestQUICVarintShort(t *testing.T) {
+
+	data := []byte{0x00, 0x80, 0x01, 0x02}
+
+	b := NewBuffer(data)
+	b.Skip(1)
+
+	_, err := b.ReadQUICVarintE()
+
+	var de *DecodeError
+
+	if !errors.As(err, &de) || de.Op != "ReadQUICVarint" || de.Offset != 1 || de.Want != 4 || de.Have != 3 || !errors.Is(err, ErrShortBuffer) {
+		t.Fatalf("Buffer.ReadQUICVarintE error = %v", err)
+	}
+
+	if b.Offset() != 1 {
+		t.Fatalf("failed ReadQUICVarintE moved the cursor to %d", b.Offset())
+	}
+
+	s := NewStreamReader(bytes.NewReader(data))
+	s.Skip(1)
+
+	if _, err := s.ReadQUICVarint(); !errors.As(err, &de) || de.Offset != 1 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("StreamReader.ReadQUICVarint error = %v", err)
+	}
+
+	if _, err := NewStreamReader(bytes.NewReader(nil)).ReadQUICVarint(); err != io.EOF {
+		t.Fatalf("StreamReader.ReadQUICVarint at the end = %v, want io.EOF", err)
+	}
+}
+
+func TestQUICVarintLengthVector(t *testing.T) {
+
+	data := bytes.Repeat([]byte{0xaa}, 100)
+
+	b := NewEmpty()
+	b.WriteVector(data, QUICVarintLength)
+
+	if b.Size() != 2+len(data) || b.Bytes()[0] != 0x40 {
+		t.Fatalf("wrote % x...", b.Bytes()[:2])
+	}
+
+	s := NewStreamReader(bytes.NewReader(b.Bytes()))
+
+	if v, err := b.ReadVectorE(QUICVarintLength); err != nil || !bytes.Equal(v, data) {
+		t.Fatalf("Buffer.ReadVectorE: %v", err)
+	}
+
+	if v, err := s.ReadVector(QUICVarintLength); err != nil || !bytes.Equal(v, data) {
+		t.Fatalf("StreamReader.ReadVector: %v", err)
+	}
+}
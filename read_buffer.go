@@ -1,30 +1,134 @@
 package bytebuilder
 
 import (
+	"errors"
+	"io"
+	"math"
 	"time"
 )
 
-// ReadBytes removes the first n bytes from b and returns it.
+// ReadBytes reads the next n bytes from b and advances the read cursor.
 // If the read failed, returns nil.
 func (b *Buffer) ReadBytes(n int) []byte {
 
-	if len(b.b) < n || n < 0 {
+	if b.Remaining() < n || n < 0 {
 		return nil
 	}
 
-	v := b.b[:n]
-	b.b = b.b[n:]
+	v := b.b[b.off : b.off+n]
+	b.off += n
 
 	return v
 }
 
-// Skip removes the first n bytes from b.
+// Read reads up to len(p) bytes from b into p and advances the read cursor.
+// It returns the number of bytes read.
+// If b has no unread bytes, err is io.EOF (unless len(p) is zero).
+// Read implements the io.Reader interface.
+func (b *Buffer) Read(p []byte) (n int, err error) {
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if b.Empty() {
+		return 0, io.EOF
+	}
+
+	n = copy(p, b.b[b.off:])
+	b.off += n
+
+	return n, nil
+}
+
+// ReadByte reads and returns the next byte from b.
+// If no byte is available, returns io.EOF.
+// ReadByte implements the io.ByteReader interface.
+func (b *Buffer) ReadByte() (byte, error) {
+
+	if b.Empty() {
+		return 0, io.EOF
+	}
+
+	v := b.b[b.off]
+	b.off++
+
+	return v, nil
+}
+
+// UnreadByte moves the read cursor back by one byte.
+// UnreadByte implements the io.ByteScanner interface.
+func (b *Buffer) UnreadByte() error {
+
+	if b.off == 0 {
+		return errors.New("bytebuilder.Buffer.UnreadByte: at beginning of slice")
+	}
+
+	b.off--
+
+	return nil
+}
+
+// WriteTo writes the unread bytes of b to w and advances the read cursor by the number of bytes written.
+// WriteTo implements the io.WriterTo interface.
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+
+	if b.Empty() {
+		return 0, nil
+	}
+
+	n, err := w.Write(b.b[b.off:])
+	if n > b.Remaining() {
+		panic("bytebuilder.Buffer.WriteTo: invalid Write count")
+	}
+
+	b.off += n
+
+	if err == nil && !b.Empty() {
+		err = io.ErrShortWrite
+	}
+
+	return int64(n), err
+}
+
+// ReadBytesE is like ReadBytes, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBytesE(n int) ([]byte, error) {
+	return b.readE(n, "ReadBytes")
+}
+
+// readE reads the next n bytes from b and advances the read cursor.
+// op is the name of the operation used in the returned *DecodeError.
+func (b *Buffer) readE(n int, op string) ([]byte, error) {
+
+	if n < 0 {
+		return nil, &DecodeError{Op: op, Offset: b.base + b.off, Want: n, Have: b.Remaining(), Err: ErrInvalidLength}
+	}
+
+	if b.Remaining() < n {
+		return nil, &DecodeError{Op: op, Offset: b.base + b.off, Want: n, Have: b.Remaining(), Err: ErrShortBuffer}
+	}
+
+	v := b.b[b.off : b.off+n]
+	b.off += n
+
+	return v, nil
+}
+
+// Skip advances the read cursor of b by n bytes.
 // Returns whether it was successful.
 func (b *Buffer) Skip(n int) bool {
 	return b.ReadBytes(n) != nil
 }
 
-// ReadUint8 removes the first byte from b and returns it as an uint8.
+// SkipE is like Skip, but returns a *DecodeError if it failed.
+func (b *Buffer) SkipE(n int) error {
+
+	_, err := b.readE(n, "Skip")
+
+	return err
+}
+
+// ReadUint8 reads the next byte from b and returns it as an uint8.
 // The bool indicates whether the read was successful.
 func (b *Buffer) ReadUint8() (uint8, bool) {
 
@@ -36,7 +140,18 @@ func (b *Buffer) ReadUint8() (uint8, bool) {
 	return uint8(v[0]), true
 }
 
-// ReadInt8 removes the first byte from b and returns it as an int8.
+// ReadUint8E is like ReadUint8, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint8E() (uint8, error) {
+
+	v, err := b.readE(1, "ReadUint8")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint8(v[0]), nil
+}
+
+// ReadInt8 reads the next byte from b and returns it as an int8.
 // The bool indicates whether the read was successful.
 func (b *Buffer) ReadInt8() (int8, bool) {
 
@@ -48,9 +163,43 @@ func (b *Buffer) ReadInt8() (int8, bool) {
 	return int8(v[0]), true
 }
 
-// ReadUint16 removes the first bytes from b and returns it as an uint16.
+// ReadInt8E is like ReadInt8, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadInt8E() (int8, error) {
+
+	v, err := b.readE(1, "ReadInt8")
+	if err != nil {
+		return 0, err
+	}
+
+	return int8(v[0]), nil
+}
+
+// ReadLittleUint16 reads the next bytes from b and returns it as an uint16 in little-endian order.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadUint16() (uint16, bool) {
+func (b *Buffer) ReadLittleUint16() (uint16, bool) {
+
+	v := b.ReadBytes(2)
+	if v == nil {
+		return 0, false
+	}
+
+	return uint16(v[1])<<8 | uint16(v[0]), true
+}
+
+// ReadLittleUint16E is like ReadLittleUint16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleUint16E() (uint16, error) {
+
+	v, err := b.readE(2, "ReadLittleUint16")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint16(v[1])<<8 | uint16(v[0]), nil
+}
+
+// ReadBigUint16 reads the next bytes from b and returns it as an uint16 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigUint16() (uint16, bool) {
 
 	v := b.ReadBytes(2)
 	if v == nil {
@@ -60,9 +209,67 @@ func (b *Buffer) ReadUint16() (uint16, bool) {
 	return uint16(v[0])<<8 | uint16(v[1]), true
 }
 
-// ReadInt16 removes the first bytes from b and returns it as an int16.
+// ReadBigUint16E is like ReadBigUint16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigUint16E() (uint16, error) {
+
+	v, err := b.readE(2, "ReadBigUint16")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint16(v[0])<<8 | uint16(v[1]), nil
+}
+
+// ReadUint16 reads the next bytes from b and returns it as an uint16 in the byte order of b.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadInt16() (int16, bool) {
+func (b *Buffer) ReadUint16() (uint16, bool) {
+
+	if b.little {
+		return b.ReadLittleUint16()
+	}
+
+	return b.ReadBigUint16()
+}
+
+// ReadUint16E is like ReadUint16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint16E() (uint16, error) {
+
+	if b.little {
+		v, err := b.ReadLittleUint16E()
+		return v, withOp(err, "ReadUint16")
+	}
+
+	v, err := b.ReadBigUint16E()
+
+	return v, withOp(err, "ReadUint16")
+}
+
+// ReadLittleInt16 reads the next bytes from b and returns it as an int16 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleInt16() (int16, bool) {
+
+	v := b.ReadBytes(2)
+	if v == nil {
+		return 0, false
+	}
+
+	return int16(v[1])<<8 | int16(v[0]), true
+}
+
+// ReadLittleInt16E is like ReadLittleInt16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleInt16E() (int16, error) {
+
+	v, err := b.readE(2, "ReadLittleInt16")
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(v[1])<<8 | int16(v[0]), nil
+}
+
+// ReadBigInt16 reads the next bytes from b and returns it as an int16 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigInt16() (int16, bool) {
 
 	v := b.ReadBytes(2)
 	if v == nil {
@@ -72,152 +279,961 @@ func (b *Buffer) ReadInt16() (int16, bool) {
 	return int16(v[0])<<8 | int16(v[1]), true
 }
 
-// ReadUint24 removes the first bytes from b and returns it as a uint32.
+// ReadBigInt16E is like ReadBigInt16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigInt16E() (int16, error) {
+
+	v, err := b.readE(2, "ReadBigInt16")
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(v[0])<<8 | int16(v[1]), nil
+}
+
+// ReadInt16 reads the next bytes from b and returns it as an int16 in the byte order of b.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadUint24() (uint32, bool) {
+func (b *Buffer) ReadInt16() (int16, bool) {
+
+	if b.little {
+		return b.ReadLittleInt16()
+	}
+
+	return b.ReadBigInt16()
+}
+
+// ReadInt16E is like ReadInt16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadInt16E() (int16, error) {
+
+	if b.little {
+		v, err := b.ReadLittleInt16E()
+		return v, withOp(err, "ReadInt16")
+	}
+
+	v, err := b.ReadBigInt16E()
+
+	return v, withOp(err, "ReadInt16")
+}
+
+// ReadLittleUint24 reads the next bytes from b and returns it as a uint32 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleUint24() (uint32, bool) {
 
 	v := b.ReadBytes(3)
 	if v == nil {
 		return 0, false
 	}
 
-	return uint32(v[0])<<16 | uint32(v[1])<<8 | uint32(v[2]), true
+	return uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), true
+}
+
+// ReadLittleUint24E is like ReadLittleUint24, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleUint24E() (uint32, error) {
+
+	v, err := b.readE(3, "ReadLittleUint24")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), nil
 }
 
-// ReadInt24 removes the first bytes from b and returns it as a int32.
+// ReadBigUint24 reads the next bytes from b and returns it as a uint32 in big-endian order.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadInt24() (int32, bool) {
+func (b *Buffer) ReadBigUint24() (uint32, bool) {
 
 	v := b.ReadBytes(3)
 	if v == nil {
 		return 0, false
 	}
 
-	return int32(v[0])<<16 | int32(v[1])<<8 | int32(v[2]), true
+	return uint32(v[0])<<16 | uint32(v[1])<<8 | uint32(v[2]), true
+}
+
+// ReadBigUint24E is like ReadBigUint24, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigUint24E() (uint32, error) {
+
+	v, err := b.readE(3, "ReadBigUint24")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[0])<<16 | uint32(v[1])<<8 | uint32(v[2]), nil
 }
 
-// ReadUint32 removes the first bytes from b and returns it as an uint32.
+// ReadUint24 reads the next bytes from b and returns it as a uint32 in the byte order of b.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadUint32() (uint32, bool) {
+func (b *Buffer) ReadUint24() (uint32, bool) {
 
-	v := b.ReadBytes(4)
+	if b.little {
+		return b.ReadLittleUint24()
+	}
+
+	return b.ReadBigUint24()
+}
+
+// ReadUint24E is like ReadUint24, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint24E() (uint32, error) {
+
+	if b.little {
+		v, err := b.ReadLittleUint24E()
+		return v, withOp(err, "ReadUint24")
+	}
+
+	v, err := b.ReadBigUint24E()
+
+	return v, withOp(err, "ReadUint24")
+}
+
+// ReadLittleInt24 reads the next bytes from b and returns it as an int32 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleInt24() (int32, bool) {
+
+	v := b.ReadBytes(3)
 	if v == nil {
 		return 0, false
 	}
 
-	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), true
+	return int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), true
 }
 
-// ReadInt32 removes the first bytes from b and returns it as an int32.
+// ReadLittleInt24E is like ReadLittleInt24, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleInt24E() (int32, error) {
+
+	v, err := b.readE(3, "ReadLittleInt24")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), nil
+}
+
+// ReadBigInt24 reads the next bytes from b and returns it as an int32 in big-endian order.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadInt32() (int32, bool) {
+func (b *Buffer) ReadBigInt24() (int32, bool) {
 
-	v := b.ReadBytes(4)
+	v := b.ReadBytes(3)
 	if v == nil {
 		return 0, false
 	}
 
-	return int32(v[0])<<24 | int32(v[1])<<16 | int32(v[2])<<8 | int32(v[3]), true
+	return int32(v[0])<<16 | int32(v[1])<<8 | int32(v[2]), true
+}
+
+// ReadBigInt24E is like ReadBigInt24, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigInt24E() (int32, error) {
+
+	v, err := b.readE(3, "ReadBigInt24")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[0])<<16 | int32(v[1])<<8 | int32(v[2]), nil
 }
 
-// ReadUint64 removes the first bytes from b and returns it as an uint64.
+// ReadInt24 reads the next bytes from b and returns it as an int32 in the byte order of b.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadUint64() (uint64, bool) {
+func (b *Buffer) ReadInt24() (int32, bool) {
 
-	v := b.ReadBytes(8)
+	if b.little {
+		return b.ReadLittleInt24()
+	}
+
+	return b.ReadBigInt24()
+}
+
+// ReadInt24E is like ReadInt24, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadInt24E() (int32, error) {
+
+	if b.little {
+		v, err := b.ReadLittleInt24E()
+		return v, withOp(err, "ReadInt24")
+	}
+
+	v, err := b.ReadBigInt24E()
+
+	return v, withOp(err, "ReadInt24")
+}
+
+// ReadLittleUint32 reads the next bytes from b and returns it as an uint32 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleUint32() (uint32, bool) {
+
+	v := b.ReadBytes(4)
 	if v == nil {
 		return 0, false
 	}
 
-	return uint64(v[0])<<56 | uint64(v[1])<<48 | uint64(v[2])<<40 | uint64(v[3])<<32 | uint64(v[4])<<24 | uint64(v[5])<<16 | uint64(v[6])<<8 | uint64(v[7]), true
+	return uint32(v[3])<<24 | uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), true
 }
 
-// ReadInt64 removes the first bytes from b and returns it as an int64.
+// ReadLittleUint32E is like ReadLittleUint32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleUint32E() (uint32, error) {
+
+	v, err := b.readE(4, "ReadLittleUint32")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[3])<<24 | uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), nil
+}
+
+// ReadBigUint32 reads the next bytes from b and returns it as an uint32 in big-endian order.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadInt64() (int64, bool) {
+func (b *Buffer) ReadBigUint32() (uint32, bool) {
 
-	v := b.ReadBytes(8)
+	v := b.ReadBytes(4)
 	if v == nil {
 		return 0, false
 	}
 
-	return int64(v[0])<<56 | int64(v[1])<<48 | int64(v[2])<<40 | int64(v[3])<<32 | int64(v[4])<<24 | int64(v[5])<<16 | int64(v[6])<<8 | int64(v[7]), true
+	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), true
 }
 
-// ReadInt removes the first bytes (depends on IntSize) from b and returns it as an int.
-func (b *Buffer) ReadInt() (int, bool) {
+// ReadBigUint32E is like ReadBigUint32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigUint32E() (uint32, error) {
 
-	switch IntSize {
-	case 32:
-		l, ok := b.ReadInt32()
-		return int(l), ok
-	case 64:
-		l, ok := b.ReadInt64()
-		return int(l), ok
-	default:
-		panic("invalid IntSize")
+	v, err := b.readE(4, "ReadBigUint32")
+	if err != nil {
+		return 0, err
 	}
+
+	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), nil
 }
 
-// ReadGMTUnixTime32 removes the first bytes from b and returns it as an unix time.
+// ReadUint32 reads the next bytes from b and returns it as an uint32 in the byte order of b.
 // The bool indicates whether the read was successful.
-func (b *Buffer) ReadGMTUnixTime32() (time.Time, bool) {
+func (b *Buffer) ReadUint32() (uint32, bool) {
 
-	v, ok := b.ReadUint32()
-	if !ok {
-		return time.Time{}, false
+	if b.little {
+		return b.ReadLittleUint32()
 	}
 
-	return time.Unix(int64(v), 0), true
+	return b.ReadBigUint32()
 }
 
-// ReadVector reads the length of bytes then the bytes itself.
-// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
-// Therefore, bitSize must be 8/16/24/32/64.
-// If bitSize is an invalid number, this function panics.
-func (b *Buffer) ReadVector(bitSize int) ([]byte, bool) {
+// ReadUint32E is like ReadUint32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint32E() (uint32, error) {
 
-	var n int
+	if b.little {
+		v, err := b.ReadLittleUint32E()
+		return v, withOp(err, "ReadUint32")
+	}
 
-	switch bitSize {
-	case 8:
-		n8, ok := b.ReadUint8()
-		if !ok {
-			return []byte{}, false
-		}
-		n = int(n8)
-	case 16:
-		n16, ok := b.ReadUint16()
-		if !ok {
-			return []byte{}, false
-		}
-		n = int(n16)
-	case 24:
-		n24, ok := b.ReadUint24()
-		if !ok {
-			return []byte{}, false
-		}
-		n = int(n24)
-	case 32:
-		n32, ok := b.ReadUint32()
-		if !ok {
-			return []byte{}, false
-		}
-		n = int(n32)
-	case 64:
-		n64, ok := b.ReadUint64()
-		if !ok {
-			return []byte{}, false
-		}
-		n = int(n64)
-	default:
-		panic("invalid bitSize value")
+	v, err := b.ReadBigUint32E()
+
+	return v, withOp(err, "ReadUint32")
+}
+
+// ReadLittleInt32 reads the next bytes from b and returns it as an int32 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleInt32() (int32, bool) {
+
+	v := b.ReadBytes(4)
+	if v == nil {
+		return 0, false
+	}
+
+	return int32(v[3])<<24 | int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), true
+}
+
+// ReadLittleInt32E is like ReadLittleInt32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleInt32E() (int32, error) {
+
+	v, err := b.readE(4, "ReadLittleInt32")
+	if err != nil {
+		return 0, err
 	}
 
-	v := b.ReadBytes(n)
+	return int32(v[3])<<24 | int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), nil
+}
+
+// ReadBigInt32 reads the next bytes from b and returns it as an int32 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigInt32() (int32, bool) {
+
+	v := b.ReadBytes(4)
 	if v == nil {
-		return []byte{}, false
+		return 0, false
 	}
 
-	return v, true
+	return int32(v[0])<<24 | int32(v[1])<<16 | int32(v[2])<<8 | int32(v[3]), true
+}
+
+// ReadBigInt32E is like ReadBigInt32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigInt32E() (int32, error) {
+
+	v, err := b.readE(4, "ReadBigInt32")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[0])<<24 | int32(v[1])<<16 | int32(v[2])<<8 | int32(v[3]), nil
+}
+
+// ReadInt32 reads the next bytes from b and returns it as an int32 in the byte order of b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadInt32() (int32, bool) {
+
+	if b.little {
+		return b.ReadLittleInt32()
+	}
+
+	return b.ReadBigInt32()
+}
+
+// ReadInt32E is like ReadInt32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadInt32E() (int32, error) {
+
+	if b.little {
+		v, err := b.ReadLittleInt32E()
+		return v, withOp(err, "ReadInt32")
+	}
+
+	v, err := b.ReadBigInt32E()
+
+	return v, withOp(err, "ReadInt32")
+}
+
+// ReadLittleUint64 reads the next bytes from b and returns it as an uint64 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleUint64() (uint64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return uint64(v[7])<<56 | uint64(v[6])<<48 | uint64(v[5])<<40 | uint64(v[4])<<32 | uint64(v[3])<<24 | uint64(v[2])<<16 | uint64(v[1])<<8 | uint64(v[0]), true
+}
+
+// ReadLittleUint64E is like ReadLittleUint64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleUint64E() (uint64, error) {
+
+	v, err := b.readE(8, "ReadLittleUint64")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(v[7])<<56 | uint64(v[6])<<48 | uint64(v[5])<<40 | uint64(v[4])<<32 | uint64(v[3])<<24 | uint64(v[2])<<16 | uint64(v[1])<<8 | uint64(v[0]), nil
+}
+
+// ReadBigUint64 reads the next bytes from b and returns it as an uint64 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigUint64() (uint64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return uint64(v[0])<<56 | uint64(v[1])<<48 | uint64(v[2])<<40 | uint64(v[3])<<32 | uint64(v[4])<<24 | uint64(v[5])<<16 | uint64(v[6])<<8 | uint64(v[7]), true
+}
+
+// ReadBigUint64E is like ReadBigUint64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigUint64E() (uint64, error) {
+
+	v, err := b.readE(8, "ReadBigUint64")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(v[0])<<56 | uint64(v[1])<<48 | uint64(v[2])<<40 | uint64(v[3])<<32 | uint64(v[4])<<24 | uint64(v[5])<<16 | uint64(v[6])<<8 | uint64(v[7]), nil
+}
+
+// ReadUint64 reads the next bytes from b and returns it as an uint64 in the byte order of b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUint64() (uint64, bool) {
+
+	if b.little {
+		return b.ReadLittleUint64()
+	}
+
+	return b.ReadBigUint64()
+}
+
+// ReadUint64E is like ReadUint64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint64E() (uint64, error) {
+
+	if b.little {
+		v, err := b.ReadLittleUint64E()
+		return v, withOp(err, "ReadUint64")
+	}
+
+	v, err := b.ReadBigUint64E()
+
+	return v, withOp(err, "ReadUint64")
+}
+
+// ReadLittleInt64 reads the next bytes from b and returns it as an int64 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleInt64() (int64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return int64(v[7])<<56 | int64(v[6])<<48 | int64(v[5])<<40 | int64(v[4])<<32 | int64(v[3])<<24 | int64(v[2])<<16 | int64(v[1])<<8 | int64(v[0]), true
+}
+
+// ReadLittleInt64E is like ReadLittleInt64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleInt64E() (int64, error) {
+
+	v, err := b.readE(8, "ReadLittleInt64")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v[7])<<56 | int64(v[6])<<48 | int64(v[5])<<40 | int64(v[4])<<32 | int64(v[3])<<24 | int64(v[2])<<16 | int64(v[1])<<8 | int64(v[0]), nil
+}
+
+// ReadBigInt64 reads the next bytes from b and returns it as an int64 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigInt64() (int64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return int64(v[0])<<56 | int64(v[1])<<48 | int64(v[2])<<40 | int64(v[3])<<32 | int64(v[4])<<24 | int64(v[5])<<16 | int64(v[6])<<8 | int64(v[7]), true
+}
+
+// ReadBigInt64E is like ReadBigInt64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigInt64E() (int64, error) {
+
+	v, err := b.readE(8, "ReadBigInt64")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v[0])<<56 | int64(v[1])<<48 | int64(v[2])<<40 | int64(v[3])<<32 | int64(v[4])<<24 | int64(v[5])<<16 | int64(v[6])<<8 | int64(v[7]), nil
+}
+
+// ReadInt64 reads the next bytes from b and returns it as an int64 in the byte order of b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadInt64() (int64, bool) {
+
+	if b.little {
+		return b.ReadLittleInt64()
+	}
+
+	return b.ReadBigInt64()
+}
+
+// ReadInt64E is like ReadInt64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadInt64E() (int64, error) {
+
+	if b.little {
+		v, err := b.ReadLittleInt64E()
+		return v, withOp(err, "ReadInt64")
+	}
+
+	v, err := b.ReadBigInt64E()
+
+	return v, withOp(err, "ReadInt64")
+}
+
+// ReadInt reads the next bytes (depends on IntSize) from b and returns it as an int.
+func (b *Buffer) ReadInt() (int, bool) {
+
+	switch IntSize {
+	case 32:
+		l, ok := b.ReadInt32()
+		return int(l), ok
+	case 64:
+		l, ok := b.ReadInt64()
+		return int(l), ok
+	default:
+		panic("invalid IntSize")
+	}
+}
+
+// ReadIntE is like ReadInt, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadIntE() (int, error) {
+
+	switch IntSize {
+	case 32:
+		l, err := b.ReadInt32E()
+		return int(l), withOp(err, "ReadInt")
+	case 64:
+		l, err := b.ReadInt64E()
+		return int(l), withOp(err, "ReadInt")
+	default:
+		panic("invalid IntSize")
+	}
+}
+
+// ReadGMTUnixTime32 reads the next bytes from b and returns it as an unix time.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadGMTUnixTime32() (time.Time, bool) {
+
+	v, ok := b.ReadUint32()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(v), 0), true
+}
+
+// ReadGMTUnixTime32E is like ReadGMTUnixTime32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadGMTUnixTime32E() (time.Time, error) {
+
+	v, err := b.ReadUint32E()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(v), 0), nil
+}
+
+// ReadVector reads the length of bytes then the bytes itself.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength.
+// The length is read in the byte order of b.
+// If bitSize is an invalid number, this function panics.
+// A length that does not fit into an int or exceeds the limits of b fails the read.
+func (b *Buffer) ReadVector(bitSize int) ([]byte, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := b.ReadVectorE(bitSize)
+	if err != nil {
+		return []byte{}, false
+	}
+
+	return v, true
+}
+
+// ReadVectorE is like ReadVector, but returns a *DecodeError if the read failed.
+// An invalid bitSize is reported with ErrInvalidBitSize instead of panicking,
+// a length that does not fit into an int is reported with ErrLengthOverflow
+// and a length that exceeds the MaxVectorLength limit of b is reported with ErrLimitExceeded.
+func (b *Buffer) ReadVectorE(bitSize int) ([]byte, error) {
+
+	n, err := b.readLengthE(bitSize, "ReadVector")
+	if err != nil {
+		return nil, err
+	}
+
+	return b.readE(n, "ReadVector")
+}
+
+// readLengthE reads a vector length with type depending on bitSize.
+// op is the name of the operation used in the returned *DecodeError.
+func (b *Buffer) readLengthE(bitSize int, op string) (int, error) {
+
+	off := b.off
+
+	var (
+		n   uint64
+		err error
+	)
+
+	switch bitSize {
+	case 8:
+		var n8 uint8
+		n8, err = b.ReadUint8E()
+		n = uint64(n8)
+	case 16:
+		var n16 uint16
+		n16, err = b.ReadUint16E()
+		n = uint64(n16)
+	case 24:
+		var n24 uint32
+		n24, err = b.ReadUint24E()
+		n = uint64(n24)
+	case 32:
+		var n32 uint32
+		n32, err = b.ReadUint32E()
+		n = uint64(n32)
+	case 64:
+		n, err = b.ReadUint64E()
+	case VarintLength:
+		n, err = b.ReadUvarintE()
+	case QUICVarintLength:
+		n, err = b.ReadQUICVarintE()
+	default:
+		return 0, &DecodeError{Op: op, Offset: b.base + off, Err: ErrInvalidBitSize}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if n > uint64(maxInt) {
+		return 0, &DecodeError{Op: op, Offset: b.base + off, Err: ErrLengthOverflow}
+	}
+
+	if b.alloc != nil {
+		if err := b.alloc.limits.checkVector(n); err != nil {
+			return 0, &DecodeError{Op: op, Offset: b.base + off, Err: err}
+		}
+	}
+
+	return int(n), nil
+}
+
+// ReadLittleFloat32 reads the next bytes from b and returns it as a float32 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleFloat32() (float32, bool) {
+
+	v := b.ReadBytes(4)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float32frombits(LittleEndian.Uint32(v)), true
+}
+
+// ReadLittleFloat32E is like ReadLittleFloat32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleFloat32E() (float32, error) {
+
+	v, err := b.readE(4, "ReadLittleFloat32")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float32frombits(LittleEndian.Uint32(v)), nil
+}
+
+// ReadBigFloat32 reads the next bytes from b and returns it as a float32 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigFloat32() (float32, bool) {
+
+	v := b.ReadBytes(4)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float32frombits(BigEndian.Uint32(v)), true
+}
+
+// ReadBigFloat32E is like ReadBigFloat32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigFloat32E() (float32, error) {
+
+	v, err := b.readE(4, "ReadBigFloat32")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float32frombits(BigEndian.Uint32(v)), nil
+}
+
+// ReadFloat32 reads the next bytes from b and returns it as a float32 in the byte order of b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadFloat32() (float32, bool) {
+
+	if b.little {
+		return b.ReadLittleFloat32()
+	}
+
+	return b.ReadBigFloat32()
+}
+
+// ReadFloat32E is like ReadFloat32, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadFloat32E() (float32, error) {
+
+	if b.little {
+		v, err := b.ReadLittleFloat32E()
+		return v, withOp(err, "ReadFloat32")
+	}
+
+	v, err := b.ReadBigFloat32E()
+
+	return v, withOp(err, "ReadFloat32")
+}
+
+// ReadLittleFloat64 reads the next bytes from b and returns it as a float64 in little-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleFloat64() (float64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float64frombits(LittleEndian.Uint64(v)), true
+}
+
+// ReadLittleFloat64E is like ReadLittleFloat64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleFloat64E() (float64, error) {
+
+	v, err := b.readE(8, "ReadLittleFloat64")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(LittleEndian.Uint64(v)), nil
+}
+
+// ReadBigFloat64 reads the next bytes from b and returns it as a float64 in big-endian order.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigFloat64() (float64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float64frombits(BigEndian.Uint64(v)), true
+}
+
+// ReadBigFloat64E is like ReadBigFloat64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigFloat64E() (float64, error) {
+
+	v, err := b.readE(8, "ReadBigFloat64")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(BigEndian.Uint64(v)), nil
+}
+
+// ReadFloat64 reads the next bytes from b and returns it as a float64 in the byte order of b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadFloat64() (float64, bool) {
+
+	if b.little {
+		return b.ReadLittleFloat64()
+	}
+
+	return b.ReadBigFloat64()
+}
+
+// ReadFloat64E is like ReadFloat64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadFloat64E() (float64, error) {
+
+	if b.little {
+		v, err := b.ReadLittleFloat64E()
+		return v, withOp(err, "ReadFloat64")
+	}
+
+	v, err := b.ReadBigFloat64E()
+
+	return v, withOp(err, "ReadFloat64")
+}
+
+// ReadLittleComplex64 reads the next bytes from b and returns it as a complex64 in little-endian order (real part first).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleComplex64() (complex64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float32frombits(LittleEndian.Uint32(v)), math.Float32frombits(LittleEndian.Uint32(v[4:]))), true
+}
+
+// ReadLittleComplex64E is like ReadLittleComplex64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleComplex64E() (complex64, error) {
+
+	v, err := b.readE(8, "ReadLittleComplex64")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float32frombits(LittleEndian.Uint32(v)), math.Float32frombits(LittleEndian.Uint32(v[4:]))), nil
+}
+
+// ReadBigComplex64 reads the next bytes from b and returns it as a complex64 in big-endian order (real part first).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigComplex64() (complex64, bool) {
+
+	v := b.ReadBytes(8)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float32frombits(BigEndian.Uint32(v)), math.Float32frombits(BigEndian.Uint32(v[4:]))), true
+}
+
+// ReadBigComplex64E is like ReadBigComplex64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigComplex64E() (complex64, error) {
+
+	v, err := b.readE(8, "ReadBigComplex64")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float32frombits(BigEndian.Uint32(v)), math.Float32frombits(BigEndian.Uint32(v[4:]))), nil
+}
+
+// ReadComplex64 reads the next bytes from b and returns it as a complex64 in the byte order of b (real part first).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadComplex64() (complex64, bool) {
+
+	if b.little {
+		return b.ReadLittleComplex64()
+	}
+
+	return b.ReadBigComplex64()
+}
+
+// ReadComplex64E is like ReadComplex64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadComplex64E() (complex64, error) {
+
+	if b.little {
+		v, err := b.ReadLittleComplex64E()
+		return v, withOp(err, "ReadComplex64")
+	}
+
+	v, err := b.ReadBigComplex64E()
+
+	return v, withOp(err, "ReadComplex64")
+}
+
+// ReadLittleComplex128 reads the next bytes from b and returns it as a complex128 in little-endian order (real part first).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadLittleComplex128() (complex128, bool) {
+
+	v := b.ReadBytes(16)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float64frombits(LittleEndian.Uint64(v)), math.Float64frombits(LittleEndian.Uint64(v[8:]))), true
+}
+
+// ReadLittleComplex128E is like ReadLittleComplex128, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLittleComplex128E() (complex128, error) {
+
+	v, err := b.readE(16, "ReadLittleComplex128")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float64frombits(LittleEndian.Uint64(v)), math.Float64frombits(LittleEndian.Uint64(v[8:]))), nil
+}
+
+// ReadBigComplex128 reads the next bytes from b and returns it as a complex128 in big-endian order (real part first).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadBigComplex128() (complex128, bool) {
+
+	v := b.ReadBytes(16)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float64frombits(BigEndian.Uint64(v)), math.Float64frombits(BigEndian.Uint64(v[8:]))), true
+}
+
+// ReadBigComplex128E is like ReadBigComplex128, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadBigComplex128E() (complex128, error) {
+
+	v, err := b.readE(16, "ReadBigComplex128")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float64frombits(BigEndian.Uint64(v)), math.Float64frombits(BigEndian.Uint64(v[8:]))), nil
+}
+
+// ReadComplex128 reads the next bytes from b and returns it as a complex128 in the byte order of b (real part first).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadComplex128() (complex128, bool) {
+
+	if b.little {
+		return b.ReadLittleComplex128()
+	}
+
+	return b.ReadBigComplex128()
+}
+
+// ReadComplex128E is like ReadComplex128, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadComplex128E() (complex128, error) {
+
+	if b.little {
+		v, err := b.ReadLittleComplex128E()
+		return v, withOp(err, "ReadComplex128")
+	}
+
+	v, err := b.ReadBigComplex128E()
+
+	return v, withOp(err, "ReadComplex128")
+}
+
+// ReadUint8LengthPrefixed reads an uint8 length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint8LengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(8, child, "ReadUint8LengthPrefixed")
+}
+
+// ReadUint16LengthPrefixed reads an uint16 length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint16LengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(16, child, "ReadUint16LengthPrefixed")
+}
+
+// ReadUint24LengthPrefixed reads a uint24 length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint24LengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(24, child, "ReadUint24LengthPrefixed")
+}
+
+// ReadUint32LengthPrefixed reads an uint32 length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint32LengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(32, child, "ReadUint32LengthPrefixed")
+}
+
+// ReadUint64LengthPrefixed reads an uint64 length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUint64LengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(64, child, "ReadUint64LengthPrefixed")
+}
+
+// ReadUvarintLengthPrefixed reads an unsigned LEB128 varint length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUvarintLengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(VarintLength, child, "ReadUvarintLengthPrefixed")
+}
+
+// ReadQUICVarintLengthPrefixed reads a QUIC variable-length integer length-prefixed vector and sets child to a Buffer over its content.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadQUICVarintLengthPrefixed(child *Buffer) error {
+	return b.readLengthPrefixed(QUICVarintLength, child, "ReadQUICVarintLengthPrefixed")
+}
+
+// ReadLengthPrefixed reads a length-prefixed vector and sets child to a Buffer over its content.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength.
+// Returns a *DecodeError if the read failed.
+func (b *Buffer) ReadLengthPrefixed(bitSize int, child *Buffer) error {
+	return b.readLengthPrefixed(bitSize, child, "ReadLengthPrefixed")
+}
+
+// readLengthPrefixed reads a length-prefixed vector into child.
+// The child can not read past the end of the vector, its capacity is limited,
+// so writing to the child does not overwrite the bytes of b.
+// The child uses the settings of b: the byte order, the limits and the ASN.1, UTF-8 and varint modes.
+func (b *Buffer) readLengthPrefixed(bitSize int, child *Buffer, op string) error {
+
+	n, err := b.readLengthE(bitSize, op)
+	if err != nil {
+		return err
+	}
+
+	v, err := b.readE(n, op)
+	if err != nil {
+		return err
+	}
+
+	*child = Buffer{b: v[:n:n], base: b.base + b.off - n, little: b.little, alloc: b.alloc, der: b.der, utf8: b.utf8, strictVarint: b.strictVarint}
+
+	return nil
+}
+
+// ExpectEmpty returns a *DecodeError wrapping ErrTrailingData if b has unread bytes.
+// It is used to check that the content of a vector was parsed completely.
+func (b *Buffer) ExpectEmpty() error {
+
+	if b.Empty() {
+		return nil
+	}
+
+	return &DecodeError{Op: "ExpectEmpty", Offset: b.base + b.off, Have: b.Remaining(), Err: ErrTrailingData}
 }
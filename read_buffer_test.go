@@ -0,0 +1,297 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBufferEndianness(t *testing.T) {
+
+	tests := []struct {
+		e    Endianness
+		want []byte
+	}{
+		{BigEndian, []byte{0x01, 0x02, 0x01, 0x02, 0x03, 0x01, 0x02, 0x03, 0x04, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}},
+		{LittleEndian, []byte{0x02, 0x01, 0x03, 0x02, 0x01, 0x04, 0x03, 0x02, 0x01, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBufferWithEndianness(nil, tt.e)
+		b.WriteUint16(0x0102)
+		b.WriteUint24(0x010203)
+		b.WriteUint32(0x01020304)
+		b.WriteUint64(0x0102030405060708)
+
+		if string(b.Bytes()) != string(tt.want) {
+			t.Fatalf("%v: wrote % x, want % x", tt.e, b.Bytes(), tt.want)
+		}
+
+		if v, err := b.ReadUint16E(); err != nil || v != 0x0102 {
+			t.Fatalf("%v: ReadUint16E = %#x, %v", tt.e, v, err)
+		}
+
+		if v, err := b.ReadUint24E(); err != nil || v != 0x010203 {
+			t.Fatalf("%v: ReadUint24E = %#x, %v", tt.e, v, err)
+		}
+
+		if v, err := b.ReadUint32E(); err != nil || v != 0x01020304 {
+			t.Fatalf("%v: ReadUint32E = %#x, %v", tt.e, v, err)
+		}
+
+		if v, err := b.ReadUint64E(); err != nil || v != 0x0102030405060708 {
+			t.Fatalf("%v: ReadUint64E = %#x, %v", tt.e, v, err)
+		}
+	}
+
+	// The explicit methods ignore the byte order of the Buffer.
+	b := NewBufferWithEndianness(nil, LittleEndian)
+	b.WriteBigUint16(0x0102)
+	b.WriteLittleUint16(0x0102)
+	b.SetEndianness(BigEndian)
+
+	if v, ok := b.ReadUint16(); !ok || v != 0x0102 {
+		t.Fatalf("ReadUint16 = %#x, %t", v, ok)
+	}
+
+	if v, ok := b.ReadLittleUint16(); !ok || v != 0x0102 {
+		t.Fatalf("ReadLittleUint16 = %#x, %t", v, ok)
+	}
+}
+
+// TestBufferErrorOp checks that the byte order neutral reads report their own name, not the name of the read they call.
+func TestBufferErrorOp(t *testing.T) {
+
+	tests := []struct {
+		op   string
+		read func(b *Buffer) error
+	}{
+		{"ReadUint16", func(b *Buffer) error { _, err := b.ReadUint16E(); return err }},
+		{"ReadInt24", func(b *Buffer) error { _, err := b.ReadInt24E(); return err }},
+		{"ReadUint32", func(b *Buffer) error { _, err := b.ReadUint32E(); return err }},
+		{"ReadInt64", func(b *Buffer) error { _, err := b.ReadInt64E(); return err }},
+	}
+
+	for _, tt := range tests {
+		for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+			b := NewBufferWithEndianness([]byte{1}, e)
+
+			err := tt.read(&b)
+
+			var de *DecodeError
+
+			if !errors.As(err, &de) || de.Op != tt.op || de.Offset != 0 || !errors.Is(err, ErrShortBuffer) {
+				t.Fatalf("%v: error = %v, want Op %s", e, err, tt.op)
+			}
+
+			if b.Offset() != 0 {
+				t.Fatalf("%v: %s moved the cursor to %d", e, tt.op, b.Offset())
+			}
+		}
+	}
+}
+
+func TestBufferFloat(t *testing.T) {
+
+	// A signaling NaN with a payload and the negative zero must survive bit-exact.
+	nan32 := math.Float32frombits(0x7fa00001)
+	nan64 := math.Float64frombits(0x7ff4000000000001)
+	negZero := math.Copysign(0, -1)
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		b := NewBufferWithEndianness(nil, e)
+		b.WriteFloat32(nan32)
+		b.WriteFloat64(nan64)
+		b.WriteFloat64(negZero)
+		b.WriteComplex64(complex(float32(1.5), nan32))
+		b.WriteComplex128(complex(-2.5, math.Inf(1)))
+
+		if b.Size() != 4+8+8+8+16 {
+			t.Fatalf("%v: wrote %d bytes", e, b.Size())
+		}
+
+		if v, err := b.ReadFloat32E(); err != nil || math.Float32bits(v) != 0x7fa00001 {
+			t.Fatalf("%v: ReadFloat32E = %#x, %v", e, math.Float32bits(v), err)
+		}
+
+		if v, err := b.ReadFloat64E(); err != nil || math.Float64bits(v) != 0x7ff4000000000001 {
+			t.Fatalf("%v: ReadFloat64E = %#x, %v", e, math.Float64bits(v), err)
+		}
+
+		if v, err := b.ReadFloat64E(); err != nil || v != 0 || !math.Signbit(v) {
+			t.Fatalf("%v: ReadFloat64E = %v, %v, want -0", e, v, err)
+		}
+
+		if v, err := b.ReadComplex64E(); err != nil || real(v) != 1.5 || math.Float32bits(imag(v)) != 0x7fa00001 {
+			t.Fatalf("%v: ReadComplex64E = %v, %v", e, v, err)
+		}
+
+		if v, err := b.ReadComplex128E(); err != nil || v != complex(-2.5, math.Inf(1)) {
+			t.Fatalf("%v: ReadComplex128E = %v, %v", e, v, err)
+		}
+	}
+
+	// The explicit byte orders match encoding/binary.
+	b := NewBuffer(nil)
+	b.WriteLittleFloat64(1.5)
+	b.WriteBigFloat32(1.5)
+
+	want := binary.LittleEndian.AppendUint64(nil, math.Float64bits(1.5))
+	want = binary.BigEndian.AppendUint32(want, math.Float32bits(1.5))
+
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Fatalf("wrote % x, want % x", b.Bytes(), want)
+	}
+}
+
+func TestBufferFloatErrorOp(t *testing.T) {
+
+	tests := []struct {
+		op   string
+		read func(b *Buffer) error
+	}{
+		{"ReadFloat32", func(b *Buffer) error { _, err := b.ReadFloat32E(); return err }},
+		{"ReadFloat64", func(b *Buffer) error { _, err := b.ReadFloat64E(); return err }},
+		{"ReadComplex64", func(b *Buffer) error { _, err := b.ReadComplex64E(); return err }},
+		{"ReadComplex128", func(b *Buffer) error { _, err := b.ReadComplex128E(); return err }},
+	}
+
+	for _, tt := range tests {
+		for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+			b := NewBufferWithEndianness(make([]byte, 3), e)
+
+			err := tt.read(&b)
+
+			var de *DecodeError
+
+			if !errors.As(err, &de) || de.Op != tt.op || de.Want == 0 || de.Have != 3 || !errors.Is(err, ErrShortBuffer) {
+				t.Fatalf("%v: error = %v, want Op %s", e, err, tt.op)
+			}
+		}
+	}
+}
+
+func TestBufferLengthPrefixed(t *testing.T) {
+
+	b := NewBuffer([]byte{0x00, 0x03, 0x01, 0x02, 0x03, 0x04})
+
+	var c Buffer
+
+	if err := b.ReadUint16LengthPrefixed(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Size() != 3 || b.Offset() != 5 {
+		t.Fatalf("child has %d bytes, Offset = %d", c.Size(), b.Offset())
+	}
+
+	// The child can not read past the end of the vector, even if the parent has more bytes.
+	if _, err := c.ReadUint32E(); !errors.Is(err, ErrShortBuffer) {
+		t.Fatalf("ReadUint32E in the child = %v, want ErrShortBuffer", err)
+	}
+
+	c.WriteUint8(0xff)
+
+	if v, ok := b.ReadUint8(); !ok || v != 0x04 {
+		t.Fatalf("a write in the child changed the parent: ReadUint8 = %#x", v)
+	}
+
+	c.Skip(3)
+
+	var de *DecodeError
+
+	if err := c.ExpectEmpty(); !errors.As(err, &de) || de.Offset != 5 || de.Have != 1 || !errors.Is(err, ErrTrailingData) {
+		t.Fatalf("ExpectEmpty = %v, want ErrTrailingData at offset 5", err)
+	}
+
+	// The vector is longer than the rest of the Buffer.
+	b = NewBuffer([]byte{0x05, 0x01, 0x02})
+
+	if err := b.ReadUint8LengthPrefixed(&c); !errors.As(err, &de) || de.Op != "ReadUint8LengthPrefixed" || de.Offset != 1 || de.Want != 5 {
+		t.Fatalf("ReadUint8LengthPrefixed = %v", err)
+	}
+}
+
+// TestBufferChildOffset checks that the errors of child Buffers report the offset in the outermost Buffer.
+func TestBufferChildOffset(t *testing.T) {
+
+	b := NewBuffer([]byte{0xaa, 0xbb, 3, 2, 1, 2})
+	b.Skip(2)
+
+	var c, cc Buffer
+
+	if err := b.ReadLengthPrefixed(8, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.BaseOffset() != 3 {
+		t.Fatalf("BaseOffset = %d, want 3", c.BaseOffset())
+	}
+
+	if err := c.ReadLengthPrefixed(8, &cc); err != nil {
+		t.Fatal(err)
+	}
+
+	cc.Skip(1)
+
+	tests := []struct {
+		op   string
+		read func(b *Buffer) error
+	}{
+		{"ReadUint16", func(b *Buffer) error { _, err := b.ReadUint16E(); return err }},
+		{"ReadVector", func(b *Buffer) error { _, err := b.ReadVectorE(12); return err }},
+		{"ReadUvarint", func(b *Buffer) error { _, err := b.ReadUvarintE(); return err }},
+		{"ReadQUICVarint", func(b *Buffer) error { _, err := b.ReadQUICVarintE(); return err }},
+		{"ReadBits", func(b *Buffer) error { _, err := NewBitReader(b, MSBFirst).ReadBits(65); return err }},
+		{"ExpectEmpty", func(b *Buffer) error { return b.ExpectEmpty() }},
+	}
+
+	for _, tt := range tests {
+
+		d := cc
+		d.SetStrictVarint(true)
+
+		// 0x02 is a varint with a redundant zero byte, and a 4 byte QUIC varint prefix.
+		d.b = []byte{0x01, 0x82}
+
+		err := tt.read(&d)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != 5 {
+			t.Fatalf("%s error = %v, want offset 5", tt.op, err)
+		}
+	}
+}
+
+func TestDecoderLengthPrefixed(t *testing.T) {
+
+	b := NewBuffer([]byte{0x00, 0x03, 0x01, 0x02, 0x03, 0x02, 0xaa})
+	d := NewDecoder(&b)
+
+	c := d.ReadUint16LengthPrefixed()
+	v := c.ReadUint16()
+	c.ExpectEmpty()
+
+	if err := d.Err(); err == nil || !errors.Is(err, ErrTrailingData) || v != 0x0102 {
+		t.Fatalf("Err = %v, want ErrTrailingData", err)
+	}
+
+	// The error of the child stops the parent.
+	if d.ReadUint8() != 0 || d.Offset() != 5 {
+		t.Fatalf("read after the error of the child moved the parent to %d", d.Offset())
+	}
+
+	b = NewBuffer([]byte{0x02, 0xaa, 0xbb})
+	d = NewDecoder(&b)
+
+	if c := d.ReadUint8LengthPrefixed(); c.ReadUint16() != 0xaabb || d.Err() != nil {
+		t.Fatalf("Err = %v", d.Err())
+	}
+}
@@ -1,12 +1,15 @@
 package bytebuilder
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"math"
 )
 
-// ReadReaderBytes reads n byte from in.
+// ReadReaderBytes reads exactly n byte from in.
 // At the end of the file, io.EOF is returned.
+// If the end of the file is reached after reading some but not all the bytes, io.ErrUnexpectedEOF is returned.
 func ReadReaderBytes(in io.Reader, n int) ([]byte, error) {
 
 	if n < 0 {
@@ -15,9 +18,9 @@ func ReadReaderBytes(in io.Reader, n int) ([]byte, error) {
 
 	b := make([]byte, n)
 
-	_, err := in.Read(b)
+	m, err := io.ReadFull(in, b)
 
-	return b, err
+	return b[:m], err
 }
 
 // ReadReaderSkip skips n bytes in in.
@@ -34,6 +37,9 @@ func SkipReader(in io.Reader, n int) error {
 func ReadReaderUint8(in io.Reader) (uint8, error) {
 
 	v, err := ReadReaderBytes(in, 1)
+	if err != nil {
+		return 0, err
+	}
 
 	return uint8(v[0]), err
 }
@@ -73,3 +79,694 @@ func ReadReaderUint32(in io.Reader) (uint32, error) {
 
 	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), err
 }
+
+// StreamReader decodes values from an io.Reader.
+// Every read is a full read: a value is either read completely or an error is returned.
+// If no bytes were read, the error is io.EOF.
+// If the end of the stream is reached in the middle of a value,
+// the error is a *DecodeError wrapping io.ErrUnexpectedEOF.
+//
+// Multi-byte integers are read in big-endian order by default,
+// the byte order can be changed with SetEndianness.
+type StreamReader struct {
+	r            io.Reader
+	n            int64     // number of bytes consumed from r
+	little       bool      // use little-endian byte order
+	buf          [16]byte  // scratch space for fixed size values
+	alloc        allocator // decoding limits
+	utf8         bool      // validate the UTF-8 encoding of the strings read
+	strictVarint bool      // reject the non-minimal varint encodings
+}
+
+// NewStreamReader creates a StreamReader that reads from r.
+func NewStreamReader(r io.Reader) *StreamReader {
+	return &StreamReader{r: r}
+}
+
+// NewStreamReaderWithEndianness creates a StreamReader that reads from r in byte order e.
+func NewStreamReaderWithEndianness(r io.Reader, e Endianness) *StreamReader {
+	return &StreamReader{r: r, little: e == LittleEndian}
+}
+
+// Endianness returns the byte order used by s.
+func (s *StreamReader) Endianness() Endianness {
+
+	if s.little {
+		return LittleEndian
+	}
+
+	return BigEndian
+}
+
+// SetEndianness sets the byte order used by s to e.
+func (s *StreamReader) SetEndianness(e Endianness) {
+	s.little = e == LittleEndian
+}
+
+// Offset returns the number of bytes consumed from the underlying reader.
+func (s *StreamReader) Offset() int64 {
+	return s.n
+}
+
+// readFull reads exactly len(p) bytes into p.
+// op is the name of the operation used in the returned *DecodeError.
+func (s *StreamReader) readFull(p []byte, op string) error {
+
+	off := s.n
+
+	n, err := io.ReadFull(s.r, p)
+	s.n += int64(n)
+
+	if err == io.ErrUnexpectedEOF {
+		return &DecodeError{Op: op, Offset: int(off), Want: len(p), Have: n, Err: io.ErrUnexpectedEOF}
+	}
+
+	return err
+}
+
+// read reads exactly n bytes (up to 16) into the scratch space of s and returns it.
+// The returned slice is valid until the next read.
+func (s *StreamReader) read(n int, op string) ([]byte, error) {
+
+	v := s.buf[:n]
+
+	if err := s.readFull(v, op); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Read reads up to len(p) bytes into p.
+// Read implements the io.Reader interface.
+func (s *StreamReader) Read(p []byte) (int, error) {
+
+	n, err := s.r.Read(p)
+	s.n += int64(n)
+
+	return n, err
+}
+
+// ReadBytes reads exactly n bytes from s.
+func (s *StreamReader) ReadBytes(n int) ([]byte, error) {
+
+	if n < 0 {
+		return nil, &DecodeError{Op: "ReadBytes", Offset: int(s.n), Want: n, Err: ErrInvalidLength}
+	}
+
+	if err := s.alloc.alloc(n); err != nil {
+		return nil, &DecodeError{Op: "ReadBytes", Offset: int(s.n), Err: err}
+	}
+
+	// Grow the slice while the data arrives, so a large n does not allocate before the data is available.
+	off := s.n
+	v := make([]byte, 0, minInt(n, maxStreamChunk))
+
+	for len(v) < n {
+
+		m := len(v)
+		v = append(v[:m], make([]byte, minInt(n-m, maxStreamChunk))...)
+
+		err := s.readFull(v[m:], "ReadBytes")
+		if (err == io.EOF && m > 0) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, &DecodeError{Op: "ReadBytes", Offset: int(off), Want: n, Have: int(s.n - off), Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// maxStreamChunk is the maximum number of bytes allocated by ReadBytes before the data is read.
+const maxStreamChunk = 64 << 10
+
+// minInt returns the smaller of a and b.
+func minInt(a, b int) int {
+
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// Skip discards the next n bytes of s.
+// If the underlying reader implements io.Seeker, Skip seeks instead of reading,
+// the bytes are read and discarded if seeking fails.
+// Seeking beyond the end of the stream is not an error, the next read returns io.EOF.
+func (s *StreamReader) Skip(n int64) error {
+
+	if n < 0 {
+		return &DecodeError{Op: "Skip", Offset: int(s.n), Want: int(n), Err: ErrInvalidLength}
+	}
+
+	if seeker, ok := s.r.(io.Seeker); ok {
+
+		// Not every io.Seeker can seek (eg.: an *os.File of a pipe), fall back to reading if it fails.
+		if _, err := seeker.Seek(n, io.SeekCurrent); err == nil {
+			s.n += n
+			return nil
+		}
+	}
+
+	m, err := io.CopyN(io.Discard, s.r, n)
+	s.n += m
+
+	switch {
+	case err == io.EOF && m == 0:
+		return io.EOF
+	case err == io.EOF:
+		return &DecodeError{Op: "Skip", Offset: int(s.n - m), Want: int(n), Have: int(m), Err: io.ErrUnexpectedEOF}
+	default:
+		return err
+	}
+}
+
+// ReadByte reads the next byte from s.
+// ReadByte implements the io.ByteReader interface.
+func (s *StreamReader) ReadByte() (byte, error) {
+
+	v, err := s.read(1, "ReadByte")
+	if err != nil {
+		return 0, err
+	}
+
+	return v[0], nil
+}
+
+// ReadUint8 reads a byte from s and returns it as an uint8.
+func (s *StreamReader) ReadUint8() (uint8, error) {
+
+	v, err := s.read(1, "ReadUint8")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint8(v[0]), nil
+}
+
+// ReadInt8 reads a byte from s and returns it as an int8.
+func (s *StreamReader) ReadInt8() (int8, error) {
+
+	v, err := s.read(1, "ReadInt8")
+	if err != nil {
+		return 0, err
+	}
+
+	return int8(v[0]), nil
+}
+
+// ReadLittleUint16 reads bytes from s and returns it as an uint16 in little-endian order.
+func (s *StreamReader) ReadLittleUint16() (uint16, error) {
+
+	v, err := s.read(2, "ReadLittleUint16")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint16(v[1])<<8 | uint16(v[0]), nil
+}
+
+// ReadBigUint16 reads bytes from s and returns it as an uint16 in big-endian order.
+func (s *StreamReader) ReadBigUint16() (uint16, error) {
+
+	v, err := s.read(2, "ReadBigUint16")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint16(v[0])<<8 | uint16(v[1]), nil
+}
+
+// ReadUint16 reads bytes from s and returns it as an uint16 in the byte order of s.
+func (s *StreamReader) ReadUint16() (uint16, error) {
+
+	if s.little {
+		v, err := s.ReadLittleUint16()
+		return v, withOp(err, "ReadUint16")
+	}
+
+	v, err := s.ReadBigUint16()
+
+	return v, withOp(err, "ReadUint16")
+}
+
+// ReadLittleInt16 reads bytes from s and returns it as an int16 in little-endian order.
+func (s *StreamReader) ReadLittleInt16() (int16, error) {
+
+	v, err := s.read(2, "ReadLittleInt16")
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(v[1])<<8 | int16(v[0]), nil
+}
+
+// ReadBigInt16 reads bytes from s and returns it as an int16 in big-endian order.
+func (s *StreamReader) ReadBigInt16() (int16, error) {
+
+	v, err := s.read(2, "ReadBigInt16")
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(v[0])<<8 | int16(v[1]), nil
+}
+
+// ReadInt16 reads bytes from s and returns it as an int16 in the byte order of s.
+func (s *StreamReader) ReadInt16() (int16, error) {
+
+	if s.little {
+		v, err := s.ReadLittleInt16()
+		return v, withOp(err, "ReadInt16")
+	}
+
+	v, err := s.ReadBigInt16()
+
+	return v, withOp(err, "ReadInt16")
+}
+
+// ReadLittleUint24 reads bytes from s and returns it as a uint32 in little-endian order.
+func (s *StreamReader) ReadLittleUint24() (uint32, error) {
+
+	v, err := s.read(3, "ReadLittleUint24")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), nil
+}
+
+// ReadBigUint24 reads bytes from s and returns it as a uint32 in big-endian order.
+func (s *StreamReader) ReadBigUint24() (uint32, error) {
+
+	v, err := s.read(3, "ReadBigUint24")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[0])<<16 | uint32(v[1])<<8 | uint32(v[2]), nil
+}
+
+// ReadUint24 reads bytes from s and returns it as a uint32 in the byte order of s.
+func (s *StreamReader) ReadUint24() (uint32, error) {
+
+	if s.little {
+		v, err := s.ReadLittleUint24()
+		return v, withOp(err, "ReadUint24")
+	}
+
+	v, err := s.ReadBigUint24()
+
+	return v, withOp(err, "ReadUint24")
+}
+
+// ReadLittleInt24 reads bytes from s and returns it as an int32 in little-endian order.
+func (s *StreamReader) ReadLittleInt24() (int32, error) {
+
+	v, err := s.read(3, "ReadLittleInt24")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), nil
+}
+
+// ReadBigInt24 reads bytes from s and returns it as an int32 in big-endian order.
+func (s *StreamReader) ReadBigInt24() (int32, error) {
+
+	v, err := s.read(3, "ReadBigInt24")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[0])<<16 | int32(v[1])<<8 | int32(v[2]), nil
+}
+
+// ReadInt24 reads bytes from s and returns it as an int32 in the byte order of s.
+func (s *StreamReader) ReadInt24() (int32, error) {
+
+	if s.little {
+		v, err := s.ReadLittleInt24()
+		return v, withOp(err, "ReadInt24")
+	}
+
+	v, err := s.ReadBigInt24()
+
+	return v, withOp(err, "ReadInt24")
+}
+
+// ReadLittleUint32 reads bytes from s and returns it as an uint32 in little-endian order.
+func (s *StreamReader) ReadLittleUint32() (uint32, error) {
+
+	v, err := s.read(4, "ReadLittleUint32")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[3])<<24 | uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), nil
+}
+
+// ReadBigUint32 reads bytes from s and returns it as an uint32 in big-endian order.
+func (s *StreamReader) ReadBigUint32() (uint32, error) {
+
+	v, err := s.read(4, "ReadBigUint32")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), nil
+}
+
+// ReadUint32 reads bytes from s and returns it as an uint32 in the byte order of s.
+func (s *StreamReader) ReadUint32() (uint32, error) {
+
+	if s.little {
+		v, err := s.ReadLittleUint32()
+		return v, withOp(err, "ReadUint32")
+	}
+
+	v, err := s.ReadBigUint32()
+
+	return v, withOp(err, "ReadUint32")
+}
+
+// ReadLittleInt32 reads bytes from s and returns it as an int32 in little-endian order.
+func (s *StreamReader) ReadLittleInt32() (int32, error) {
+
+	v, err := s.read(4, "ReadLittleInt32")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[3])<<24 | int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), nil
+}
+
+// ReadBigInt32 reads bytes from s and returns it as an int32 in big-endian order.
+func (s *StreamReader) ReadBigInt32() (int32, error) {
+
+	v, err := s.read(4, "ReadBigInt32")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[0])<<24 | int32(v[1])<<16 | int32(v[2])<<8 | int32(v[3]), nil
+}
+
+// ReadInt32 reads bytes from s and returns it as an int32 in the byte order of s.
+func (s *StreamReader) ReadInt32() (int32, error) {
+
+	if s.little {
+		v, err := s.ReadLittleInt32()
+		return v, withOp(err, "ReadInt32")
+	}
+
+	v, err := s.ReadBigInt32()
+
+	return v, withOp(err, "ReadInt32")
+}
+
+// ReadLittleUint64 reads bytes from s and returns it as an uint64 in little-endian order.
+func (s *StreamReader) ReadLittleUint64() (uint64, error) {
+
+	v, err := s.read(8, "ReadLittleUint64")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(v[7])<<56 | uint64(v[6])<<48 | uint64(v[5])<<40 | uint64(v[4])<<32 | uint64(v[3])<<24 | uint64(v[2])<<16 | uint64(v[1])<<8 | uint64(v[0]), nil
+}
+
+// ReadBigUint64 reads bytes from s and returns it as an uint64 in big-endian order.
+func (s *StreamReader) ReadBigUint64() (uint64, error) {
+
+	v, err := s.read(8, "ReadBigUint64")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(v[0])<<56 | uint64(v[1])<<48 | uint64(v[2])<<40 | uint64(v[3])<<32 | uint64(v[4])<<24 | uint64(v[5])<<16 | uint64(v[6])<<8 | uint64(v[7]), nil
+}
+
+// ReadUint64 reads bytes from s and returns it as an uint64 in the byte order of s.
+func (s *StreamReader) ReadUint64() (uint64, error) {
+
+	if s.little {
+		v, err := s.ReadLittleUint64()
+		return v, withOp(err, "ReadUint64")
+	}
+
+	v, err := s.ReadBigUint64()
+
+	return v, withOp(err, "ReadUint64")
+}
+
+// ReadLittleInt64 reads bytes from s and returns it as an int64 in little-endian order.
+func (s *StreamReader) ReadLittleInt64() (int64, error) {
+
+	v, err := s.read(8, "ReadLittleInt64")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v[7])<<56 | int64(v[6])<<48 | int64(v[5])<<40 | int64(v[4])<<32 | int64(v[3])<<24 | int64(v[2])<<16 | int64(v[1])<<8 | int64(v[0]), nil
+}
+
+// ReadBigInt64 reads bytes from s and returns it as an int64 in big-endian order.
+func (s *StreamReader) ReadBigInt64() (int64, error) {
+
+	v, err := s.read(8, "ReadBigInt64")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v[0])<<56 | int64(v[1])<<48 | int64(v[2])<<40 | int64(v[3])<<32 | int64(v[4])<<24 | int64(v[5])<<16 | int64(v[6])<<8 | int64(v[7]), nil
+}
+
+// ReadInt64 reads bytes from s and returns it as an int64 in the byte order of s.
+func (s *StreamReader) ReadInt64() (int64, error) {
+
+	if s.little {
+		v, err := s.ReadLittleInt64()
+		return v, withOp(err, "ReadInt64")
+	}
+
+	v, err := s.ReadBigInt64()
+
+	return v, withOp(err, "ReadInt64")
+}
+
+// ReadVector reads the length of bytes then the bytes itself.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64)
+// and it is read in the byte order of s.
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength, otherwise ErrInvalidBitSize is returned.
+// If the end of the stream is reached after the length, io.ErrUnexpectedEOF is returned.
+func (s *StreamReader) ReadVector(bitSize int) ([]byte, error) {
+
+	n, err := s.readLength(bitSize, "ReadVector")
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.ReadBytes(n)
+	if err == io.EOF {
+		err = &DecodeError{Op: "ReadVector", Offset: int(s.n), Want: n, Err: io.ErrUnexpectedEOF}
+	}
+
+	return v, withOp(err, "ReadVector")
+}
+
+// readLength reads a vector length with type depending on bitSize.
+// op is the name of the operation used in the returned *DecodeError.
+func (s *StreamReader) readLength(bitSize int, op string) (int, error) {
+
+	off := s.n
+
+	var (
+		n   uint64
+		err error
+	)
+
+	switch bitSize {
+	case 8:
+		var n8 uint8
+		n8, err = s.ReadUint8()
+		n = uint64(n8)
+	case 16:
+		var n16 uint16
+		n16, err = s.ReadUint16()
+		n = uint64(n16)
+	case 24:
+		var n24 uint32
+		n24, err = s.ReadUint24()
+		n = uint64(n24)
+	case 32:
+		var n32 uint32
+		n32, err = s.ReadUint32()
+		n = uint64(n32)
+	case 64:
+		n, err = s.ReadUint64()
+	case VarintLength:
+		n, err = s.ReadUvarint()
+	case QUICVarintLength:
+		n, err = s.ReadQUICVarint()
+	default:
+		return 0, &DecodeError{Op: op, Offset: int(off), Err: ErrInvalidBitSize}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if n > uint64(maxInt) {
+		return 0, &DecodeError{Op: op, Offset: int(off), Err: ErrLengthOverflow}
+	}
+
+	if err := s.alloc.limits.checkVector(n); err != nil {
+		return 0, &DecodeError{Op: op, Offset: int(off), Err: err}
+	}
+
+	return int(n), nil
+}
+
+// ReadLittleFloat32 reads bytes from s and returns it as a float32 in little-endian order.
+func (s *StreamReader) ReadLittleFloat32() (float32, error) {
+
+	v, err := s.read(4, "ReadLittleFloat32")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float32frombits(LittleEndian.Uint32(v)), nil
+}
+
+// ReadBigFloat32 reads bytes from s and returns it as a float32 in big-endian order.
+func (s *StreamReader) ReadBigFloat32() (float32, error) {
+
+	v, err := s.read(4, "ReadBigFloat32")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float32frombits(BigEndian.Uint32(v)), nil
+}
+
+// ReadFloat32 reads bytes from s and returns it as a float32 in the byte order of s.
+func (s *StreamReader) ReadFloat32() (float32, error) {
+
+	if s.little {
+		v, err := s.ReadLittleFloat32()
+		return v, withOp(err, "ReadFloat32")
+	}
+
+	v, err := s.ReadBigFloat32()
+
+	return v, withOp(err, "ReadFloat32")
+}
+
+// ReadLittleFloat64 reads bytes from s and returns it as a float64 in little-endian order.
+func (s *StreamReader) ReadLittleFloat64() (float64, error) {
+
+	v, err := s.read(8, "ReadLittleFloat64")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(LittleEndian.Uint64(v)), nil
+}
+
+// ReadBigFloat64 reads bytes from s and returns it as a float64 in big-endian order.
+func (s *StreamReader) ReadBigFloat64() (float64, error) {
+
+	v, err := s.read(8, "ReadBigFloat64")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(BigEndian.Uint64(v)), nil
+}
+
+// ReadFloat64 reads bytes from s and returns it as a float64 in the byte order of s.
+func (s *StreamReader) ReadFloat64() (float64, error) {
+
+	if s.little {
+		v, err := s.ReadLittleFloat64()
+		return v, withOp(err, "ReadFloat64")
+	}
+
+	v, err := s.ReadBigFloat64()
+
+	return v, withOp(err, "ReadFloat64")
+}
+
+// ReadLittleComplex64 reads bytes from s and returns it as a complex64 in little-endian order (real part first).
+func (s *StreamReader) ReadLittleComplex64() (complex64, error) {
+
+	v, err := s.read(8, "ReadLittleComplex64")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float32frombits(LittleEndian.Uint32(v)), math.Float32frombits(LittleEndian.Uint32(v[4:]))), nil
+}
+
+// ReadBigComplex64 reads bytes from s and returns it as a complex64 in big-endian order (real part first).
+func (s *StreamReader) ReadBigComplex64() (complex64, error) {
+
+	v, err := s.read(8, "ReadBigComplex64")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float32frombits(BigEndian.Uint32(v)), math.Float32frombits(BigEndian.Uint32(v[4:]))), nil
+}
+
+// ReadComplex64 reads bytes from s and returns it as a complex64 in the byte order of s (real part first).
+func (s *StreamReader) ReadComplex64() (complex64, error) {
+
+	if s.little {
+		v, err := s.ReadLittleComplex64()
+		return v, withOp(err, "ReadComplex64")
+	}
+
+	v, err := s.ReadBigComplex64()
+
+	return v, withOp(err, "ReadComplex64")
+}
+
+// ReadLittleComplex128 reads bytes from s and returns it as a complex128 in little-endian order (real part first).
+func (s *StreamReader) ReadLittleComplex128() (complex128, error) {
+
+	v, err := s.read(16, "ReadLittleComplex128")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float64frombits(LittleEndian.Uint64(v)), math.Float64frombits(LittleEndian.Uint64(v[8:]))), nil
+}
+
+// ReadBigComplex128 reads bytes from s and returns it as a complex128 in big-endian order (real part first).
+func (s *StreamReader) ReadBigComplex128() (complex128, error) {
+
+	v, err := s.read(16, "ReadBigComplex128")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float64frombits(BigEndian.Uint64(v)), math.Float64frombits(BigEndian.Uint64(v[8:]))), nil
+}
+
+// ReadComplex128 reads bytes from s and returns it as a complex128 in the byte order of s (real part first).
+func (s *StreamReader) ReadComplex128() (complex128, error) {
+
+	if s.little {
+		v, err := s.ReadLittleComplex128()
+		return v, withOp(err, "ReadComplex128")
+	}
+
+	v, err := s.ReadBigComplex128()
+
+	return v, withOp(err, "ReadComplex128")
+}
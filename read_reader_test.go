@@ -0,0 +1,206 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestStreamReaderRoundTrip(t *testing.T) {
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		b := NewBufferWithEndianness(nil, e)
+		b.WriteUint16(0x0102)
+		b.WriteUint24(0x010203)
+		b.WriteInt32(-2)
+		b.WriteUint64(0x0102030405060708)
+		b.WriteVector([]byte("abc"), 16)
+
+		s := NewStreamReaderWithEndianness(bytes.NewReader(b.Bytes()), e)
+
+		if v, err := s.ReadUint16(); err != nil || v != 0x0102 {
+			t.Fatalf("%v: ReadUint16 = %#x, %v", e, v, err)
+		}
+
+		if v, err := s.ReadUint24(); err != nil || v != 0x010203 {
+			t.Fatalf("%v: ReadUint24 = %#x, %v", e, v, err)
+		}
+
+		if v, err := s.ReadInt32(); err != nil || v != -2 {
+			t.Fatalf("%v: ReadInt32 = %d, %v", e, v, err)
+		}
+
+		if v, err := s.ReadUint64(); err != nil || v != 0x0102030405060708 {
+			t.Fatalf("%v: ReadUint64 = %#x, %v", e, v, err)
+		}
+
+		if v, err := s.ReadVector(16); err != nil || string(v) != "abc" {
+			t.Fatalf("%v: ReadVector = %q, %v", e, v, err)
+		}
+
+		if _, err := s.ReadUint8(); err != io.EOF {
+			t.Fatalf("%v: ReadUint8 at the end = %v, want io.EOF", e, err)
+		}
+	}
+}
+
+func TestStreamReaderErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		read   func(s *StreamReader) error
+		op     string
+		offset int
+		err    error
+	}{
+		{"eof", nil, func(s *StreamReader) error { _, err := s.ReadUint32(); return err }, "", 0, io.EOF},
+		{"unexpected eof", []byte{1, 2, 3}, func(s *StreamReader) error { _, err := s.ReadUint32(); return err }, "ReadUint32", 0, io.ErrUnexpectedEOF},
+		{"after read", []byte{1, 2, 3}, func(s *StreamReader) error { s.ReadUint8(); _, err := s.ReadInt64(); return err }, "ReadInt64", 1, io.ErrUnexpectedEOF},
+		{"vector", []byte{0, 4, 1, 2}, func(s *StreamReader) error { _, err := s.ReadVector(16); return err }, "ReadVector", 2, io.ErrUnexpectedEOF},
+		{"bitSize", []byte{0, 4, 1, 2}, func(s *StreamReader) error { _, err := s.ReadVector(12); return err }, "ReadVector", 0, ErrInvalidBitSize},
+		{"skip", []byte{1, 2}, func(s *StreamReader) error { return s.Skip(3) }, "Skip", 0, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.read(NewStreamReader(bytes.NewBuffer(tt.data)))
+
+			if tt.op == "" {
+				if err != tt.err {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+
+			var de *DecodeError
+
+			if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %s at offset %d: %v", err, tt.op, tt.offset, tt.err)
+			}
+		})
+	}
+}
+
+func TestStreamReaderErrorOp(t *testing.T) {
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		s := NewStreamReaderWithEndianness(bytes.NewReader([]byte{1}), e)
+
+		_, err := s.ReadUint16()
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != "ReadUint16" || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("%v: ReadUint16 error = %v, want Op ReadUint16", e, err)
+		}
+	}
+}
+
+func TestStreamReaderSkip(t *testing.T) {
+
+	data := []byte{1, 2, 3, 4, 5}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+
+	go func() {
+		pw.Write(data)
+		pw.Close()
+	}()
+
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{"reader", bytes.NewBuffer(data)},
+		{"seeker", bytes.NewReader(data)},
+		{"pipe", pr}, // *os.File implements io.Seeker, but a pipe can not seek
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := NewStreamReader(tt.r)
+
+			if err := s.Skip(3); err != nil {
+				t.Fatalf("Skip: %s", err)
+			}
+
+			if s.Offset() != 3 {
+				t.Fatalf("Offset = %d, want 3", s.Offset())
+			}
+
+			if v, err := s.ReadUint8(); err != nil || v != 4 {
+				t.Fatalf("ReadUint8 after Skip = %d, %v, want 4", v, err)
+			}
+		})
+	}
+}
+
+func TestStreamFloat(t *testing.T) {
+
+	nan64 := math.Float64frombits(0x7ff4000000000001)
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		var w bytes.Buffer
+
+		sw := NewStreamWriter(&w)
+		sw.SetEndianness(e)
+		sw.WriteFloat32(-0.5)
+		sw.WriteFloat64(nan64)
+		sw.WriteComplex64(complex(1, 2))
+		sw.WriteComplex128(complex(1.5, -2.5))
+
+		if err := sw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The stream and the Buffer encodings are the same.
+		b := NewBufferWithEndianness(nil, e)
+		b.WriteFloat32(-0.5)
+		b.WriteFloat64(nan64)
+		b.WriteComplex64(complex(1, 2))
+		b.WriteComplex128(complex(1.5, -2.5))
+
+		if !bytes.Equal(w.Bytes(), b.Bytes()) {
+			t.Fatalf("%v: StreamWriter wrote % x, Buffer % x", e, w.Bytes(), b.Bytes())
+		}
+
+		s := NewStreamReaderWithEndianness(&w, e)
+
+		if v, err := s.ReadFloat32(); err != nil || v != -0.5 {
+			t.Fatalf("%v: ReadFloat32 = %v, %v", e, v, err)
+		}
+
+		if v, err := s.ReadFloat64(); err != nil || math.Float64bits(v) != 0x7ff4000000000001 {
+			t.Fatalf("%v: ReadFloat64 = %#x, %v", e, math.Float64bits(v), err)
+		}
+
+		if v, err := s.ReadComplex64(); err != nil || v != complex(1, 2) {
+			t.Fatalf("%v: ReadComplex64 = %v, %v", e, v, err)
+		}
+
+		if v, err := s.ReadComplex128(); err != nil || v != complex(1.5, -2.5) {
+			t.Fatalf("%v: ReadComplex128 = %v, %v", e, v, err)
+		}
+	}
+
+	s := NewStreamReader(bytes.NewReader(make([]byte, 15)))
+
+	var de *DecodeError
+
+	if _, err := s.ReadComplex128(); !errors.As(err, &de) || de.Op != "ReadComplex128" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadComplex128 error = %v, want Op ReadComplex128", err)
+	}
+}
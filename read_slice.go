@@ -1,6 +1,9 @@
 package bytebuilder
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // ReadByte removes the first byte from b and returns it as a byte.
 // The bool indicates whether the read was successful.
@@ -16,6 +19,17 @@ func ReadByte(b *[]byte) (byte, bool) {
 	return v, true
 }
 
+// ReadByteE is like ReadByte, but returns a *DecodeError if the read failed.
+func ReadByteE(b *[]byte) (byte, error) {
+
+	v, err := readBytesE(b, 1, "ReadByte")
+	if err != nil {
+		return 0, err
+	}
+
+	return v[0], nil
+}
+
 // ReadBytes removes the first n bytes from b and returns it.
 // If the read failed, returns nil.
 func ReadBytes(b *[]byte, n int) []byte {
@@ -30,12 +44,44 @@ func ReadBytes(b *[]byte, n int) []byte {
 	return v
 }
 
+// ReadBytesE removes the first n bytes from b and returns it.
+// If the read failed, returns a *DecodeError.
+func ReadBytesE(b *[]byte, n int) ([]byte, error) {
+	return readBytesE(b, n, "ReadBytes")
+}
+
+// readBytesE removes the first n bytes from b and returns it.
+// op is the name of the operation used in the returned *DecodeError.
+func readBytesE(b *[]byte, n int, op string) ([]byte, error) {
+
+	if n < 0 {
+		return nil, &DecodeError{Op: op, Offset: -1, Want: n, Have: len(*b), Err: ErrInvalidLength}
+	}
+
+	if len(*b) < n {
+		return nil, &DecodeError{Op: op, Offset: -1, Want: n, Have: len(*b), Err: ErrShortBuffer}
+	}
+
+	v := (*b)[:n]
+	*b = (*b)[n:]
+
+	return v, nil
+}
+
 // Skip removes the first n bytes from b.
 // Returns whether it was successful.
 func Skip(b *[]byte, n int) bool {
 	return ReadBytes(b, n) != nil
 }
 
+// SkipE is like Skip, but returns a *DecodeError if it failed.
+func SkipE(b *[]byte, n int) error {
+
+	_, err := readBytesE(b, n, "Skip")
+
+	return err
+}
+
 // ReadUint8 removes the first byte from b and returns it as an uint8.
 // The bool indicates whether the read was successful.
 func ReadUint8(b *[]byte) (uint8, bool) {
@@ -48,6 +94,17 @@ func ReadUint8(b *[]byte) (uint8, bool) {
 	return uint8(v[0]), true
 }
 
+// ReadUint8E is like ReadUint8, but returns a *DecodeError if the read failed.
+func ReadUint8E(b *[]byte) (uint8, error) {
+
+	v, err := readBytesE(b, 1, "ReadUint8")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint8(v[0]), nil
+}
+
 // ReadInt8 removes the first byte from b and returns it as an int8.
 // The bool indicates whether the read was successful.
 func ReadInt8(b *[]byte) (int8, bool) {
@@ -60,6 +117,17 @@ func ReadInt8(b *[]byte) (int8, bool) {
 	return int8(v[0]), true
 }
 
+// ReadInt8E is like ReadInt8, but returns a *DecodeError if the read failed.
+func ReadInt8E(b *[]byte) (int8, error) {
+
+	v, err := readBytesE(b, 1, "ReadInt8")
+	if err != nil {
+		return 0, err
+	}
+
+	return int8(v[0]), nil
+}
+
 // ReadLittleUint16 removes the first bytes from b and returns it as an uint16 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleUint16(b *[]byte) (uint16, bool) {
@@ -72,6 +140,17 @@ func ReadLittleUint16(b *[]byte) (uint16, bool) {
 	return uint16(v[1])<<8 | uint16(v[0]), true
 }
 
+// ReadLittleUint16E is like ReadLittleUint16, but returns a *DecodeError if the read failed.
+func ReadLittleUint16E(b *[]byte) (uint16, error) {
+
+	v, err := readBytesE(b, 2, "ReadLittleUint16")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint16(v[1])<<8 | uint16(v[0]), nil
+}
+
 // Uint16BE removes the first bytes from b and returns it as an uint16 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigUint16(b *[]byte) (uint16, bool) {
@@ -84,6 +163,17 @@ func ReadBigUint16(b *[]byte) (uint16, bool) {
 	return uint16(v[0])<<8 | uint16(v[1]), true
 }
 
+// ReadBigUint16E is like ReadBigUint16, but returns a *DecodeError if the read failed.
+func ReadBigUint16E(b *[]byte) (uint16, error) {
+
+	v, err := readBytesE(b, 2, "ReadBigUint16")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint16(v[0])<<8 | uint16(v[1]), nil
+}
+
 // ReadUint16 removes the first bytes from b and returns it as an uint16 in native endian order.
 // The bool indicates whether the read was successful.
 func ReadUint16(b *[]byte) (uint16, bool) {
@@ -98,6 +188,19 @@ func ReadUint16(b *[]byte) (uint16, bool) {
 	}
 }
 
+// ReadUint16E is like ReadUint16, but returns a *DecodeError if the read failed.
+func ReadUint16E(b *[]byte) (uint16, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleUint16E(b)
+	case BigEndian:
+		return ReadBigUint16E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadLittleInt16 removes the first bytes from b and returns it as an int16 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleInt16(b *[]byte) (int16, bool) {
@@ -110,6 +213,17 @@ func ReadLittleInt16(b *[]byte) (int16, bool) {
 	return int16(v[1])<<8 | int16(v[0]), true
 }
 
+// ReadLittleInt16E is like ReadLittleInt16, but returns a *DecodeError if the read failed.
+func ReadLittleInt16E(b *[]byte) (int16, error) {
+
+	v, err := readBytesE(b, 2, "ReadLittleInt16")
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(v[1])<<8 | int16(v[0]), nil
+}
+
 // ReadBigInt16 removes the first bytes from b and returns it as an int16 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigInt16(b *[]byte) (int16, bool) {
@@ -122,6 +236,17 @@ func ReadBigInt16(b *[]byte) (int16, bool) {
 	return int16(v[0])<<8 | int16(v[1]), true
 }
 
+// ReadBigInt16E is like ReadBigInt16, but returns a *DecodeError if the read failed.
+func ReadBigInt16E(b *[]byte) (int16, error) {
+
+	v, err := readBytesE(b, 2, "ReadBigInt16")
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(v[0])<<8 | int16(v[1]), nil
+}
+
 // ReadInt16 removes the first bytes from b and returns it as an int16 in native endian order.
 // The bool indicates whether the read was successful.
 func ReadInt16(b *[]byte) (int16, bool) {
@@ -136,6 +261,19 @@ func ReadInt16(b *[]byte) (int16, bool) {
 	}
 }
 
+// ReadInt16E is like ReadInt16, but returns a *DecodeError if the read failed.
+func ReadInt16E(b *[]byte) (int16, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleInt16E(b)
+	case BigEndian:
+		return ReadBigInt16E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadLittleUint24 removes the first bytes from b and returns it as a uint32 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleUint24(b *[]byte) (uint32, bool) {
@@ -148,6 +286,17 @@ func ReadLittleUint24(b *[]byte) (uint32, bool) {
 	return uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), true
 }
 
+// ReadLittleUint24E is like ReadLittleUint24, but returns a *DecodeError if the read failed.
+func ReadLittleUint24E(b *[]byte) (uint32, error) {
+
+	v, err := readBytesE(b, 3, "ReadLittleUint24")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), nil
+}
+
 // ReadBigUint24 removes the first bytes from b and returns it as a uint32 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigUint24(b *[]byte) (uint32, bool) {
@@ -160,6 +309,17 @@ func ReadBigUint24(b *[]byte) (uint32, bool) {
 	return uint32(v[0])<<16 | uint32(v[1])<<8 | uint32(v[2]), true
 }
 
+// ReadBigUint24E is like ReadBigUint24, but returns a *DecodeError if the read failed.
+func ReadBigUint24E(b *[]byte) (uint32, error) {
+
+	v, err := readBytesE(b, 3, "ReadBigUint24")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[0])<<16 | uint32(v[1])<<8 | uint32(v[2]), nil
+}
+
 // ReadUint24 removes the first bytes from b and returns it as a uint32 in native endian order.
 // The bool indicates whether the read was successful.
 func ReadUint24(b *[]byte) (uint32, bool) {
@@ -174,6 +334,19 @@ func ReadUint24(b *[]byte) (uint32, bool) {
 	}
 }
 
+// ReadUint24E is like ReadUint24, but returns a *DecodeError if the read failed.
+func ReadUint24E(b *[]byte) (uint32, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleUint24E(b)
+	case BigEndian:
+		return ReadBigUint24E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadInt24 removes the first bytes from b and returns it as a int32 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleInt24(b *[]byte) (int32, bool) {
@@ -186,6 +359,17 @@ func ReadLittleInt24(b *[]byte) (int32, bool) {
 	return int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), true
 }
 
+// ReadLittleInt24E is like ReadLittleInt24, but returns a *DecodeError if the read failed.
+func ReadLittleInt24E(b *[]byte) (int32, error) {
+
+	v, err := readBytesE(b, 3, "ReadLittleInt24")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), nil
+}
+
 // ReadInt24 removes the first bytes from b and returns it as a int32 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigInt24(b *[]byte) (int32, bool) {
@@ -198,6 +382,17 @@ func ReadBigInt24(b *[]byte) (int32, bool) {
 	return int32(v[0])<<16 | int32(v[1])<<8 | int32(v[2]), true
 }
 
+// ReadBigInt24E is like ReadBigInt24, but returns a *DecodeError if the read failed.
+func ReadBigInt24E(b *[]byte) (int32, error) {
+
+	v, err := readBytesE(b, 3, "ReadBigInt24")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[0])<<16 | int32(v[1])<<8 | int32(v[2]), nil
+}
+
 // ReadInt24 removes the first bytes from b and returns it as a int32 in native endian order.
 // The bool indicates whether the read was successful.
 func ReadInt24(b *[]byte) (int32, bool) {
@@ -212,6 +407,19 @@ func ReadInt24(b *[]byte) (int32, bool) {
 	}
 }
 
+// ReadInt24E is like ReadInt24, but returns a *DecodeError if the read failed.
+func ReadInt24E(b *[]byte) (int32, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleInt24E(b)
+	case BigEndian:
+		return ReadBigInt24E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadLittleUint32 removes the first bytes from b and returns it as an uint32 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleUint32(b *[]byte) (uint32, bool) {
@@ -224,6 +432,17 @@ func ReadLittleUint32(b *[]byte) (uint32, bool) {
 	return uint32(v[3])<<24 | uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), true
 }
 
+// ReadLittleUint32E is like ReadLittleUint32, but returns a *DecodeError if the read failed.
+func ReadLittleUint32E(b *[]byte) (uint32, error) {
+
+	v, err := readBytesE(b, 4, "ReadLittleUint32")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[3])<<24 | uint32(v[2])<<16 | uint32(v[1])<<8 | uint32(v[0]), nil
+}
+
 // ReadBigUint32 removes the first bytes from b and returns it as an uint32 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigUint32(b *[]byte) (uint32, bool) {
@@ -236,6 +455,17 @@ func ReadBigUint32(b *[]byte) (uint32, bool) {
 	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), true
 }
 
+// ReadBigUint32E is like ReadBigUint32, but returns a *DecodeError if the read failed.
+func ReadBigUint32E(b *[]byte) (uint32, error) {
+
+	v, err := readBytesE(b, 4, "ReadBigUint32")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3]), nil
+}
+
 // ReadUint32 removes the first bytes from b and returns it as an uint32 in native-endian order.
 // The bool indicates whether the read was successful.
 func ReadUint32(b *[]byte) (uint32, bool) {
@@ -250,6 +480,19 @@ func ReadUint32(b *[]byte) (uint32, bool) {
 	}
 }
 
+// ReadUint32E is like ReadUint32, but returns a *DecodeError if the read failed.
+func ReadUint32E(b *[]byte) (uint32, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleUint32E(b)
+	case BigEndian:
+		return ReadBigUint32E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadLittleInt32 removes the first bytes from b and returns it as an int32 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleInt32(b *[]byte) (int32, bool) {
@@ -262,6 +505,17 @@ func ReadLittleInt32(b *[]byte) (int32, bool) {
 	return int32(v[3])<<24 | int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), true
 }
 
+// ReadLittleInt32E is like ReadLittleInt32, but returns a *DecodeError if the read failed.
+func ReadLittleInt32E(b *[]byte) (int32, error) {
+
+	v, err := readBytesE(b, 4, "ReadLittleInt32")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[3])<<24 | int32(v[2])<<16 | int32(v[1])<<8 | int32(v[0]), nil
+}
+
 // ReadBigInt32 removes the first bytes from b and returns it as an int32 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigInt32(b *[]byte) (int32, bool) {
@@ -274,6 +528,17 @@ func ReadBigInt32(b *[]byte) (int32, bool) {
 	return int32(v[0])<<24 | int32(v[1])<<16 | int32(v[2])<<8 | int32(v[3]), true
 }
 
+// ReadBigInt32E is like ReadBigInt32, but returns a *DecodeError if the read failed.
+func ReadBigInt32E(b *[]byte) (int32, error) {
+
+	v, err := readBytesE(b, 4, "ReadBigInt32")
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(v[0])<<24 | int32(v[1])<<16 | int32(v[2])<<8 | int32(v[3]), nil
+}
+
 // ReadInt32 removes the first bytes from b and returns it as an int32 in native-endian order.
 // The bool indicates whether the read was successful.
 func ReadInt32(b *[]byte) (int32, bool) {
@@ -288,6 +553,19 @@ func ReadInt32(b *[]byte) (int32, bool) {
 	}
 }
 
+// ReadInt32E is like ReadInt32, but returns a *DecodeError if the read failed.
+func ReadInt32E(b *[]byte) (int32, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleInt32E(b)
+	case BigEndian:
+		return ReadBigInt32E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadUint64 removes the first bytes from b and returns it as an uint64 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleUint64(b *[]byte) (uint64, bool) {
@@ -300,6 +578,17 @@ func ReadLittleUint64(b *[]byte) (uint64, bool) {
 	return uint64(v[7])<<56 | uint64(v[6])<<48 | uint64(v[5])<<40 | uint64(v[4])<<32 | uint64(v[3])<<24 | uint64(v[2])<<16 | uint64(v[1])<<8 | uint64(v[0]), true
 }
 
+// ReadLittleUint64E is like ReadLittleUint64, but returns a *DecodeError if the read failed.
+func ReadLittleUint64E(b *[]byte) (uint64, error) {
+
+	v, err := readBytesE(b, 8, "ReadLittleUint64")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(v[7])<<56 | uint64(v[6])<<48 | uint64(v[5])<<40 | uint64(v[4])<<32 | uint64(v[3])<<24 | uint64(v[2])<<16 | uint64(v[1])<<8 | uint64(v[0]), nil
+}
+
 // ReadUint64 removes the first bytes from b and returns it as an uint64 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigUint64(b *[]byte) (uint64, bool) {
@@ -312,6 +601,17 @@ func ReadBigUint64(b *[]byte) (uint64, bool) {
 	return uint64(v[0])<<56 | uint64(v[1])<<48 | uint64(v[2])<<40 | uint64(v[3])<<32 | uint64(v[4])<<24 | uint64(v[5])<<16 | uint64(v[6])<<8 | uint64(v[7]), true
 }
 
+// ReadBigUint64E is like ReadBigUint64, but returns a *DecodeError if the read failed.
+func ReadBigUint64E(b *[]byte) (uint64, error) {
+
+	v, err := readBytesE(b, 8, "ReadBigUint64")
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(v[0])<<56 | uint64(v[1])<<48 | uint64(v[2])<<40 | uint64(v[3])<<32 | uint64(v[4])<<24 | uint64(v[5])<<16 | uint64(v[6])<<8 | uint64(v[7]), nil
+}
+
 // ReadUint64 removes the first bytes from b and returns it as an uint64 in native-endian order.
 // The bool indicates whether the read was successful.
 func ReadUint64(b *[]byte) (uint64, bool) {
@@ -326,6 +626,19 @@ func ReadUint64(b *[]byte) (uint64, bool) {
 	}
 }
 
+// ReadUint64E is like ReadUint64, but returns a *DecodeError if the read failed.
+func ReadUint64E(b *[]byte) (uint64, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleUint64E(b)
+	case BigEndian:
+		return ReadBigUint64E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
 // ReadInt64 removes the first bytes from b and returns it as an int64 in little-endian order.
 // The bool indicates whether the read was successful.
 func ReadLittleInt64(b *[]byte) (int64, bool) {
@@ -338,6 +651,17 @@ func ReadLittleInt64(b *[]byte) (int64, bool) {
 	return int64(v[7])<<56 | int64(v[6])<<48 | int64(v[5])<<40 | int64(v[4])<<32 | int64(v[3])<<24 | int64(v[2])<<16 | int64(v[1])<<8 | int64(v[0]), true
 }
 
+// ReadLittleInt64E is like ReadLittleInt64, but returns a *DecodeError if the read failed.
+func ReadLittleInt64E(b *[]byte) (int64, error) {
+
+	v, err := readBytesE(b, 8, "ReadLittleInt64")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v[7])<<56 | int64(v[6])<<48 | int64(v[5])<<40 | int64(v[4])<<32 | int64(v[3])<<24 | int64(v[2])<<16 | int64(v[1])<<8 | int64(v[0]), nil
+}
+
 // ReadInt64 removes the first bytes from b and returns it as an int64 in big-endian order.
 // The bool indicates whether the read was successful.
 func ReadBigInt64(b *[]byte) (int64, bool) {
@@ -350,6 +674,17 @@ func ReadBigInt64(b *[]byte) (int64, bool) {
 	return int64(v[0])<<56 | int64(v[1])<<48 | int64(v[2])<<40 | int64(v[3])<<32 | int64(v[4])<<24 | int64(v[5])<<16 | int64(v[6])<<8 | int64(v[7]), true
 }
 
+// ReadBigInt64E is like ReadBigInt64, but returns a *DecodeError if the read failed.
+func ReadBigInt64E(b *[]byte) (int64, error) {
+
+	v, err := readBytesE(b, 8, "ReadBigInt64")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v[0])<<56 | int64(v[1])<<48 | int64(v[2])<<40 | int64(v[3])<<32 | int64(v[4])<<24 | int64(v[5])<<16 | int64(v[6])<<8 | int64(v[7]), nil
+}
+
 // ReadInt64 removes the first bytes from b and returns it as an int64 in native-endian order.
 // The bool indicates whether the read was successful.
 func ReadInt64(b *[]byte) (int64, bool) {
@@ -363,3 +698,308 @@ func ReadInt64(b *[]byte) (int64, bool) {
 		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
 	}
 }
+
+// ReadInt64E is like ReadInt64, but returns a *DecodeError if the read failed.
+func ReadInt64E(b *[]byte) (int64, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleInt64E(b)
+	case BigEndian:
+		return ReadBigInt64E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadLittleFloat32 removes the first bytes from b and returns it as a float32 in little-endian order.
+// The bool indicates whether the read was successful.
+func ReadLittleFloat32(b *[]byte) (float32, bool) {
+
+	v := ReadBytes(b, 4)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float32frombits(LittleEndian.Uint32(v)), true
+}
+
+// ReadLittleFloat32E is like ReadLittleFloat32, but returns a *DecodeError if the read failed.
+func ReadLittleFloat32E(b *[]byte) (float32, error) {
+
+	v, err := readBytesE(b, 4, "ReadLittleFloat32")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float32frombits(LittleEndian.Uint32(v)), nil
+}
+
+// ReadBigFloat32 removes the first bytes from b and returns it as a float32 in big-endian order.
+// The bool indicates whether the read was successful.
+func ReadBigFloat32(b *[]byte) (float32, bool) {
+
+	v := ReadBytes(b, 4)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float32frombits(BigEndian.Uint32(v)), true
+}
+
+// ReadBigFloat32E is like ReadBigFloat32, but returns a *DecodeError if the read failed.
+func ReadBigFloat32E(b *[]byte) (float32, error) {
+
+	v, err := readBytesE(b, 4, "ReadBigFloat32")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float32frombits(BigEndian.Uint32(v)), nil
+}
+
+// ReadFloat32 removes the first bytes from b and returns it as a float32 in native-endian order.
+// The bool indicates whether the read was successful.
+func ReadFloat32(b *[]byte) (float32, bool) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleFloat32(b)
+	case BigEndian:
+		return ReadBigFloat32(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadFloat32E is like ReadFloat32, but returns a *DecodeError if the read failed.
+func ReadFloat32E(b *[]byte) (float32, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleFloat32E(b)
+	case BigEndian:
+		return ReadBigFloat32E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadLittleFloat64 removes the first bytes from b and returns it as a float64 in little-endian order.
+// The bool indicates whether the read was successful.
+func ReadLittleFloat64(b *[]byte) (float64, bool) {
+
+	v := ReadBytes(b, 8)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float64frombits(LittleEndian.Uint64(v)), true
+}
+
+// ReadLittleFloat64E is like ReadLittleFloat64, but returns a *DecodeError if the read failed.
+func ReadLittleFloat64E(b *[]byte) (float64, error) {
+
+	v, err := readBytesE(b, 8, "ReadLittleFloat64")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(LittleEndian.Uint64(v)), nil
+}
+
+// ReadBigFloat64 removes the first bytes from b and returns it as a float64 in big-endian order.
+// The bool indicates whether the read was successful.
+func ReadBigFloat64(b *[]byte) (float64, bool) {
+
+	v := ReadBytes(b, 8)
+	if v == nil {
+		return 0, false
+	}
+
+	return math.Float64frombits(BigEndian.Uint64(v)), true
+}
+
+// ReadBigFloat64E is like ReadBigFloat64, but returns a *DecodeError if the read failed.
+func ReadBigFloat64E(b *[]byte) (float64, error) {
+
+	v, err := readBytesE(b, 8, "ReadBigFloat64")
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(BigEndian.Uint64(v)), nil
+}
+
+// ReadFloat64 removes the first bytes from b and returns it as a float64 in native-endian order.
+// The bool indicates whether the read was successful.
+func ReadFloat64(b *[]byte) (float64, bool) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleFloat64(b)
+	case BigEndian:
+		return ReadBigFloat64(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadFloat64E is like ReadFloat64, but returns a *DecodeError if the read failed.
+func ReadFloat64E(b *[]byte) (float64, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleFloat64E(b)
+	case BigEndian:
+		return ReadBigFloat64E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadLittleComplex64 removes the first bytes from b and returns it as a complex64 in little-endian order (real part first).
+// The bool indicates whether the read was successful.
+func ReadLittleComplex64(b *[]byte) (complex64, bool) {
+
+	v := ReadBytes(b, 8)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float32frombits(LittleEndian.Uint32(v)), math.Float32frombits(LittleEndian.Uint32(v[4:]))), true
+}
+
+// ReadLittleComplex64E is like ReadLittleComplex64, but returns a *DecodeError if the read failed.
+func ReadLittleComplex64E(b *[]byte) (complex64, error) {
+
+	v, err := readBytesE(b, 8, "ReadLittleComplex64")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float32frombits(LittleEndian.Uint32(v)), math.Float32frombits(LittleEndian.Uint32(v[4:]))), nil
+}
+
+// ReadBigComplex64 removes the first bytes from b and returns it as a complex64 in big-endian order (real part first).
+// The bool indicates whether the read was successful.
+func ReadBigComplex64(b *[]byte) (complex64, bool) {
+
+	v := ReadBytes(b, 8)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float32frombits(BigEndian.Uint32(v)), math.Float32frombits(BigEndian.Uint32(v[4:]))), true
+}
+
+// ReadBigComplex64E is like ReadBigComplex64, but returns a *DecodeError if the read failed.
+func ReadBigComplex64E(b *[]byte) (complex64, error) {
+
+	v, err := readBytesE(b, 8, "ReadBigComplex64")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float32frombits(BigEndian.Uint32(v)), math.Float32frombits(BigEndian.Uint32(v[4:]))), nil
+}
+
+// ReadComplex64 removes the first bytes from b and returns it as a complex64 in native-endian order (real part first).
+// The bool indicates whether the read was successful.
+func ReadComplex64(b *[]byte) (complex64, bool) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleComplex64(b)
+	case BigEndian:
+		return ReadBigComplex64(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadComplex64E is like ReadComplex64, but returns a *DecodeError if the read failed.
+func ReadComplex64E(b *[]byte) (complex64, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleComplex64E(b)
+	case BigEndian:
+		return ReadBigComplex64E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadLittleComplex128 removes the first bytes from b and returns it as a complex128 in little-endian order (real part first).
+// The bool indicates whether the read was successful.
+func ReadLittleComplex128(b *[]byte) (complex128, bool) {
+
+	v := ReadBytes(b, 16)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float64frombits(LittleEndian.Uint64(v)), math.Float64frombits(LittleEndian.Uint64(v[8:]))), true
+}
+
+// ReadLittleComplex128E is like ReadLittleComplex128, but returns a *DecodeError if the read failed.
+func ReadLittleComplex128E(b *[]byte) (complex128, error) {
+
+	v, err := readBytesE(b, 16, "ReadLittleComplex128")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float64frombits(LittleEndian.Uint64(v)), math.Float64frombits(LittleEndian.Uint64(v[8:]))), nil
+}
+
+// ReadBigComplex128 removes the first bytes from b and returns it as a complex128 in big-endian order (real part first).
+// The bool indicates whether the read was successful.
+func ReadBigComplex128(b *[]byte) (complex128, bool) {
+
+	v := ReadBytes(b, 16)
+	if v == nil {
+		return 0, false
+	}
+
+	return complex(math.Float64frombits(BigEndian.Uint64(v)), math.Float64frombits(BigEndian.Uint64(v[8:]))), true
+}
+
+// ReadBigComplex128E is like ReadBigComplex128, but returns a *DecodeError if the read failed.
+func ReadBigComplex128E(b *[]byte) (complex128, error) {
+
+	v, err := readBytesE(b, 16, "ReadBigComplex128")
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(math.Float64frombits(BigEndian.Uint64(v)), math.Float64frombits(BigEndian.Uint64(v[8:]))), nil
+}
+
+// ReadComplex128 removes the first bytes from b and returns it as a complex128 in native-endian order (real part first).
+// The bool indicates whether the read was successful.
+func ReadComplex128(b *[]byte) (complex128, bool) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleComplex128(b)
+	case BigEndian:
+		return ReadBigComplex128(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// ReadComplex128E is like ReadComplex128, but returns a *DecodeError if the read failed.
+func ReadComplex128E(b *[]byte) (complex128, error) {
+
+	switch NativeEndian {
+	case LittleEndian:
+		return ReadLittleComplex128E(b)
+	case BigEndian:
+		return ReadBigComplex128E(b)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
@@ -0,0 +1,789 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidUTF8 is returned when a string is not valid UTF-8 and the validation is enabled.
+	ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+	// ErrUnterminatedString is returned when the terminating NUL byte of a C string is missing.
+	ErrUnterminatedString = errors.New("unterminated string")
+
+	// ErrEmbeddedNUL is returned when a C string to write contains a NUL byte.
+	ErrEmbeddedNUL = errors.New("string contains a NUL byte")
+)
+
+// checkUTF8 returns a *DecodeError wrapping ErrInvalidUTF8 if v is not valid UTF-8.
+// off is the position of v, the offset of the error is the position of the first invalid byte.
+func checkUTF8(v []byte, off int, op string) error {
+
+	if utf8.Valid(v) {
+		return nil
+	}
+
+	i := 0
+
+	for i < len(v) {
+
+		r, n := utf8.DecodeRune(v[i:])
+		if r == utf8.RuneError && n == 1 {
+			break
+		}
+
+		i += n
+	}
+
+	return &DecodeError{Op: op, Offset: off + i, Err: ErrInvalidUTF8}
+}
+
+// trimPad removes the trailing pad bytes from v.
+func trimPad(v []byte, pad byte) []byte {
+
+	for len(v) > 0 && v[len(v)-1] == pad {
+		v = v[:len(v)-1]
+	}
+
+	return v
+}
+
+// fullRuneLen returns the length of the UTF-8 sequence started by c, 1 if c can not start a multi-byte sequence.
+func fullRuneLen(c byte) int {
+
+	switch {
+	case c >= 0xc2 && c <= 0xdf:
+		return 2
+	case c >= 0xe0 && c <= 0xef:
+		return 3
+	case c >= 0xf0 && c <= 0xf4:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// readSlice calls f with a Buffer created from b in native-endian order
+// and removes the bytes read by f from the start of b.
+// If validate is true, the strings read by f are validated.
+// If f fails, b is not modified and the offset of the returned *DecodeError is -1, like the other functions of the slice API.
+func readSlice(b *[]byte, validate bool, f func(buf *Buffer) error) error {
+
+	buf := Buffer{b: *b, little: NativeEndian == LittleEndian, utf8: validate}
+
+	if err := f(&buf); err != nil {
+
+		var de *DecodeError
+		if errors.As(err, &de) {
+			de.Offset = -1
+		}
+
+		return err
+	}
+
+	*b = (*b)[buf.off:]
+
+	return nil
+}
+
+// writeSlice calls f with a Buffer created from b in native-endian order and stores the extended slice in b.
+func writeSlice(b *[]byte, f func(buf *Buffer) error) error {
+
+	buf := Buffer{b: *b, little: NativeEndian == LittleEndian}
+
+	if err := f(&buf); err != nil {
+		return err
+	}
+
+	*b = buf.b
+
+	return nil
+}
+
+// ReadCString removes a NUL-terminated string from the start of b and returns it without the NUL byte.
+// If validate is true, the string must be valid UTF-8.
+// The bool indicates whether the read was successful.
+func ReadCString(b *[]byte, validate bool) (string, bool) {
+
+	v, err := ReadCStringE(b, validate)
+
+	return v, err == nil
+}
+
+// ReadCStringE is like ReadCString, but returns a *DecodeError if the read failed.
+func ReadCStringE(b *[]byte, validate bool) (string, error) {
+
+	var v string
+
+	err := readSlice(b, validate, func(buf *Buffer) (err error) {
+		v, err = buf.ReadCStringE()
+		return err
+	})
+
+	return v, err
+}
+
+// ReadFixedString removes a field of n bytes from the start of b and returns it without the trailing pad bytes.
+// If validate is true, the string must be valid UTF-8.
+// The bool indicates whether the read was successful.
+func ReadFixedString(b *[]byte, n int, pad byte, validate bool) (string, bool) {
+
+	v, err := ReadFixedStringE(b, n, pad, validate)
+
+	return v, err == nil
+}
+
+// ReadFixedStringE is like ReadFixedString, but returns a *DecodeError if the read failed.
+func ReadFixedStringE(b *[]byte, n int, pad byte, validate bool) (string, error) {
+
+	var v string
+
+	err := readSlice(b, validate, func(buf *Buffer) (err error) {
+		v, err = buf.ReadFixedStringE(n, pad)
+		return err
+	})
+
+	return v, err
+}
+
+// ReadVectorString removes a length-prefixed string from the start of b and returns it.
+// The length type is depend on bitSize (see Buffer.ReadVector) and it is read in native-endian order.
+// If validate is true, the string must be valid UTF-8.
+// If bitSize is an invalid number, this function panics.
+// The bool indicates whether the read was successful.
+func ReadVectorString(b *[]byte, bitSize int, validate bool) (string, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := ReadVectorStringE(b, bitSize, validate)
+
+	return v, err == nil
+}
+
+// ReadVectorStringE is like ReadVectorString, but returns a *DecodeError if the read failed.
+// An invalid bitSize is reported with ErrInvalidBitSize instead of panicking.
+func ReadVectorStringE(b *[]byte, bitSize int, validate bool) (string, error) {
+
+	var v string
+
+	err := readSlice(b, validate, func(buf *Buffer) (err error) {
+		v, err = buf.ReadVectorStringE(bitSize)
+		return err
+	})
+
+	return v, err
+}
+
+// ReadRune removes a UTF-8 encoded rune from the start of b and returns it and its size in bytes.
+// An invalid byte is returned as utf8.RuneError with size 1, unless validate is true.
+// The bool indicates whether the read was successful.
+func ReadRune(b *[]byte, validate bool) (rune, int, bool) {
+
+	r, n, err := ReadRuneE(b, validate)
+
+	return r, n, err == nil
+}
+
+// ReadRuneE is like ReadRune, but returns a *DecodeError if the read failed.
+func ReadRuneE(b *[]byte, validate bool) (rune, int, error) {
+
+	var (
+		r rune
+		n int
+	)
+
+	err := readSlice(b, validate, func(buf *Buffer) (err error) {
+
+		r, n, err = buf.ReadRune()
+		if err == io.EOF {
+			err = &DecodeError{Op: "ReadRune", Want: 1, Err: ErrShortBuffer}
+		}
+
+		return err
+	})
+
+	return r, n, err
+}
+
+// WriteCString appends s and a NUL byte at the end of b.
+// If s contains a NUL byte, s is truncated before it, use WriteCStringE to detect it.
+func WriteCString(b *[]byte, s string) {
+	writeSlice(b, func(buf *Buffer) error {
+		buf.WriteCString(s)
+		return nil
+	})
+}
+
+// WriteCStringE is like WriteCString, but returns an *EncodeError wrapping ErrEmbeddedNUL instead of truncating s.
+// If an error is returned, b is not modified.
+func WriteCStringE(b *[]byte, s string) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteCStringE(s)
+	})
+}
+
+// WriteFixedString appends s as a field of n bytes at the end of b, padded with pad.
+// If s is longer than n, it is truncated, use WriteFixedStringE to detect it.
+// If n is negative, this function panics.
+func WriteFixedString(b *[]byte, s string, n int, pad byte) {
+	writeSlice(b, func(buf *Buffer) error {
+		buf.WriteFixedString(s, n, pad)
+		return nil
+	})
+}
+
+// WriteFixedStringE is like WriteFixedString, but returns an *EncodeError instead of truncating s or panicking.
+// If an error is returned, b is not modified.
+func WriteFixedStringE(b *[]byte, s string, n int, pad byte) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteFixedStringE(s, n, pad)
+	})
+}
+
+// WriteVectorString appends the length of s then s itself at the end of b.
+// The length type is depend on bitSize (see Buffer.WriteVector) and it is written in native-endian order.
+// If bitSize is an invalid number, this function panics.
+// If len(s) does not fit into the length type, the length is truncated, use WriteVectorStringE to detect it.
+func WriteVectorString(b *[]byte, s string, bitSize int) {
+	writeSlice(b, func(buf *Buffer) error {
+		buf.WriteVectorString(s, bitSize)
+		return nil
+	})
+}
+
+// WriteVectorStringE is like WriteVectorString, but returns an *EncodeError instead of truncating the length or panicking.
+// If an error is returned, b is not modified.
+func WriteVectorStringE(b *[]byte, s string, bitSize int) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteVectorStringE(s, bitSize)
+	})
+}
+
+// WriteRune appends the UTF-8 encoding of r at the end of b.
+// If r is not a valid rune, the encoding of utf8.RuneError is appended.
+func WriteRune(b *[]byte, r rune) {
+	*b = utf8.AppendRune(*b, r)
+}
+
+// ValidateUTF8 returns whether the strings read from b are validated.
+func (b *Buffer) ValidateUTF8() bool {
+	return b.utf8
+}
+
+// SetValidateUTF8 sets whether the strings read from b must be valid UTF-8.
+// If it is enabled, the string reads (eg.: ReadCString, ReadVectorString, ReadRune)
+// return a *DecodeError wrapping ErrInvalidUTF8 on an invalid sequence and the read cursor is not advanced.
+// The mode is inherited by the child Buffers (eg.: ReadLengthPrefixed).
+func (b *Buffer) SetValidateUTF8(validate bool) {
+	b.utf8 = validate
+}
+
+// checkUTF8 validates v read at off if the validation is enabled.
+func (b *Buffer) checkUTF8(v []byte, off int, op string) error {
+
+	if !b.utf8 {
+		return nil
+	}
+
+	return checkUTF8(v, b.base+off, op)
+}
+
+// ReadCString reads a NUL-terminated string from b and returns it without the NUL byte.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadCString() (string, bool) {
+
+	v, err := b.ReadCStringE()
+
+	return v, err == nil
+}
+
+// ReadCStringE is like ReadCString, but returns a *DecodeError if the read failed.
+// If there is no NUL byte in the unread bytes, the error wraps ErrUnterminatedString.
+func (b *Buffer) ReadCStringE() (string, error) {
+
+	n := bytes.IndexByte(b.b[b.off:], 0)
+	if n < 0 {
+		return "", &DecodeError{Op: "ReadCString", Offset: b.base + b.off, Err: ErrUnterminatedString}
+	}
+
+	v := b.b[b.off : b.off+n]
+
+	if err := b.checkUTF8(v, b.off, "ReadCString"); err != nil {
+		return "", err
+	}
+
+	b.off += n + 1
+
+	return string(v), nil
+}
+
+// ReadFixedString reads a field of n bytes from b and returns it without the trailing pad bytes
+// (eg.: ' ' for space-padded or 0 for NUL-padded fields).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadFixedString(n int, pad byte) (string, bool) {
+
+	v, err := b.ReadFixedStringE(n, pad)
+
+	return v, err == nil
+}
+
+// ReadFixedStringE is like ReadFixedString, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadFixedStringE(n int, pad byte) (string, error) {
+
+	off := b.off
+
+	v, err := b.readE(n, "ReadFixedString")
+	if err != nil {
+		return "", err
+	}
+
+	v = trimPad(v, pad)
+
+	if err := b.checkUTF8(v, off, "ReadFixedString"); err != nil {
+		b.off = off
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// ReadVectorString reads the length of a string then the string itself (a Pascal string).
+// The length type is depend on bitSize (see ReadVector) and it is read in the byte order of b.
+// If bitSize is an invalid number, this function panics.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadVectorString(bitSize int) (string, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := b.ReadVectorStringE(bitSize)
+
+	return v, err == nil
+}
+
+// ReadVectorStringE is like ReadVectorString, but returns a *DecodeError if the read failed.
+// The errors are the same as of ReadVectorE.
+// If the read failed, the read cursor is not advanced.
+func (b *Buffer) ReadVectorStringE(bitSize int) (string, error) {
+
+	off := b.off
+
+	n, err := b.readLengthE(bitSize, "ReadVectorString")
+	if err != nil {
+		b.off = off
+		return "", err
+	}
+
+	v, err := b.readE(n, "ReadVectorString")
+	if err == nil {
+		err = b.checkUTF8(v, b.off-n, "ReadVectorString")
+	}
+
+	if err != nil {
+		b.off = off
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// ReadRune reads a UTF-8 encoded rune from b and returns it and its size in bytes.
+// An invalid byte is returned as utf8.RuneError with size 1,
+// or as a *DecodeError wrapping ErrInvalidUTF8 if the validation is enabled.
+// If b has no unread bytes, err is io.EOF.
+// ReadRune implements the io.RuneReader interface.
+func (b *Buffer) ReadRune() (r rune, size int, err error) {
+
+	if b.Empty() {
+		return 0, 0, io.EOF
+	}
+
+	if c := b.b[b.off]; c < utf8.RuneSelf {
+		b.off++
+		return rune(c), 1, nil
+	}
+
+	r, size = utf8.DecodeRune(b.b[b.off:])
+
+	if r == utf8.RuneError && size == 1 && b.utf8 {
+		return 0, 0, &DecodeError{Op: "ReadRune", Offset: b.base + b.off, Err: ErrInvalidUTF8}
+	}
+
+	b.off += size
+
+	return r, size, nil
+}
+
+// WriteCString appends s and a NUL byte at the end of b.
+// If s contains a NUL byte, s is truncated before it, use WriteCStringE to detect it.
+func (b *Buffer) WriteCString(s string) {
+
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+
+	b.b = append(b.b, s...)
+	b.b = append(b.b, 0)
+}
+
+// WriteCStringE is like WriteCString, but returns an *EncodeError wrapping ErrEmbeddedNUL instead of truncating s.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteCStringE(s string) error {
+
+	if strings.IndexByte(s, 0) >= 0 {
+		return &EncodeError{Op: "WriteCString", Length: len(s), Err: ErrEmbeddedNUL}
+	}
+
+	b.WriteCString(s)
+
+	return nil
+}
+
+// WriteFixedString appends s as a field of n bytes at the end of b, padded with pad
+// (eg.: ' ' for space-padded or 0 for NUL-padded fields).
+// If s is longer than n, it is truncated, use WriteFixedStringE to detect it.
+// If n is negative, this function panics.
+func (b *Buffer) WriteFixedString(s string, n int, pad byte) {
+
+	if len(s) > n {
+		s = s[:n]
+	}
+
+	b.b = append(b.b, s...)
+
+	for i := len(s); i < n; i++ {
+		b.b = append(b.b, pad)
+	}
+}
+
+// WriteFixedStringE is like WriteFixedString, but returns an *EncodeError instead of truncating s or panicking.
+// If s is longer than n, the error wraps ErrLengthOverflow.
+// If n is negative, the error wraps ErrInvalidLength.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteFixedStringE(s string, n int, pad byte) error {
+
+	switch {
+	case n < 0:
+		return &EncodeError{Op: "WriteFixedString", Length: n, Err: ErrInvalidLength}
+	case len(s) > n:
+		return &EncodeError{Op: "WriteFixedString", Length: len(s), Err: ErrLengthOverflow}
+	}
+
+	b.WriteFixedString(s, n, pad)
+
+	return nil
+}
+
+// WriteVectorString appends the length of s then s itself at the end of b (a Pascal string).
+// The length type is depend on bitSize (see WriteVector) and it is written in the byte order of b.
+// If bitSize is an invalid number, this function panics.
+// If len(s) does not fit into the length type, the length is truncated, use WriteVectorStringE to detect it.
+func (b *Buffer) WriteVectorString(s string, bitSize int) {
+
+	b.writeLength(len(s), bitSize)
+	b.b = append(b.b, s...)
+}
+
+// WriteVectorStringE is like WriteVectorString, but returns an *EncodeError instead of truncating the length or panicking.
+// The errors are the same as of WriteVectorE.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteVectorStringE(s string, bitSize int) error {
+
+	if err := checkVectorLength(len(s), bitSize); err != nil {
+		return &EncodeError{Op: "WriteVectorString", Length: len(s), Err: err}
+	}
+
+	b.WriteVectorString(s, bitSize)
+
+	return nil
+}
+
+// WriteRune appends the UTF-8 encoding of r at the end of b and returns its length.
+// If r is not a valid rune, the encoding of utf8.RuneError is appended.
+// The returned error is always nil.
+func (b *Buffer) WriteRune(r rune) (int, error) {
+
+	n := len(b.b)
+	b.b = utf8.AppendRune(b.b, r)
+
+	return len(b.b) - n, nil
+}
+
+// ReadCString reads a NUL-terminated string and returns it without the NUL byte.
+func (d *Decoder) ReadCString() string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadCStringE()
+	*d.err = err
+
+	return v
+}
+
+// ReadFixedString reads a field of n bytes and returns it without the trailing pad bytes.
+func (d *Decoder) ReadFixedString(n int, pad byte) string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadFixedStringE(n, pad)
+	*d.err = err
+
+	return v
+}
+
+// ReadVectorString reads the length of a string then the string itself.
+// The length type is depend on bitSize (see ReadVector).
+func (d *Decoder) ReadVectorString(bitSize int) string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadVectorStringE(bitSize)
+	*d.err = err
+
+	return v
+}
+
+// ValidateUTF8 returns whether the strings read from s are validated.
+func (s *StreamReader) ValidateUTF8() bool {
+	return s.utf8
+}
+
+// SetValidateUTF8 sets whether the strings read from s must be valid UTF-8.
+// If it is enabled, the string reads return a *DecodeError wrapping ErrInvalidUTF8 on an invalid sequence.
+func (s *StreamReader) SetValidateUTF8(validate bool) {
+	s.utf8 = validate
+}
+
+// checkUTF8 validates v read at off if the validation is enabled.
+func (s *StreamReader) checkUTF8(v []byte, off int64, op string) error {
+
+	if !s.utf8 {
+		return nil
+	}
+
+	return checkUTF8(v, int(off), op)
+}
+
+// ReadCString reads a NUL-terminated string from s and returns it without the NUL byte.
+// The string is read byte by byte, so s should wrap a buffered reader (eg.: bufio.Reader).
+// The length of the string is limited by MaxVectorLength.
+// If the end of the stream is reached before the NUL byte, io.ErrUnexpectedEOF is returned.
+func (s *StreamReader) ReadCString() (string, error) {
+
+	off := s.n
+
+	var v []byte
+
+	for {
+
+		c, err := s.ReadByte()
+		if err == io.EOF && s.n > off {
+			return "", &DecodeError{Op: "ReadCString", Offset: int(off), Have: len(v), Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if c == 0 {
+			break
+		}
+
+		if err := s.alloc.limits.checkVector(uint64(len(v) + 1)); err != nil {
+			return "", &DecodeError{Op: "ReadCString", Offset: int(off), Err: err}
+		}
+
+		v = append(v, c)
+	}
+
+	if err := s.alloc.alloc(len(v)); err != nil {
+		return "", &DecodeError{Op: "ReadCString", Offset: int(off), Err: err}
+	}
+
+	if err := s.checkUTF8(v, off, "ReadCString"); err != nil {
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// ReadFixedString reads a field of n bytes from s and returns it without the trailing pad bytes.
+func (s *StreamReader) ReadFixedString(n int, pad byte) (string, error) {
+
+	off := s.n
+
+	v, err := s.ReadBytes(n)
+	if err != nil {
+		return "", withOp(err, "ReadFixedString")
+	}
+
+	v = trimPad(v, pad)
+
+	if err := s.checkUTF8(v, off, "ReadFixedString"); err != nil {
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// ReadVectorString reads the length of a string then the string itself (a Pascal string).
+// The length type is depend on bitSize (see ReadVector) and it is read in the byte order of s.
+func (s *StreamReader) ReadVectorString(bitSize int) (string, error) {
+
+	v, err := s.ReadVector(bitSize)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkUTF8(v, s.n-int64(len(v)), "ReadVectorString"); err != nil {
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// ReadRune reads a UTF-8 encoded rune from s and returns it and its size in bytes.
+// The number of bytes read is determined by the first byte, because they can not be unread:
+// an invalid sequence is consumed as a whole and returned as utf8.RuneError with that size,
+// or as a *DecodeError wrapping ErrInvalidUTF8 if the validation is enabled.
+// ReadRune implements the io.RuneReader interface.
+func (s *StreamReader) ReadRune() (r rune, size int, err error) {
+
+	off := s.n
+
+	c, err := s.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if c < utf8.RuneSelf {
+		return rune(c), 1, nil
+	}
+
+	n := fullRuneLen(c)
+
+	v := s.buf[:n]
+	v[0] = c
+
+	if err := s.readFull(v[1:], "ReadRune"); err != nil {
+		// The error is reported at the start of the rune, not at its continuation bytes.
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = &DecodeError{Op: "ReadRune", Offset: int(off), Want: n, Have: int(s.n - off), Err: io.ErrUnexpectedEOF}
+		}
+		return 0, 0, err
+	}
+
+	r, size = utf8.DecodeRune(v)
+
+	if r == utf8.RuneError && size == 1 {
+
+		if s.utf8 {
+			return 0, 0, &DecodeError{Op: "ReadRune", Offset: int(off), Err: ErrInvalidUTF8}
+		}
+
+		return utf8.RuneError, n, nil
+	}
+
+	return r, size, nil
+}
+
+// WriteString writes the contents of str to s.
+// WriteString implements the io.StringWriter interface.
+func (s *StreamWriter) WriteString(str string) (int, error) {
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	s.buf = append(s.buf, str...)
+	s.advance(len(str))
+
+	return len(str), nil
+}
+
+// WriteCString writes str and a NUL byte to s.
+// If str contains a NUL byte, an *EncodeError wrapping ErrEmbeddedNUL is recorded.
+func (s *StreamWriter) WriteCString(str string) {
+
+	if s.err != nil {
+		return
+	}
+
+	if strings.IndexByte(str, 0) >= 0 {
+		s.err = &EncodeError{Op: "WriteCString", Length: len(str), Err: ErrEmbeddedNUL}
+		return
+	}
+
+	s.WriteString(str)
+	s.WriteByte(0)
+}
+
+// WriteFixedString writes str as a field of n bytes to s, padded with pad.
+// If str is longer than n or n is negative, an *EncodeError is recorded.
+func (s *StreamWriter) WriteFixedString(str string, n int, pad byte) {
+
+	if s.err != nil {
+		return
+	}
+
+	switch {
+	case n < 0:
+		s.err = &EncodeError{Op: "WriteFixedString", Length: n, Err: ErrInvalidLength}
+		return
+	case len(str) > n:
+		s.err = &EncodeError{Op: "WriteFixedString", Length: len(str), Err: ErrLengthOverflow}
+		return
+	}
+
+	s.WriteString(str)
+
+	for i := len(str); i < n; i++ {
+		s.WriteByte(pad)
+	}
+}
+
+// WriteVectorString writes the length of str then str itself to s (a Pascal string).
+// The length type is depend on bitSize (see WriteVector) and it is written in the byte order of s.
+// If bitSize is invalid or len(str) does not fit into the length type, an *EncodeError is recorded.
+func (s *StreamWriter) WriteVectorString(str string, bitSize int) {
+
+	if s.err != nil {
+		return
+	}
+
+	if err := checkVectorLength(len(str), bitSize); err != nil {
+		s.err = &EncodeError{Op: "WriteVectorString", Length: len(str), Err: err}
+		return
+	}
+
+	s.writeLength(len(str), bitSize)
+	s.WriteString(str)
+}
+
+// WriteRune writes the UTF-8 encoding of r to s and returns its length.
+// If r is not a valid rune, the encoding of utf8.RuneError is written.
+func (s *StreamWriter) WriteRune(r rune) (int, error) {
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	n := len(s.buf)
+	s.buf = utf8.AppendRune(s.buf, r)
+	n = len(s.buf) - n
+	s.advance(n)
+
+	return n, nil
+}
@@ -0,0 +1,434 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStringRoundTrip(t *testing.T) {
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		b := NewEmpty()
+		b.SetEndianness(e)
+
+		b.WriteCString("héllo")
+		b.WriteFixedString("ab", 4, ' ')
+		b.WriteFixedString("a\x00b", 5, 0)
+		b.WriteVectorString("vector", 16)
+		b.WriteVectorString("", VarintLength)
+		b.WriteRune('€')
+		b.WriteRune(utf8.MaxRune + 1)
+
+		b.SetValidateUTF8(true)
+
+		if v, ok := b.ReadCString(); !ok || v != "héllo" {
+			t.Fatalf("%v: ReadCString = %q, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadFixedString(4, ' '); !ok || v != "ab" {
+			t.Fatalf("%v: ReadFixedString = %q, %v", e, v, ok)
+		}
+
+		// Only the trailing pad bytes are removed, the embedded NUL is kept.
+		if v, ok := b.ReadFixedString(5, 0); !ok || v != "a\x00b" {
+			t.Fatalf("%v: ReadFixedString = %q, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadVectorString(16); !ok || v != "vector" {
+			t.Fatalf("%v: ReadVectorString = %q, %v", e, v, ok)
+		}
+
+		if v, ok := b.ReadVectorString(VarintLength); !ok || v != "" {
+			t.Fatalf("%v: ReadVectorString = %q, %v", e, v, ok)
+		}
+
+		if r, size, err := b.ReadRune(); err != nil || r != '€' || size != 3 {
+			t.Fatalf("%v: ReadRune = %q, %d, %v", e, r, size, err)
+		}
+
+		if r, size, err := b.ReadRune(); err != nil || r != utf8.RuneError || size != 3 {
+			t.Fatalf("%v: ReadRune = %q, %d, %v", e, r, size, err)
+		}
+
+		if _, _, err := b.ReadRune(); err != io.EOF {
+			t.Fatalf("%v: ReadRune at the end = %v, want io.EOF", e, err)
+		}
+	}
+}
+
+func TestReadStringErrors(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		data     []byte
+		validate bool
+		read     func(b *Buffer) error
+		op       string
+		offset   int
+		err      error
+	}{
+		{"CString/unterminated", []byte("abc"), false, readCString, "ReadCString", 1, ErrUnterminatedString},
+		{"CString/empty", nil, false, readCString, "ReadCString", 1, ErrUnterminatedString},
+		{"CString/invalid", []byte("a\xffb\x00"), true, readCString, "ReadCString", 2, ErrInvalidUTF8},
+		{"FixedString/short", []byte("ab"), false, readFixedString(5), "ReadFixedString", 1, ErrShortBuffer},
+		{"FixedString/invalid", []byte("ab\xc3 "), true, readFixedString(4), "ReadFixedString", 3, ErrInvalidUTF8},
+		{"FixedString/surrogate", []byte("\xed\xa0\x80"), true, readFixedString(3), "ReadFixedString", 1, ErrInvalidUTF8},
+		{"VectorString/short length", []byte{0x00}, false, readVectorString(16), "ReadUint16", 1, ErrShortBuffer},
+		{"VectorString/short", []byte{0x05, 'a'}, false, readVectorString(8), "ReadVectorString", 2, ErrShortBuffer},
+		{"VectorString/invalid", []byte{0x03, 'a', 'b', 0xff}, true, readVectorString(8), "ReadVectorString", 4, ErrInvalidUTF8},
+		{"Rune/invalid", []byte{0xff}, true, readRune, "ReadRune", 1, ErrInvalidUTF8},
+		{"Rune/truncated", []byte{0xe2, 0x82}, true, readRune, "ReadRune", 1, ErrInvalidUTF8},
+	}
+
+	for _, tt := range tests {
+
+		// The string is read from a child, so the offsets include its base.
+		data := append([]byte{byte(len(tt.data))}, tt.data...)
+
+		p := NewBuffer(data)
+		p.SetValidateUTF8(tt.validate)
+
+		var b Buffer
+
+		if err := p.ReadLengthPrefixed(8, &b); err != nil {
+			t.Fatalf("%s: ReadLengthPrefixed: %s", tt.name, err)
+		}
+
+		err := tt.read(&b)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+
+		if b.Offset() != 0 {
+			t.Fatalf("%s: read cursor advanced to %d", tt.name, b.Offset())
+		}
+
+		// The same input is accepted if the validation is disabled.
+		if tt.validate {
+
+			b.SetValidateUTF8(false)
+
+			if err := tt.read(&b); err != nil {
+				t.Fatalf("%s: without validation: %s", tt.name, err)
+			}
+		}
+	}
+}
+
+func readCString(b *Buffer) error {
+	_, err := b.ReadCStringE()
+	return err
+}
+
+func readFixedString(n int) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := b.ReadFixedStringE(n, ' ')
+		return err
+	}
+}
+
+func readVectorString(bitSize int) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := b.ReadVectorStringE(bitSize)
+		return err
+	}
+}
+
+func readRune(b *Buffer) error {
+	_, _, err := b.ReadRune()
+	return err
+}
+
+func TestWriteStringErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		write  func(b *Buffer) error
+		op     string
+		length int
+		err    error
+	}{
+		{"CString/embedded NUL", func(b *Buffer) error { return b.WriteCStringE("a\x00b") }, "WriteCString", 3, ErrEmbeddedNUL},
+		{"FixedString/overflow", func(b *Buffer) error { return b.WriteFixedStringE("abcde", 4, ' ') }, "WriteFixedString", 5, ErrLengthOverflow},
+		{"FixedString/negative", func(b *Buffer) error { return b.WriteFixedStringE("", -1, ' ') }, "WriteFixedString", -1, ErrInvalidLength},
+		{"VectorString/overflow", func(b *Buffer) error { return b.WriteVectorStringE(strings.Repeat("a", 256), 8) }, "WriteVectorString", 256, ErrLengthOverflow},
+		{"VectorString/bitSize", func(b *Buffer) error { return b.WriteVectorStringE("a", 12) }, "WriteVectorString", 1, ErrInvalidBitSize},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBuffer([]byte{0x01})
+
+		err := tt.write(&b)
+
+		var ee *EncodeError
+
+		if !errors.As(err, &ee) || ee.Op != tt.op || ee.Length != tt.length || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s with length %d: %v", tt.name, err, tt.op, tt.length, tt.err)
+		}
+
+		if !bytes.Equal(b.Bytes(), []byte{0x01}) {
+			t.Fatalf("%s: b modified to % x", tt.name, b.Bytes())
+		}
+	}
+
+	// The variants without error truncate.
+	b := NewEmpty()
+	b.WriteCString("a\x00b")
+	b.WriteFixedString("abcde", 4, ' ')
+
+	if want := []byte("a\x00abcd"); !bytes.Equal(b.Bytes(), want) {
+		t.Fatalf("truncated = % x, want % x", b.Bytes(), want)
+	}
+}
+
+func TestStringSlice(t *testing.T) {
+
+	var b []byte
+
+	WriteCString(&b, "abc")
+	WriteFixedString(&b, "de", 3, 0)
+	WriteVectorString(&b, "fg", QUICVarintLength)
+	WriteRune(&b, 'ő')
+
+	if err := WriteCStringE(&b, "\x00"); !errors.Is(err, ErrEmbeddedNUL) {
+		t.Fatalf("WriteCStringE = %v, want ErrEmbeddedNUL", err)
+	}
+
+	if v, ok := ReadCString(&b, true); !ok || v != "abc" {
+		t.Fatalf("ReadCString = %q, %v", v, ok)
+	}
+
+	if v, ok := ReadFixedString(&b, 3, 0, true); !ok || v != "de" {
+		t.Fatalf("ReadFixedString = %q, %v", v, ok)
+	}
+
+	if v, ok := ReadVectorString(&b, QUICVarintLength, true); !ok || v != "fg" {
+		t.Fatalf("ReadVectorString = %q, %v", v, ok)
+	}
+
+	if r, size, ok := ReadRune(&b, true); !ok || r != 'ő' || size != 2 {
+		t.Fatalf("ReadRune = %q, %d, %v", r, size, ok)
+	}
+
+	if len(b) != 0 {
+		t.Fatalf("%d bytes left", len(b))
+	}
+
+	// The failed reads do not modify b and report offset -1.
+	b = []byte{0x02, 'a', 0xff}
+
+	var de *DecodeError
+
+	if _, err := ReadVectorStringE(&b, 8, true); !errors.As(err, &de) || de.Offset != -1 || !errors.Is(err, ErrInvalidUTF8) {
+		t.Fatalf("ReadVectorStringE = %v, want ErrInvalidUTF8 at offset -1", err)
+	}
+
+	if len(b) != 3 {
+		t.Fatalf("b modified to % x", b)
+	}
+
+	if v, err := ReadVectorStringE(&b, 8, false); err != nil || v != "a\xff" {
+		t.Fatalf("ReadVectorStringE without validation = %q, %v", v, err)
+	}
+}
+
+func TestDecoderString(t *testing.T) {
+
+	b := NewBuffer([]byte("ab\x00cd  \x02ef"))
+	d := NewDecoder(&b)
+
+	if v := d.ReadCString(); v != "ab" {
+		t.Fatalf("ReadCString = %q", v)
+	}
+
+	if v := d.ReadFixedString(4, ' '); v != "cd" {
+		t.Fatalf("ReadFixedString = %q", v)
+	}
+
+	if v := d.ReadVectorString(8); v != "ef" {
+		t.Fatalf("ReadVectorString = %q", v)
+	}
+
+	if v := d.ReadCString(); v != "" || !errors.Is(d.Err(), ErrUnterminatedString) {
+		t.Fatalf("ReadCString = %q, %v, want ErrUnterminatedString", v, d.Err())
+	}
+
+	// The error is sticky.
+	if v := d.ReadFixedString(0, ' '); v != "" || !errors.Is(d.Err(), ErrUnterminatedString) {
+		t.Fatalf("ReadFixedString after error = %q, %v", v, d.Err())
+	}
+}
+
+func TestStreamString(t *testing.T) {
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		var w bytes.Buffer
+
+		s := NewStreamWriter(&w)
+		s.SetEndianness(e)
+		s.WriteCString("héllo")
+		s.WriteFixedString("ab", 4, ' ')
+		s.WriteVectorString("vector", 24)
+		s.WriteRune('€')
+
+		if err := s.Flush(); err != nil {
+			t.Fatalf("%v: Flush: %s", e, err)
+		}
+
+		// The stream encoding is the same as of Buffer.
+		b := NewEmpty()
+		b.SetEndianness(e)
+		b.WriteCString("héllo")
+		b.WriteFixedString("ab", 4, ' ')
+		b.WriteVectorString("vector", 24)
+		b.WriteRune('€')
+
+		if !bytes.Equal(w.Bytes(), b.Bytes()) {
+			t.Fatalf("%v: wrote % x, want % x", e, w.Bytes(), b.Bytes())
+		}
+
+		r := NewStreamReaderWithEndianness(&w, e)
+		r.SetValidateUTF8(true)
+
+		if v, err := r.ReadCString(); err != nil || v != "héllo" {
+			t.Fatalf("%v: ReadCString = %q, %v", e, v, err)
+		}
+
+		if v, err := r.ReadFixedString(4, ' '); err != nil || v != "ab" {
+			t.Fatalf("%v: ReadFixedString = %q, %v", e, v, err)
+		}
+
+		if v, err := r.ReadVectorString(24); err != nil || v != "vector" {
+			t.Fatalf("%v: ReadVectorString = %q, %v", e, v, err)
+		}
+
+		if v, size, err := r.ReadRune(); err != nil || v != '€' || size != 3 {
+			t.Fatalf("%v: ReadRune = %q, %d, %v", e, v, size, err)
+		}
+
+		if _, _, err := r.ReadRune(); err != io.EOF {
+			t.Fatalf("%v: ReadRune at the end = %v, want io.EOF", e, err)
+		}
+	}
+}
+
+func TestStreamReadStringErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		limits Limits
+		read   func(s *StreamReader) error
+		op     string
+		offset int
+		err    error
+	}{
+		{"CString/unterminated", []byte("xabc"), Limits{}, streamCString, "ReadCString", 1, io.ErrUnexpectedEOF},
+		{"CString/limit", []byte("xabc\x00"), Limits{MaxVectorLength: 2}, streamCString, "ReadCString", 1, ErrLimitExceeded},
+		{"CString/invalid", []byte("xa\xff\x00"), Limits{}, streamCString, "ReadCString", 2, ErrInvalidUTF8},
+		{"FixedString/invalid", []byte("xab\xc3 "), Limits{}, streamFixedString(4), "ReadFixedString", 3, ErrInvalidUTF8},
+		{"FixedString/short", []byte("xab"), Limits{}, streamFixedString(4), "ReadFixedString", 1, io.ErrUnexpectedEOF},
+		{"VectorString/invalid", []byte{'x', 0x03, 'a', 'b', 0xff}, Limits{}, streamVectorString(8), "ReadVectorString", 4, ErrInvalidUTF8},
+		{"Rune/invalid", []byte{'x', 0xe2, 0x28, 0xa1}, Limits{}, streamRune, "ReadRune", 1, ErrInvalidUTF8},
+		{"Rune/truncated", []byte{'x', 0xe2, 0x82}, Limits{}, streamRune, "ReadRune", 1, io.ErrUnexpectedEOF},
+		{"Rune/lead byte only", []byte{'x', 0xe2}, Limits{}, streamRune, "ReadRune", 1, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+
+		s := NewStreamReader(bytes.NewReader(tt.data))
+		s.SetLimits(tt.limits)
+		s.SetValidateUTF8(true)
+
+		if _, err := s.ReadUint8(); err != nil {
+			t.Fatalf("%s: ReadUint8: %s", tt.name, err)
+		}
+
+		err := tt.read(s)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+	}
+
+	// An invalid sequence is consumed as a whole without validation.
+	s := NewStreamReader(bytes.NewReader([]byte{0xe2, 0x28, 0xa1, 'a'}))
+
+	if r, size, err := s.ReadRune(); err != nil || r != utf8.RuneError || size != 3 {
+		t.Fatalf("ReadRune = %q, %d, %v", r, size, err)
+	}
+
+	if r, _, err := s.ReadRune(); err != nil || r != 'a' {
+		t.Fatalf("ReadRune after invalid = %q, %v", r, err)
+	}
+}
+
+func streamCString(s *StreamReader) error {
+	_, err := s.ReadCString()
+	return err
+}
+
+func streamFixedString(n int) func(s *StreamReader) error {
+	return func(s *StreamReader) error {
+		_, err := s.ReadFixedString(n, ' ')
+		return err
+	}
+}
+
+func streamVectorString(bitSize int) func(s *StreamReader) error {
+	return func(s *StreamReader) error {
+		_, err := s.ReadVectorString(bitSize)
+		return err
+	}
+}
+
+func streamRune(s *StreamReader) error {
+	_, _, err := s.ReadRune()
+	return err
+}
+
+func TestStreamWriteStringErrors(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		write func(s *StreamWriter)
+		op    string
+		err   error
+	}{
+		{"CString/embedded NUL", func(s *StreamWriter) { s.WriteCString("a\x00") }, "WriteCString", ErrEmbeddedNUL},
+		{"FixedString/overflow", func(s *StreamWriter) { s.WriteFixedString("abc", 2, ' ') }, "WriteFixedString", ErrLengthOverflow},
+		{"FixedString/negative", func(s *StreamWriter) { s.WriteFixedString("", -1, ' ') }, "WriteFixedString", ErrInvalidLength},
+		{"VectorString/overflow", func(s *StreamWriter) { s.WriteVectorString(strings.Repeat("a", 256), 8) }, "WriteVectorString", ErrLengthOverflow},
+	}
+
+	for _, tt := range tests {
+
+		var w bytes.Buffer
+
+		s := NewStreamWriter(&w)
+		tt.write(s)
+		s.WriteCString("ok")
+
+		var ee *EncodeError
+
+		if err := s.Flush(); !errors.As(err, &ee) || ee.Op != tt.op || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s: %v", tt.name, err, tt.op, tt.err)
+		}
+
+		if w.Len() != 0 {
+			t.Fatalf("%s: wrote % x after the error", tt.name, w.Bytes())
+		}
+	}
+}
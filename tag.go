@@ -0,0 +1,141 @@
+package bytebuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TagOrder is the byte order option of a struct tag.
+type TagOrder byte
+
+const (
+	OrderDefault TagOrder = iota // The byte order of the Buffer
+	OrderBig                     // The "be" option
+	OrderLittle                  // The "le" option
+)
+
+// FieldTag is the parsed form of a `bb:"..."` struct tag used by Marshal, Unmarshal and cmd/bytebuildergen.
+//
+// The tag is a comma separated list of options:
+//
+//	"-"                skip the field
+//	uint8 ... uint64   encode an integer with the given width (also int8 ... int64, uint24 and int24)
+//	varint             encode an integer as a LEB128 varint (zigzag for signed types)
+//	quicvarint         encode an unsigned integer as a QUIC variable-length integer
+//	be, le             byte order of the field, the default is the byte order of the Buffer
+//	vector,N           length-prefixed slice or string, N is 8/16/24/32/64, varint or quicvarint
+//	fixed,N            fixed number of elements (bytes for strings)
+//
+// The width, varint and byte order options of a slice or array apply to its elements.
+type FieldTag struct {
+	Skip   bool
+	Size   int      // Width of integers in bytes, 0 for the size of the Go type
+	Varint int      // 0, VarintLength or QUICVarintLength
+	Order  TagOrder // Byte order of the field
+	Vector int      // bitSize of the vector length, 0 if not a vector
+	Fixed  int      // Fixed length, -1 if not fixed
+}
+
+// ParseFieldTag parses the value of a bb struct tag.
+func ParseFieldTag(s string) (FieldTag, error) {
+
+	t := FieldTag{Fixed: -1}
+
+	if s == "" {
+		return t, nil
+	}
+
+	if s == "-" {
+		t.Skip = true
+		return t, nil
+	}
+
+	opts := strings.Split(s, ",")
+	hasLen := false
+
+	for i := 0; i < len(opts); i++ {
+
+		opt := strings.TrimSpace(opts[i])
+
+		switch opt {
+		case "uint8", "int8":
+			t.Size = 1
+		case "uint16", "int16":
+			t.Size = 2
+		case "uint24", "int24":
+			t.Size = 3
+		case "uint32", "int32":
+			t.Size = 4
+		case "uint64", "int64":
+			t.Size = 8
+		case "varint":
+			t.Varint = VarintLength
+		case "quicvarint":
+			t.Varint = QUICVarintLength
+		case "be":
+			t.Order = OrderBig
+		case "le":
+			t.Order = OrderLittle
+		case "vector", "fixed":
+
+			if hasLen {
+				return t, fmt.Errorf("invalid tag %q: multiple vector or fixed options", s)
+			}
+
+			if i+1 >= len(opts) {
+				return t, fmt.Errorf("invalid tag %q: missing length after %s", s, opt)
+			}
+
+			i++
+			arg := strings.TrimSpace(opts[i])
+			hasLen = true
+
+			if opt == "fixed" {
+				n, err := strconv.Atoi(arg)
+				if err != nil || n < 0 {
+					return t, fmt.Errorf("invalid tag %q: invalid fixed length %q", s, arg)
+				}
+				t.Fixed = n
+				continue
+			}
+
+			switch arg {
+			case "8", "16", "24", "32", "64":
+				t.Vector, _ = strconv.Atoi(arg)
+			case "varint":
+				t.Vector = VarintLength
+			case "quicvarint":
+				t.Vector = QUICVarintLength
+			default:
+				return t, fmt.Errorf("invalid tag %q: invalid vector size %q", s, arg)
+			}
+		default:
+			return t, fmt.Errorf("invalid tag %q: unknown option %q", s, opt)
+		}
+	}
+
+	return t, nil
+}
+
+// ElemTag returns the tag that applies to the elements of a slice or array.
+func (t FieldTag) ElemTag() FieldTag {
+
+	t.Vector = 0
+	t.Fixed = -1
+
+	return t
+}
+
+// little returns whether the field uses little-endian byte order in b.
+func (t FieldTag) little(b *Buffer) bool {
+
+	switch t.Order {
+	case OrderBig:
+		return false
+	case OrderLittle:
+		return true
+	default:
+		return b.little
+	}
+}
@@ -0,0 +1,432 @@
+package bytebuilder
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInvalidTime is returned when an encoded time has an invalid field (eg.: month 13 in a DOS date).
+var ErrInvalidTime = errors.New("invalid time")
+
+const (
+	// ntpEpoch is the start of the NTP era 0 (1900-01-01) in Unix seconds.
+	ntpEpoch = -2208988800
+
+	// fileTimeEpoch is the start of FILETIME (1601-01-01) in Unix seconds.
+	fileTimeEpoch = -11644473600
+
+	// tai64Label is the TAI64 label of the Unix epoch (1970-01-01 00:00:10 TAI).
+	tai64Label = 1<<62 + 10
+
+	// maxUnix is the largest number of seconds since the Unix epoch that time.Time can hold,
+	// it counts the seconds from the year 1 in an int64.
+	maxUnix = math.MaxInt64 - 62135596800
+)
+
+// The times are read and written in the byte order of b (except TAI64N, which is always big-endian)
+// and the times read are in UTC.
+//
+// Writing a time that is out of the range of the format returns an *EncodeError wrapping ErrValueOverflow.
+// Precision finer than the format is truncated.
+
+// readUnix reads a signed 64-bit number of units of d nanoseconds since the Unix epoch.
+// A value that time.Time can not hold is reported with ErrValueOverflow.
+func (b *Buffer) readUnix(d int64, op string) (time.Time, error) {
+
+	off := b.off
+
+	v, err := b.readE(8, op)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	n := int64(b.Endianness().Uint64(v))
+	sec, nsec := n/(1e9/d), n%(1e9/d)*d
+
+	if sec > maxUnix {
+		b.off = off
+		return time.Time{}, &DecodeError{Op: op, Offset: b.base + off, Err: ErrValueOverflow}
+	}
+
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+// timeError returns an *EncodeError wrapping ErrValueOverflow for a time that can not be encoded by op.
+func timeError(op string) error {
+	return &EncodeError{Op: op, Err: ErrValueOverflow}
+}
+
+// WriteGMTUnixTime32E is like WriteGMTUnixTime32, but returns an *EncodeError
+// if t is before 1970 or after 2106-02-07 06:28:15 UTC instead of truncating it.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteGMTUnixTime32E(t time.Time) error {
+
+	if v := t.Unix(); v < 0 || v > math.MaxUint32 {
+		return timeError("WriteGMTUnixTime32")
+	}
+
+	b.WriteGMTUnixTime32(t)
+
+	return nil
+}
+
+// ReadUnixTime64 reads a signed 64-bit number of seconds since the Unix epoch.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUnixTime64() (time.Time, bool) {
+
+	v, err := b.ReadUnixTime64E()
+
+	return v, err == nil
+}
+
+// ReadUnixTime64E is like ReadUnixTime64, but returns a *DecodeError if the read failed.
+// A value out of the range of time.Time is reported with ErrValueOverflow.
+func (b *Buffer) ReadUnixTime64E() (time.Time, error) {
+	return b.readUnix(1e9, "ReadUnixTime64")
+}
+
+// WriteUnixTime64 appends t to b as a signed 64-bit number of seconds since the Unix epoch.
+func (b *Buffer) WriteUnixTime64(t time.Time) {
+	b.WriteInt64(t.Unix())
+}
+
+// ReadUnixMilli64 reads a signed 64-bit number of milliseconds since the Unix epoch.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUnixMilli64() (time.Time, bool) {
+
+	v, err := b.ReadUnixMilli64E()
+
+	return v, err == nil
+}
+
+// ReadUnixMilli64E is like ReadUnixMilli64, but returns a *DecodeError if the read failed.
+// A value out of the range of time.Time is reported with ErrValueOverflow.
+func (b *Buffer) ReadUnixMilli64E() (time.Time, error) {
+	return b.readUnix(1e6, "ReadUnixMilli64")
+}
+
+// WriteUnixMilli64E appends t to b as a signed 64-bit number of milliseconds since the Unix epoch.
+// Returns an *EncodeError if t is out of range (about 292 million years around 1970).
+func (b *Buffer) WriteUnixMilli64E(t time.Time) error {
+
+	v, ok := unixSub(t, 1e6)
+	if !ok {
+		return timeError("WriteUnixMilli64")
+	}
+
+	b.WriteInt64(v)
+
+	return nil
+}
+
+// ReadUnixMicro64 reads a signed 64-bit number of microseconds since the Unix epoch.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUnixMicro64() (time.Time, bool) {
+
+	v, err := b.ReadUnixMicro64E()
+
+	return v, err == nil
+}
+
+// ReadUnixMicro64E is like ReadUnixMicro64, but returns a *DecodeError if the read failed.
+// A value out of the range of time.Time is reported with ErrValueOverflow.
+func (b *Buffer) ReadUnixMicro64E() (time.Time, error) {
+	return b.readUnix(1e3, "ReadUnixMicro64")
+}
+
+// WriteUnixMicro64E appends t to b as a signed 64-bit number of microseconds since the Unix epoch.
+// Returns an *EncodeError if t is out of range (about 292 thousand years around 1970).
+func (b *Buffer) WriteUnixMicro64E(t time.Time) error {
+
+	v, ok := unixSub(t, 1e3)
+	if !ok {
+		return timeError("WriteUnixMicro64")
+	}
+
+	b.WriteInt64(v)
+
+	return nil
+}
+
+// ReadUnixNano64 reads a signed 64-bit number of nanoseconds since the Unix epoch.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUnixNano64() (time.Time, bool) {
+
+	v, err := b.ReadUnixNano64E()
+
+	return v, err == nil
+}
+
+// ReadUnixNano64E is like ReadUnixNano64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUnixNano64E() (time.Time, error) {
+
+	v, err := b.ReadInt64E()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, v).UTC(), nil
+}
+
+// WriteUnixNano64E appends t to b as a signed 64-bit number of nanoseconds since the Unix epoch.
+// Returns an *EncodeError if t is before 1677-09-21 or after 2262-04-11.
+func (b *Buffer) WriteUnixNano64E(t time.Time) error {
+
+	v, ok := unixSub(t, 1)
+	if !ok {
+		return timeError("WriteUnixNano64")
+	}
+
+	b.WriteInt64(v)
+
+	return nil
+}
+
+// unixSub returns t as the number of units of d nanoseconds since the Unix epoch, rounded towards the past.
+// The bool is false if the result does not fit into an int64.
+func unixSub(t time.Time, d int64) (int64, bool) {
+
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	n := int64(1e9) / d
+
+	if sec > (math.MaxInt64-nsec/d)/n || sec < math.MinInt64/n {
+		return 0, false
+	}
+
+	return sec*n + nsec/d, true
+}
+
+// ReadNTPTime64 reads an NTP 64-bit timestamp: 32-bit seconds since 1900-01-01 and a 32-bit fraction of a second.
+// The timestamp is interpreted in era 0 (1900 to 2036).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadNTPTime64() (time.Time, bool) {
+
+	v, err := b.ReadNTPTime64E()
+
+	return v, err == nil
+}
+
+// ReadNTPTime64E is like ReadNTPTime64, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadNTPTime64E() (time.Time, error) {
+
+	v, err := b.readE(8, "ReadNTPTime64")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	sec, frac := b.Endianness().Uint32(v), b.Endianness().Uint32(v[4:])
+
+	return time.Unix(int64(sec)+ntpEpoch, int64((uint64(frac)*1e9)>>32)).UTC(), nil
+}
+
+// WriteNTPTime64E appends t to b as an NTP 64-bit timestamp: 32-bit seconds since 1900-01-01 and a 32-bit fraction of a second.
+// Returns an *EncodeError if t is out of era 0 (before 1900 or after 2036-02-07 06:28:15 UTC).
+func (b *Buffer) WriteNTPTime64E(t time.Time) error {
+
+	sec := t.Unix() - ntpEpoch
+	if sec < 0 || sec > math.MaxUint32 {
+		return timeError("WriteNTPTime64")
+	}
+
+	// Rounded up, so reading it back gives the same nanoseconds.
+	frac := (uint64(t.Nanosecond())<<32 + 1e9 - 1) / 1e9
+
+	b.WriteUint32(uint32(sec))
+	b.WriteUint32(uint32(frac))
+
+	return nil
+}
+
+// ReadFileTime reads a Windows FILETIME: an unsigned 64-bit number of 100-nanosecond intervals since 1601-01-01.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadFileTime() (time.Time, bool) {
+
+	v, err := b.ReadFileTimeE()
+
+	return v, err == nil
+}
+
+// ReadFileTimeE is like ReadFileTime, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadFileTimeE() (time.Time, error) {
+
+	v, err := b.ReadUint64E()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(v/1e7)+fileTimeEpoch, int64(v%1e7)*100).UTC(), nil
+}
+
+// WriteFileTimeE appends t to b as a Windows FILETIME: an unsigned 64-bit number of 100-nanosecond intervals since 1601-01-01.
+// Returns an *EncodeError if t is before 1601 (or after the year 60056).
+func (b *Buffer) WriteFileTimeE(t time.Time) error {
+
+	sec := t.Unix() - fileTimeEpoch
+	if t.Unix() < fileTimeEpoch || uint64(sec) > (math.MaxUint64-(1e7-1))/uint64(1e7) {
+		return timeError("WriteFileTime")
+	}
+
+	b.WriteUint64(uint64(sec)*1e7 + uint64(t.Nanosecond()/100))
+
+	return nil
+}
+
+// ReadDOSDateTime reads an MS-DOS time then date (eg.: FAT, ZIP), each as an uint16.
+// The format has no time zone, so the fields are returned as a time in UTC.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadDOSDateTime() (time.Time, bool) {
+
+	v, err := b.ReadDOSDateTimeE()
+
+	return v, err == nil
+}
+
+// ReadDOSDateTimeE is like ReadDOSDateTime, but returns a *DecodeError if the read failed.
+// A field out of range (eg.: month 0) is reported with ErrInvalidTime.
+func (b *Buffer) ReadDOSDateTimeE() (time.Time, error) {
+
+	off := b.off
+
+	v, err := b.readE(4, "ReadDOSDateTime")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tm, dt := b.Endianness().Uint16(v), b.Endianness().Uint16(v[2:])
+
+	var (
+		year   = int(dt>>9) + 1980
+		month  = int(dt >> 5 & 0xf)
+		day    = int(dt & 0x1f)
+		hour   = int(tm >> 11)
+		minute = int(tm >> 5 & 0x3f)
+		second = int(tm&0x1f) * 2
+	)
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+
+	// time.Date normalizes the fields, so an invalid field changes them.
+	if t.Month() != time.Month(month) || t.Day() != day || t.Hour() != hour || t.Minute() != minute || t.Second() != second {
+		b.off = off
+		return time.Time{}, &DecodeError{Op: "ReadDOSDateTime", Offset: b.base + off, Err: ErrInvalidTime}
+	}
+
+	return t, nil
+}
+
+// WriteDOSDateTimeE appends t to b as an MS-DOS time then date (eg.: FAT, ZIP), each as an uint16.
+// The fields of t are written in its location, the seconds are rounded down to even.
+// Returns an *EncodeError if t is before 1980 or after 2107.
+func (b *Buffer) WriteDOSDateTimeE(t time.Time) error {
+
+	year, month, day := t.Date()
+	if year < 1980 || year > 1980+0x7f {
+		return timeError("WriteDOSDateTime")
+	}
+
+	b.WriteUint16(uint16(t.Hour()<<11 | t.Minute()<<5 | t.Second()/2))
+	b.WriteUint16(uint16((year-1980)<<9 | int(month)<<5 | day))
+
+	return nil
+}
+
+// ReadTAI64N reads a TAI64N label: a big-endian 64-bit TAI64 label and a big-endian 32-bit number of nanoseconds.
+// Leap seconds are ignored, like in the conventional mapping of UTC (1970-01-01 00:00:10 TAI is the Unix epoch).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadTAI64N() (time.Time, bool) {
+
+	v, err := b.ReadTAI64NE()
+
+	return v, err == nil
+}
+
+// ReadTAI64NE is like ReadTAI64N, but returns a *DecodeError if the read failed.
+// A reserved label (at least 2^63) or nanoseconds greater than 999999999 are reported with ErrInvalidTime.
+func (b *Buffer) ReadTAI64NE() (time.Time, error) {
+
+	off := b.off
+
+	v, err := b.readE(12, "ReadTAI64N")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	label, nsec := BigEndian.Uint64(v), BigEndian.Uint32(v[8:])
+
+	if label >= 1<<63 || nsec >= 1e9 {
+		b.off = off
+		return time.Time{}, &DecodeError{Op: "ReadTAI64N", Offset: b.base + off, Err: ErrInvalidTime}
+	}
+
+	return time.Unix(int64(label-tai64Label), int64(nsec)).UTC(), nil
+}
+
+// WriteTAI64NE appends t to b as a TAI64N label: a big-endian 64-bit TAI64 label and a big-endian 32-bit number of nanoseconds.
+// Leap seconds are ignored, like in the conventional mapping of UTC (1970-01-01 00:00:10 TAI is the Unix epoch).
+// Returns an *EncodeError if t is out of the range of TAI64 (about 146 billion years around 1970).
+func (b *Buffer) WriteTAI64NE(t time.Time) error {
+
+	sec := t.Unix()
+	if sec < -tai64Label || sec >= 1<<62-10 {
+		return timeError("WriteTAI64N")
+	}
+
+	b.b = BigEndian.AppendUint64(b.b, uint64(sec+tai64Label))
+	b.b = BigEndian.AppendUint32(b.b, uint32(t.Nanosecond()))
+
+	return nil
+}
+
+// ReadUnixTime64 reads a signed 64-bit number of seconds since the Unix epoch.
+func (d *Decoder) ReadUnixTime64() time.Time {
+	return d.readTime(d.b.ReadUnixTime64E)
+}
+
+// ReadUnixMilli64 reads a signed 64-bit number of milliseconds since the Unix epoch.
+func (d *Decoder) ReadUnixMilli64() time.Time {
+	return d.readTime(d.b.ReadUnixMilli64E)
+}
+
+// ReadUnixMicro64 reads a signed 64-bit number of microseconds since the Unix epoch.
+func (d *Decoder) ReadUnixMicro64() time.Time {
+	return d.readTime(d.b.ReadUnixMicro64E)
+}
+
+// ReadUnixNano64 reads a signed 64-bit number of nanoseconds since the Unix epoch.
+func (d *Decoder) ReadUnixNano64() time.Time {
+	return d.readTime(d.b.ReadUnixNano64E)
+}
+
+// ReadNTPTime64 reads an NTP 64-bit timestamp.
+func (d *Decoder) ReadNTPTime64() time.Time {
+	return d.readTime(d.b.ReadNTPTime64E)
+}
+
+// ReadFileTime reads a Windows FILETIME.
+func (d *Decoder) ReadFileTime() time.Time {
+	return d.readTime(d.b.ReadFileTimeE)
+}
+
+// ReadDOSDateTime reads an MS-DOS time then date.
+func (d *Decoder) ReadDOSDateTime() time.Time {
+	return d.readTime(d.b.ReadDOSDateTimeE)
+}
+
+// ReadTAI64N reads a TAI64N label.
+func (d *Decoder) ReadTAI64N() time.Time {
+	return d.readTime(d.b.ReadTAI64NE)
+}
+
+// readTime calls read if there was no error before and records its error.
+func (d *Decoder) readTime(read func() (time.Time, error)) time.Time {
+
+	if *d.err != nil {
+		return time.Time{}
+	}
+
+	v, err := read()
+	*d.err = err
+
+	return v
+}
@@ -0,0 +1,228 @@
+package bytebuilder
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeRoundTrip(t *testing.T) {
+
+	when := time.Date(2024, 2, 29, 12, 30, 44, 123456700, time.UTC)
+
+	tests := []struct {
+		name  string
+		write func(b *Buffer, t time.Time) error
+		read  func(b *Buffer) (time.Time, error)
+		trunc time.Duration
+	}{
+		{"UnixTime64", func(b *Buffer, t time.Time) error { b.WriteUnixTime64(t); return nil }, (*Buffer).ReadUnixTime64E, time.Second},
+		{"UnixMilli64", (*Buffer).WriteUnixMilli64E, (*Buffer).ReadUnixMilli64E, time.Millisecond},
+		{"UnixMicro64", (*Buffer).WriteUnixMicro64E, (*Buffer).ReadUnixMicro64E, time.Microsecond},
+		{"UnixNano64", (*Buffer).WriteUnixNano64E, (*Buffer).ReadUnixNano64E, 1},
+		{"NTPTime64", (*Buffer).WriteNTPTime64E, (*Buffer).ReadNTPTime64E, 1},
+		{"FileTime", (*Buffer).WriteFileTimeE, (*Buffer).ReadFileTimeE, 100},
+		{"DOSDateTime", (*Buffer).WriteDOSDateTimeE, (*Buffer).ReadDOSDateTimeE, 2 * time.Second},
+		{"TAI64N", (*Buffer).WriteTAI64NE, (*Buffer).ReadTAI64NE, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+				b := NewBufferWithEndianness(nil, e)
+
+				if err := tt.write(&b, when); err != nil {
+					t.Fatalf("%s: write: %s", e, err)
+				}
+
+				v, err := tt.read(&b)
+				if err != nil {
+					t.Fatalf("%s: read: %s", e, err)
+				}
+
+				if want := when.Truncate(tt.trunc); !v.Equal(want) {
+					t.Fatalf("%s: read = %s, want %s", e, v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+
+	b := NewEmpty()
+
+	if err := b.WriteNTPTime64E(time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrValueOverflow) {
+		t.Fatalf("WriteNTPTime64E error = %v, want %v", err, ErrValueOverflow)
+	}
+
+	if err := b.WriteUnixNano64E(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrValueOverflow) {
+		t.Fatalf("WriteUnixNano64E error = %v, want %v", err, ErrValueOverflow)
+	}
+
+	if !b.Empty() {
+		t.Fatalf("%d bytes written by failed writes", b.Remaining())
+	}
+
+	// time.Time can not hold the largest number of seconds.
+	b.WriteInt64(math.MaxInt64)
+
+	_, err := b.ReadUnixTime64E()
+
+	var de *DecodeError
+
+	if !errors.As(err, &de) || de.Op != "ReadUnixTime64" || !errors.Is(err, ErrValueOverflow) || b.Offset() != 0 {
+		t.Fatalf("ReadUnixTime64E error = %v, want %v", err, ErrValueOverflow)
+	}
+
+	if v, err := b.ReadUnixMilli64E(); err != nil || v.UnixMilli() != math.MaxInt64 {
+		t.Fatalf("ReadUnixMilli64E = %s, %v", v, err)
+	}
+}
+
+func TestTimeErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		read   func(b *Buffer) (time.Time, error)
+		op     string
+		offset int
+		err    error
+	}{
+		{"UnixTime64/short", []byte{0, 0, 0, 0}, (*Buffer).ReadUnixTime64E, "ReadUnixTime64", 1, ErrShortBuffer},
+		{"UnixTime64/overflow", []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, (*Buffer).ReadUnixTime64E, "ReadUnixTime64", 1, ErrValueOverflow},
+		{"UnixMilli64/short", []byte{0}, (*Buffer).ReadUnixMilli64E, "ReadUnixMilli64", 1, ErrShortBuffer},
+		{"UnixNano64/short", nil, (*Buffer).ReadUnixNano64E, "ReadInt64", 1, ErrShortBuffer},
+		{"NTPTime64/short", []byte{0, 0, 0, 0, 0, 0, 0}, (*Buffer).ReadNTPTime64E, "ReadNTPTime64", 1, ErrShortBuffer},
+		{"FileTime/short", []byte{0, 0}, (*Buffer).ReadFileTimeE, "ReadUint64", 1, ErrShortBuffer},
+		{"DOSDateTime/short", []byte{0, 0, 0}, (*Buffer).ReadDOSDateTimeE, "ReadDOSDateTime", 1, ErrShortBuffer},
+		{"DOSDateTime/month 0", []byte{0x00, 0x00, 0x00, 0x01}, (*Buffer).ReadDOSDateTimeE, "ReadDOSDateTime", 1, ErrInvalidTime},
+		{"DOSDateTime/day 0", []byte{0x00, 0x00, 0x00, 0x20}, (*Buffer).ReadDOSDateTimeE, "ReadDOSDateTime", 1, ErrInvalidTime},
+		{"DOSDateTime/February 30", []byte{0x00, 0x00, 0x00, 0x5e}, (*Buffer).ReadDOSDateTimeE, "ReadDOSDateTime", 1, ErrInvalidTime},
+		{"DOSDateTime/hour 24", []byte{0xc0, 0x00, 0x00, 0x21}, (*Buffer).ReadDOSDateTimeE, "ReadDOSDateTime", 1, ErrInvalidTime},
+		{"DOSDateTime/second 60", []byte{0x00, 0x1e, 0x00, 0x21}, (*Buffer).ReadDOSDateTimeE, "ReadDOSDateTime", 1, ErrInvalidTime},
+		{"TAI64N/short", make([]byte, 11), (*Buffer).ReadTAI64NE, "ReadTAI64N", 1, ErrShortBuffer},
+		{"TAI64N/reserved label", []byte{0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, (*Buffer).ReadTAI64NE, "ReadTAI64N", 1, ErrInvalidTime},
+		{"TAI64N/nanoseconds", []byte{0x40, 0, 0, 0, 0, 0, 0, 0x0a, 0x3b, 0x9a, 0xca, 0x00}, (*Buffer).ReadTAI64NE, "ReadTAI64N", 1, ErrInvalidTime},
+	}
+
+	for _, tt := range tests {
+
+		// The time is read from a child, so the offsets include its base.
+		p := NewBuffer(append([]byte{byte(len(tt.data))}, tt.data...))
+
+		var b Buffer
+
+		if err := p.ReadLengthPrefixed(8, &b); err != nil {
+			t.Fatalf("%s: ReadLengthPrefixed: %s", tt.name, err)
+		}
+
+		_, err := tt.read(&b)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+
+		if b.Offset() != 0 {
+			t.Fatalf("%s: read cursor advanced to %d", tt.name, b.Offset())
+		}
+	}
+}
+
+func TestWriteTimeErrors(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		write func(b *Buffer) error
+		op    string
+	}{
+		{"GMTUnixTime32/before 1970", func(b *Buffer) error { return b.WriteGMTUnixTime32E(time.Unix(-1, 0)) }, "WriteGMTUnixTime32"},
+		{"GMTUnixTime32/after 2106", func(b *Buffer) error { return b.WriteGMTUnixTime32E(time.Unix(math.MaxUint32+1, 0)) }, "WriteGMTUnixTime32"},
+		{"UnixMilli64", func(b *Buffer) error { return b.WriteUnixMilli64E(time.Unix(math.MaxInt64/1000+1, 0)) }, "WriteUnixMilli64"},
+		{"UnixMicro64", func(b *Buffer) error { return b.WriteUnixMicro64E(time.Unix(math.MinInt64/1000000-1, 0)) }, "WriteUnixMicro64"},
+		{"UnixNano64", func(b *Buffer) error { return b.WriteUnixNano64E(time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)) }, "WriteUnixNano64"},
+		{"NTPTime64", func(b *Buffer) error { return b.WriteNTPTime64E(time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC)) }, "WriteNTPTime64"},
+		{"FileTime", func(b *Buffer) error { return b.WriteFileTimeE(time.Date(1600, 12, 31, 23, 59, 59, 0, time.UTC)) }, "WriteFileTime"},
+		{"DOSDateTime/before 1980", func(b *Buffer) error { return b.WriteDOSDateTimeE(time.Date(1979, 12, 31, 0, 0, 0, 0, time.UTC)) }, "WriteDOSDateTime"},
+		{"DOSDateTime/after 2107", func(b *Buffer) error { return b.WriteDOSDateTimeE(time.Date(2108, 1, 1, 0, 0, 0, 0, time.UTC)) }, "WriteDOSDateTime"},
+		{"TAI64N", func(b *Buffer) error { return b.WriteTAI64NE(time.Unix(1<<62, 0)) }, "WriteTAI64N"},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBuffer([]byte{0x01})
+
+		err := tt.write(&b)
+
+		var ee *EncodeError
+
+		if !errors.As(err, &ee) || ee.Op != tt.op || !errors.Is(err, ErrValueOverflow) {
+			t.Fatalf("%s: error = %v, want %s: %v", tt.name, err, tt.op, ErrValueOverflow)
+		}
+
+		if b.Size() != 1 {
+			t.Fatalf("%s: %d bytes written by a failed write", tt.name, b.Size()-1)
+		}
+	}
+}
+
+func TestTimeLimits(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		when  time.Time
+		write func(b *Buffer, t time.Time) error
+		read  func(b *Buffer) (time.Time, error)
+	}{
+		{"GMTUnixTime32/max", time.Unix(math.MaxUint32, 0), (*Buffer).WriteGMTUnixTime32E, (*Buffer).ReadGMTUnixTime32E},
+		{"NTPTime64/min", time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), (*Buffer).WriteNTPTime64E, (*Buffer).ReadNTPTime64E},
+		{"NTPTime64/max", time.Date(2036, 2, 7, 6, 28, 15, 999999999, time.UTC), (*Buffer).WriteNTPTime64E, (*Buffer).ReadNTPTime64E},
+		{"FileTime/min", time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC), (*Buffer).WriteFileTimeE, (*Buffer).ReadFileTimeE},
+		{"UnixNano64/before 1970", time.Unix(-1, 1), (*Buffer).WriteUnixNano64E, (*Buffer).ReadUnixNano64E},
+		{"UnixMilli64/before 1970", time.Unix(-1, 1e6), (*Buffer).WriteUnixMilli64E, (*Buffer).ReadUnixMilli64E},
+		{"DOSDateTime/min", time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), (*Buffer).WriteDOSDateTimeE, (*Buffer).ReadDOSDateTimeE},
+		{"DOSDateTime/max", time.Date(2107, 12, 31, 23, 59, 58, 0, time.UTC), (*Buffer).WriteDOSDateTimeE, (*Buffer).ReadDOSDateTimeE},
+		{"TAI64N/before 1970", time.Date(1900, 1, 1, 0, 0, 0, 1, time.UTC), (*Buffer).WriteTAI64NE, (*Buffer).ReadTAI64NE},
+	}
+
+	for _, tt := range tests {
+
+		b := NewEmpty()
+
+		if err := tt.write(&b, tt.when); err != nil {
+			t.Fatalf("%s: write: %s", tt.name, err)
+		}
+
+		if v, err := tt.read(&b); err != nil || !v.Equal(tt.when) {
+			t.Fatalf("%s: read = %s, %v, want %s", tt.name, v, err, tt.when)
+		}
+	}
+}
+
+func TestDecoderTime(t *testing.T) {
+
+	b := NewEmpty()
+	b.WriteUnixTime64(time.Unix(1700000000, 0))
+	b.WriteUint32(0)
+
+	d := NewDecoder(&b)
+
+	if v := d.ReadUnixTime64(); v.Unix() != 1700000000 || v.Location() != time.UTC {
+		t.Fatalf("ReadUnixTime64 = %s", v)
+	}
+
+	if v := d.ReadDOSDateTime(); !v.IsZero() || !errors.Is(d.Err(), ErrInvalidTime) {
+		t.Fatalf("ReadDOSDateTime = %s, %v, want ErrInvalidTime", v, d.Err())
+	}
+
+	// The error is sticky.
+	if v := d.ReadTAI64N(); !v.IsZero() || !errors.Is(d.Err(), ErrInvalidTime) {
+		t.Fatalf("ReadTAI64N after error = %s, %v", v, d.Err())
+	}
+}
@@ -0,0 +1,468 @@
+package tls
+
+import (
+	"strconv"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// Extension is a hello extension with an unparsed body.
+// The typed constructors (eg.: NewServerName) and accessors (eg.: ServerName) build and parse the known bodies.
+type Extension struct {
+	Type uint16
+	Data []byte
+}
+
+// KeyShare is an entry of the key_share extension.
+type KeyShare struct {
+	Group       uint16
+	KeyExchange []byte
+}
+
+// findExtension returns the first extension with typ.
+func findExtension(exts []Extension, typ uint16) (Extension, bool) {
+
+	for i := range exts {
+		if exts[i].Type == typ {
+			return exts[i], true
+		}
+	}
+
+	return Extension{}, false
+}
+
+// checkExtensions returns an error if the extensions of msg do not fit into the extensions block.
+func checkExtensions(msg string, exts []Extension) error {
+
+	n := 0
+
+	for i := range exts {
+
+		if len(exts[i].Data) > 1<<16-1 {
+			return bytebuilder.WrapFieldError(msg+".Extensions["+strconv.Itoa(i)+"]", ErrMalformed)
+		}
+
+		n += 4 + len(exts[i].Data)
+	}
+
+	if n > 1<<16-1 {
+		return bytebuilder.WrapFieldError(msg+".Extensions", ErrMalformed)
+	}
+
+	return nil
+}
+
+// writeExtensions appends the extensions block.
+func writeExtensions(b *bytebuilder.Buffer, exts []Extension) error {
+
+	return b.AddUint16LengthPrefixed(func(b *bytebuilder.Buffer) error {
+
+		for i := range exts {
+
+			b.WriteUint16(exts[i].Type)
+
+			if err := b.WriteVectorE(exts[i].Data, 16); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// readExtensions reads the extensions block of msg.
+// The returned slice is not nil, even if the block is empty.
+func readExtensions(msg string, b *bytebuilder.Buffer) ([]Extension, error) {
+
+	var list bytebuilder.Buffer
+
+	if err := b.ReadUint16LengthPrefixed(&list); err != nil {
+		return nil, bytebuilder.WrapFieldError(msg+".Extensions", err)
+	}
+
+	exts := []Extension{}
+
+	for i := 0; !list.Empty(); i++ {
+
+		typ, err := list.ReadUint16E()
+		if err != nil {
+			return nil, bytebuilder.WrapFieldError(msg+".Extensions["+strconv.Itoa(i)+"]", err)
+		}
+
+		data, err := list.ReadVectorE(16)
+		if err != nil {
+			return nil, bytebuilder.WrapFieldError(msg+".Extensions["+strconv.Itoa(i)+"]", err)
+		}
+
+		exts = append(exts, Extension{Type: typ, Data: append([]byte{}, data...)})
+	}
+
+	return exts, nil
+}
+
+// newExtension returns an extension with typ whose body is written by f.
+func newExtension(typ uint16, f bytebuilder.BuilderContinuation) (Extension, error) {
+
+	b := bytebuilder.NewEmpty()
+
+	if err := f(&b); err != nil {
+		return Extension{}, err
+	}
+
+	if b.Remaining() > 1<<16-1 {
+		return Extension{}, ErrMalformed
+	}
+
+	return Extension{Type: typ, Data: b.Bytes()}, nil
+}
+
+// parse checks the type of e and parses its body with f.
+// The body must be consumed completely.
+func (e Extension) parse(typ uint16, name string, f func(b *bytebuilder.Buffer) error) error {
+
+	if e.Type != typ {
+		return bytebuilder.WrapFieldError(name, ErrUnexpectedExtension)
+	}
+
+	b := bytebuilder.NewBuffer(e.Data)
+
+	if err := f(&b); err != nil {
+		return bytebuilder.WrapFieldError(name, err)
+	}
+
+	return bytebuilder.WrapFieldError(name, b.ExpectEmpty())
+}
+
+// NewServerName returns a server_name extension (SNI) with a host_name entry (RFC 6066, 3).
+func NewServerName(host string) (Extension, error) {
+
+	return newExtension(ExtensionServerName, func(b *bytebuilder.Buffer) error {
+		return b.AddUint16LengthPrefixed(func(b *bytebuilder.Buffer) error {
+			b.WriteUint8(0) // host_name
+			return b.WriteVectorE([]byte(host), 16)
+		})
+	})
+}
+
+// ServerName returns the first host_name of a server_name extension.
+// The empty extension sent by servers to acknowledge SNI returns an empty string.
+func (e Extension) ServerName() (string, error) {
+
+	var host string
+
+	err := e.parse(ExtensionServerName, "ServerName", func(b *bytebuilder.Buffer) error {
+
+		if b.Empty() {
+			return nil
+		}
+
+		var list bytebuilder.Buffer
+
+		if err := b.ReadUint16LengthPrefixed(&list); err != nil {
+			return err
+		}
+
+		for !list.Empty() {
+
+			typ, err := list.ReadUint8E()
+			if err != nil {
+				return err
+			}
+
+			name, err := list.ReadVectorE(16)
+			if err != nil {
+				return err
+			}
+
+			if typ == 0 && host == "" {
+				host = string(name)
+			}
+		}
+
+		return nil
+	})
+
+	return host, err
+}
+
+// NewALPN returns an application_layer_protocol_negotiation extension (RFC 7301).
+// The protocol names must be 1-255 bytes long.
+func NewALPN(protocols ...string) (Extension, error) {
+
+	return newExtension(ExtensionALPN, func(b *bytebuilder.Buffer) error {
+		return b.AddUint16LengthPrefixed(func(b *bytebuilder.Buffer) error {
+
+			for _, p := range protocols {
+
+				if len(p) == 0 {
+					return ErrMalformed
+				}
+
+				if err := b.WriteVectorE([]byte(p), 8); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// ALPN returns the protocol names of an application_layer_protocol_negotiation extension.
+func (e Extension) ALPN() ([]string, error) {
+
+	var protocols []string
+
+	err := e.parse(ExtensionALPN, "ALPN", func(b *bytebuilder.Buffer) error {
+
+		var list bytebuilder.Buffer
+
+		if err := b.ReadUint16LengthPrefixed(&list); err != nil {
+			return err
+		}
+
+		for !list.Empty() {
+
+			p, err := list.ReadVectorE(8)
+			if err != nil {
+				return err
+			}
+
+			if len(p) == 0 {
+				return ErrMalformed
+			}
+
+			protocols = append(protocols, string(p))
+		}
+
+		return nil
+	})
+
+	return protocols, err
+}
+
+// NewSupportedGroups returns a supported_groups extension (RFC 8446, 4.2.7).
+func NewSupportedGroups(groups ...uint16) (Extension, error) {
+
+	return newExtension(ExtensionSupportedGroups, func(b *bytebuilder.Buffer) error {
+		return writeUint16List(b, 16, groups)
+	})
+}
+
+// SupportedGroups returns the named groups of a supported_groups extension.
+func (e Extension) SupportedGroups() ([]uint16, error) {
+
+	var groups []uint16
+
+	err := e.parse(ExtensionSupportedGroups, "SupportedGroups", func(b *bytebuilder.Buffer) (err error) {
+		groups, err = readUint16List(b, 16)
+		return err
+	})
+
+	return groups, err
+}
+
+// NewSignatureAlgorithms returns a signature_algorithms extension (RFC 8446, 4.2.3).
+func NewSignatureAlgorithms(schemes ...uint16) (Extension, error) {
+
+	return newExtension(ExtensionSignatureAlgorithms, func(b *bytebuilder.Buffer) error {
+		return writeUint16List(b, 16, schemes)
+	})
+}
+
+// SignatureAlgorithms returns the signature schemes of a signature_algorithms extension.
+func (e Extension) SignatureAlgorithms() ([]uint16, error) {
+
+	var schemes []uint16
+
+	err := e.parse(ExtensionSignatureAlgorithms, "SignatureAlgorithms", func(b *bytebuilder.Buffer) (err error) {
+		schemes, err = readUint16List(b, 16)
+		return err
+	})
+
+	return schemes, err
+}
+
+// NewECPointFormats returns an ec_point_formats extension (RFC 8422, 5.1.2).
+func NewECPointFormats(formats ...uint8) (Extension, error) {
+
+	return newExtension(ExtensionECPointFormats, func(b *bytebuilder.Buffer) error {
+		return b.WriteVectorE(formats, 8)
+	})
+}
+
+// ECPointFormats returns the point formats of an ec_point_formats extension.
+func (e Extension) ECPointFormats() ([]uint8, error) {
+
+	var formats []uint8
+
+	err := e.parse(ExtensionECPointFormats, "ECPointFormats", func(b *bytebuilder.Buffer) error {
+
+		v, err := b.ReadVectorE(8)
+		if err != nil {
+			return err
+		}
+
+		formats = append([]uint8{}, v...)
+
+		return nil
+	})
+
+	return formats, err
+}
+
+// NewClientSupportedVersions returns the supported_versions extension of a ClientHello (RFC 8446, 4.2.1).
+func NewClientSupportedVersions(versions ...uint16) (Extension, error) {
+
+	return newExtension(ExtensionSupportedVersions, func(b *bytebuilder.Buffer) error {
+		return writeUint16List(b, 8, versions)
+	})
+}
+
+// ClientSupportedVersions returns the versions of the supported_versions extension of a ClientHello.
+func (e Extension) ClientSupportedVersions() ([]uint16, error) {
+
+	var versions []uint16
+
+	err := e.parse(ExtensionSupportedVersions, "ClientSupportedVersions", func(b *bytebuilder.Buffer) (err error) {
+		versions, err = readUint16List(b, 8)
+		return err
+	})
+
+	return versions, err
+}
+
+// NewServerSupportedVersion returns the supported_versions extension of a ServerHello with the selected version.
+func NewServerSupportedVersion(version uint16) (Extension, error) {
+
+	return newExtension(ExtensionSupportedVersions, func(b *bytebuilder.Buffer) error {
+		b.WriteUint16(version)
+		return nil
+	})
+}
+
+// ServerSupportedVersion returns the selected version of the supported_versions extension of a ServerHello.
+func (e Extension) ServerSupportedVersion() (uint16, error) {
+
+	var version uint16
+
+	err := e.parse(ExtensionSupportedVersions, "ServerSupportedVersion", func(b *bytebuilder.Buffer) (err error) {
+		version, err = b.ReadUint16E()
+		return err
+	})
+
+	return version, err
+}
+
+// writeKeyShare appends a KeyShareEntry.
+func writeKeyShare(b *bytebuilder.Buffer, share KeyShare) error {
+
+	b.WriteUint16(share.Group)
+
+	return b.WriteVectorE(share.KeyExchange, 16)
+}
+
+// readKeyShare reads a KeyShareEntry.
+func readKeyShare(b *bytebuilder.Buffer) (KeyShare, error) {
+
+	group, err := b.ReadUint16E()
+	if err != nil {
+		return KeyShare{}, err
+	}
+
+	key, err := b.ReadVectorE(16)
+	if err != nil {
+		return KeyShare{}, err
+	}
+
+	return KeyShare{Group: group, KeyExchange: append([]byte{}, key...)}, nil
+}
+
+// NewClientKeyShares returns the key_share extension of a ClientHello (RFC 8446, 4.2.8).
+func NewClientKeyShares(shares ...KeyShare) (Extension, error) {
+
+	return newExtension(ExtensionKeyShare, func(b *bytebuilder.Buffer) error {
+		return b.AddUint16LengthPrefixed(func(b *bytebuilder.Buffer) error {
+
+			for _, s := range shares {
+				if err := writeKeyShare(b, s); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// ClientKeyShares returns the entries of the key_share extension of a ClientHello.
+func (e Extension) ClientKeyShares() ([]KeyShare, error) {
+
+	var shares []KeyShare
+
+	err := e.parse(ExtensionKeyShare, "ClientKeyShares", func(b *bytebuilder.Buffer) error {
+
+		var list bytebuilder.Buffer
+
+		if err := b.ReadUint16LengthPrefixed(&list); err != nil {
+			return err
+		}
+
+		for !list.Empty() {
+
+			s, err := readKeyShare(&list)
+			if err != nil {
+				return err
+			}
+
+			shares = append(shares, s)
+		}
+
+		return nil
+	})
+
+	return shares, err
+}
+
+// NewServerKeyShare returns the key_share extension of a ServerHello with the selected entry.
+func NewServerKeyShare(share KeyShare) (Extension, error) {
+
+	return newExtension(ExtensionKeyShare, func(b *bytebuilder.Buffer) error {
+		return writeKeyShare(b, share)
+	})
+}
+
+// ServerKeyShare returns the selected entry of the key_share extension of a ServerHello.
+func (e Extension) ServerKeyShare() (KeyShare, error) {
+
+	var share KeyShare
+
+	err := e.parse(ExtensionKeyShare, "ServerKeyShare", func(b *bytebuilder.Buffer) (err error) {
+		share, err = readKeyShare(b)
+		return err
+	})
+
+	return share, err
+}
+
+// NewHelloRetryKeyShare returns the key_share extension of a HelloRetryRequest with the selected group.
+func NewHelloRetryKeyShare(group uint16) (Extension, error) {
+
+	return newExtension(ExtensionKeyShare, func(b *bytebuilder.Buffer) error {
+		b.WriteUint16(group)
+		return nil
+	})
+}
+
+// HelloRetryKeyShare returns the selected group of the key_share extension of a HelloRetryRequest.
+func (e Extension) HelloRetryKeyShare() (uint16, error) {
+
+	var group uint16
+
+	err := e.parse(ExtensionKeyShare, "HelloRetryKeyShare", func(b *bytebuilder.Buffer) (err error) {
+		group, err = b.ReadUint16E()
+		return err
+	})
+
+	return group, err
+}
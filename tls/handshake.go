@@ -0,0 +1,404 @@
+package tls
+
+import (
+	"io"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// HandshakeHeaderLength is the size of the handshake message header: type and uint24 length.
+const HandshakeHeaderLength = 4
+
+// maxHandshakeLength is the largest length of a handshake message body.
+const maxHandshakeLength = 1<<24 - 1
+
+// Handshake is a handshake message with an unparsed body.
+type Handshake struct {
+	Type HandshakeType
+	Body []byte
+}
+
+// EncodeTo appends the handshake message to b.
+func (h *Handshake) EncodeTo(b *bytebuilder.Buffer) error {
+
+	if len(h.Body) > maxHandshakeLength {
+		return ErrMalformed
+	}
+
+	b.WriteUint8(uint8(h.Type))
+	b.WriteBigUint24(uint32(len(h.Body)))
+	b.WriteBytes(h.Body...)
+
+	return nil
+}
+
+// DecodeFrom reads a handshake message from b. The Body points into b.
+// Messages fragmented across records must be concatenated before decoding.
+func (h *Handshake) DecodeFrom(b *bytebuilder.Buffer) error {
+
+	typ, err := b.ReadUint8E()
+	if err != nil {
+		return bytebuilder.WrapFieldError("Handshake.Type", err)
+	}
+
+	n, err := b.ReadBigUint24E()
+	if err != nil {
+		return bytebuilder.WrapFieldError("Handshake.Body", err)
+	}
+
+	body, err := b.ReadBytesE(int(n))
+	if err != nil {
+		return bytebuilder.WrapFieldError("Handshake.Body", err)
+	}
+
+	*h = Handshake{Type: HandshakeType(typ), Body: body}
+
+	return nil
+}
+
+// PeekHandshakeType returns the type of the next handshake message in b without moving the read cursor.
+// The bool indicates whether the read was successful.
+func PeekHandshakeType(b *bytebuilder.Buffer) (HandshakeType, bool) {
+
+	v := b.Bytes()
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	return HandshakeType(v[0]), true
+}
+
+// readHandshake reads the header of a message with typ and sets body to a Buffer over its content.
+// If the type does not match, returns ErrUnexpectedMessage and the read cursor is not moved.
+func readHandshake(b *bytebuilder.Buffer, typ HandshakeType, body *bytebuilder.Buffer) error {
+
+	t, ok := PeekHandshakeType(b)
+	if !ok {
+		_, err := b.ReadUint8E()
+		return err
+	}
+
+	if t != typ {
+		return ErrUnexpectedMessage
+	}
+
+	b.Skip(1)
+
+	return b.ReadUint24LengthPrefixed(body)
+}
+
+// ClientHello is the ClientHello handshake message.
+//
+// Extensions is nil if the message has no extensions block (eg.: SSL 3.0),
+// an empty non-nil slice is encoded as an empty extensions block.
+type ClientHello struct {
+	Version            uint16 // legacy_version
+	Random             [32]byte
+	SessionID          []byte
+	CipherSuites       []uint16
+	CompressionMethods []uint8
+	Extensions         []Extension
+}
+
+// EncodeTo appends the ClientHello with the handshake header to b.
+// If a field violates the length constraints, returns an error wrapping ErrMalformed and b is left unchanged.
+func (m *ClientHello) EncodeTo(b *bytebuilder.Buffer) error {
+
+	switch {
+	case len(m.SessionID) > 32:
+		return bytebuilder.WrapFieldError("ClientHello.SessionID", ErrMalformed)
+	case len(m.CipherSuites) == 0 || len(m.CipherSuites) > 1<<15-1:
+		return bytebuilder.WrapFieldError("ClientHello.CipherSuites", ErrMalformed)
+	case len(m.CompressionMethods) == 0 || len(m.CompressionMethods) > 255:
+		return bytebuilder.WrapFieldError("ClientHello.CompressionMethods", ErrMalformed)
+	}
+
+	if err := checkExtensions("ClientHello", m.Extensions); err != nil {
+		return err
+	}
+
+	w := network(b)
+
+	w.WriteUint8(uint8(TypeClientHello))
+
+	err := w.AddUint24LengthPrefixed(func(b *bytebuilder.Buffer) error {
+
+		b.WriteUint16(m.Version)
+		b.WriteBytes(m.Random[:]...)
+		b.WriteVector(m.SessionID, 8)
+
+		if err := writeUint16List(b, 16, m.CipherSuites); err != nil {
+			return err
+		}
+
+		b.WriteVector(m.CompressionMethods, 8)
+
+		if m.Extensions != nil {
+			return writeExtensions(b, m.Extensions)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*b.BytesPointer() = *w.BytesPointer()
+
+	return nil
+}
+
+// DecodeFrom reads a ClientHello with the handshake header from b.
+// If the next message is not a ClientHello, returns ErrUnexpectedMessage and the read cursor is not moved.
+func (m *ClientHello) DecodeFrom(b *bytebuilder.Buffer) error {
+
+	r := network(b)
+
+	var body bytebuilder.Buffer
+
+	if err := readHandshake(&r, TypeClientHello, &body); err != nil {
+		return bytebuilder.WrapFieldError("ClientHello", err)
+	}
+
+	var (
+		v   ClientHello
+		err error
+	)
+
+	if v.Version, err = body.ReadUint16E(); err != nil {
+		return bytebuilder.WrapFieldError("ClientHello.Version", err)
+	}
+
+	if err := readRandom(&body, &v.Random); err != nil {
+		return bytebuilder.WrapFieldError("ClientHello.Random", err)
+	}
+
+	if v.SessionID, err = readSessionID(&body); err != nil {
+		return bytebuilder.WrapFieldError("ClientHello.SessionID", err)
+	}
+
+	if v.CipherSuites, err = readUint16List(&body, 16); err != nil {
+		return bytebuilder.WrapFieldError("ClientHello.CipherSuites", err)
+	}
+
+	if len(v.CipherSuites) == 0 {
+		return bytebuilder.WrapFieldError("ClientHello.CipherSuites", ErrMalformed)
+	}
+
+	methods, err := body.ReadVectorE(8)
+	if err != nil {
+		return bytebuilder.WrapFieldError("ClientHello.CompressionMethods", err)
+	}
+
+	if len(methods) == 0 {
+		return bytebuilder.WrapFieldError("ClientHello.CompressionMethods", ErrMalformed)
+	}
+
+	v.CompressionMethods = append([]uint8{}, methods...)
+
+	if !body.Empty() {
+		if v.Extensions, err = readExtensions("ClientHello", &body); err != nil {
+			return err
+		}
+	}
+
+	if err := body.ExpectEmpty(); err != nil {
+		return bytebuilder.WrapFieldError("ClientHello", err)
+	}
+
+	*m = v
+
+	b.Seek(int64(r.Offset()), io.SeekStart)
+
+	return nil
+}
+
+// Extension returns the first extension of m with typ.
+// The bool indicates whether the extension is present.
+func (m *ClientHello) Extension(typ uint16) (Extension, bool) {
+	return findExtension(m.Extensions, typ)
+}
+
+// ServerHello is the ServerHello handshake message.
+// A HelloRetryRequest is a ServerHello with a special Random (see IsHelloRetryRequest).
+type ServerHello struct {
+	Version           uint16 // legacy_version
+	Random            [32]byte
+	SessionID         []byte
+	CipherSuite       uint16
+	CompressionMethod uint8
+	Extensions        []Extension
+}
+
+// EncodeTo appends the ServerHello with the handshake header to b.
+// If a field violates the length constraints, returns an error wrapping ErrMalformed and b is left unchanged.
+func (m *ServerHello) EncodeTo(b *bytebuilder.Buffer) error {
+
+	if len(m.SessionID) > 32 {
+		return bytebuilder.WrapFieldError("ServerHello.SessionID", ErrMalformed)
+	}
+
+	if err := checkExtensions("ServerHello", m.Extensions); err != nil {
+		return err
+	}
+
+	w := network(b)
+
+	w.WriteUint8(uint8(TypeServerHello))
+
+	err := w.AddUint24LengthPrefixed(func(b *bytebuilder.Buffer) error {
+
+		b.WriteUint16(m.Version)
+		b.WriteBytes(m.Random[:]...)
+		b.WriteVector(m.SessionID, 8)
+		b.WriteUint16(m.CipherSuite)
+		b.WriteUint8(m.CompressionMethod)
+
+		if m.Extensions != nil {
+			return writeExtensions(b, m.Extensions)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*b.BytesPointer() = *w.BytesPointer()
+
+	return nil
+}
+
+// DecodeFrom reads a ServerHello with the handshake header from b.
+// If the next message is not a ServerHello, returns ErrUnexpectedMessage and the read cursor is not moved.
+func (m *ServerHello) DecodeFrom(b *bytebuilder.Buffer) error {
+
+	r := network(b)
+
+	var body bytebuilder.Buffer
+
+	if err := readHandshake(&r, TypeServerHello, &body); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello", err)
+	}
+
+	var (
+		v   ServerHello
+		err error
+	)
+
+	if v.Version, err = body.ReadUint16E(); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello.Version", err)
+	}
+
+	if err := readRandom(&body, &v.Random); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello.Random", err)
+	}
+
+	if v.SessionID, err = readSessionID(&body); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello.SessionID", err)
+	}
+
+	if v.CipherSuite, err = body.ReadUint16E(); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello.CipherSuite", err)
+	}
+
+	if v.CompressionMethod, err = body.ReadUint8E(); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello.CompressionMethod", err)
+	}
+
+	if !body.Empty() {
+		if v.Extensions, err = readExtensions("ServerHello", &body); err != nil {
+			return err
+		}
+	}
+
+	if err := body.ExpectEmpty(); err != nil {
+		return bytebuilder.WrapFieldError("ServerHello", err)
+	}
+
+	*m = v
+
+	b.Seek(int64(r.Offset()), io.SeekStart)
+
+	return nil
+}
+
+// Extension returns the first extension of m with typ.
+// The bool indicates whether the extension is present.
+func (m *ServerHello) Extension(typ uint16) (Extension, bool) {
+	return findExtension(m.Extensions, typ)
+}
+
+// IsHelloRetryRequest returns whether m is a HelloRetryRequest (RFC 8446, 4.1.3).
+func (m *ServerHello) IsHelloRetryRequest() bool {
+	return m.Random == helloRetryRequestRandom
+}
+
+// SetHelloRetryRequest sets the Random of m to the value identifying a HelloRetryRequest.
+func (m *ServerHello) SetHelloRetryRequest() {
+	m.Random = helloRetryRequestRandom
+}
+
+// readRandom reads the 32 byte random into dst.
+func readRandom(b *bytebuilder.Buffer, dst *[32]byte) error {
+
+	v, err := b.ReadBytesE(32)
+	if err != nil {
+		return err
+	}
+
+	copy(dst[:], v)
+
+	return nil
+}
+
+// readSessionID reads the legacy_session_id with at most 32 bytes.
+func readSessionID(b *bytebuilder.Buffer) ([]byte, error) {
+
+	v, err := b.ReadVectorE(8)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) > 32 {
+		return nil, ErrMalformed
+	}
+
+	return append([]byte{}, v...), nil
+}
+
+// readUint16List reads a length-prefixed list of uint16 values, the length is the size in bytes.
+func readUint16List(b *bytebuilder.Buffer, bitSize int) ([]uint16, error) {
+
+	var list bytebuilder.Buffer
+
+	if err := b.ReadLengthPrefixed(bitSize, &list); err != nil {
+		return nil, err
+	}
+
+	if list.Remaining()%2 != 0 {
+		return nil, ErrMalformed
+	}
+
+	v := make([]uint16, 0, list.Remaining()/2)
+
+	for !list.Empty() {
+		x, _ := list.ReadUint16()
+		v = append(v, x)
+	}
+
+	return v, nil
+}
+
+// writeUint16List appends a length-prefixed list of uint16 values.
+func writeUint16List(b *bytebuilder.Buffer, bitSize int, v []uint16) error {
+
+	return b.AddLengthPrefixed(bitSize, func(b *bytebuilder.Buffer) error {
+
+		for _, x := range v {
+			b.WriteUint16(x)
+		}
+
+		return nil
+	})
+}
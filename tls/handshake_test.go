@@ -0,0 +1,295 @@
+package tls
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+// message is a handshake message with a pointer receiver EncodeTo and DecodeFrom.
+type message interface {
+	bytebuilder.Marshaler
+	bytebuilder.Unmarshaler
+}
+
+func TestHelloRoundTrip(t *testing.T) {
+
+	ext := func(e Extension, err error) Extension {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	tests := []struct {
+		name string
+		m    message
+		new  func() message
+	}{
+		{"client hello", &ClientHello{
+			Version:            VersionTLS12,
+			Random:             [32]byte{1, 2, 3},
+			SessionID:          bytes.Repeat([]byte{0xaa}, 32),
+			CipherSuites:       []uint16{0x1301, 0x1302, 0xc02f},
+			CompressionMethods: []uint8{0},
+			Extensions: []Extension{
+				ext(NewServerName("example.com")),
+				ext(NewALPN("h2", "http/1.1")),
+				ext(NewSupportedGroups(29, 23)),
+				ext(NewClientSupportedVersions(VersionTLS13, VersionTLS12)),
+				ext(NewClientKeyShares(KeyShare{Group: 29, KeyExchange: bytes.Repeat([]byte{1}, 32)})),
+				{Type: 0x0a0a, Data: []byte{}},
+			},
+		}, func() message { return new(ClientHello) }},
+		{"client hello without extensions", &ClientHello{
+			Version:            VersionSSL30,
+			SessionID:          []byte{},
+			CipherSuites:       []uint16{0x000a},
+			CompressionMethods: []uint8{0},
+		}, func() message { return new(ClientHello) }},
+		{"server hello", &ServerHello{
+			Version:           VersionTLS12,
+			Random:            [32]byte{4, 5, 6},
+			SessionID:         []byte{1},
+			CipherSuite:       0x1301,
+			CompressionMethod: 0,
+			Extensions: []Extension{
+				ext(NewServerSupportedVersion(VersionTLS13)),
+				ext(NewServerKeyShare(KeyShare{Group: 29, KeyExchange: []byte{1, 2}})),
+			},
+		}, func() message { return new(ServerHello) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			big := bytebuilder.NewEmpty()
+
+			if err := tt.m.EncodeTo(&big); err != nil {
+				t.Fatalf("EncodeTo: %s", err)
+			}
+
+			// The byte order of the Buffer must not change the encoding.
+			little := bytebuilder.NewBufferWithEndianness(nil, bytebuilder.LittleEndian)
+
+			if err := tt.m.EncodeTo(&little); err != nil {
+				t.Fatalf("EncodeTo: %s", err)
+			}
+
+			if !bytes.Equal(big.Bytes(), little.Bytes()) {
+				t.Fatalf("little-endian encoding = % x, want % x", little.Bytes(), big.Bytes())
+			}
+
+			m := tt.new()
+
+			if err := m.DecodeFrom(&little); err != nil {
+				t.Fatalf("DecodeFrom: %s", err)
+			}
+
+			if !reflect.DeepEqual(m, tt.m) {
+				t.Fatalf("DecodeFrom = %+v, want %+v", m, tt.m)
+			}
+
+			if little.Endianness() != bytebuilder.LittleEndian {
+				t.Fatal("the byte order of the Buffer is changed")
+			}
+		})
+	}
+}
+
+func TestHelloErrors(t *testing.T) {
+
+	tests := []struct {
+		name string
+		m    message
+		err  error
+	}{
+		{"long session id", &ClientHello{SessionID: make([]byte, 33), CipherSuites: []uint16{1}, CompressionMethods: []uint8{0}}, ErrMalformed},
+		{"no cipher suites", &ClientHello{CompressionMethods: []uint8{0}}, ErrMalformed},
+		{"no compression methods", &ClientHello{CipherSuites: []uint16{1}}, ErrMalformed},
+		{"server long session id", &ServerHello{SessionID: make([]byte, 33)}, ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			b := bytebuilder.NewBuffer([]byte{0xaa})
+
+			if err := tt.m.EncodeTo(&b); !errors.Is(err, tt.err) {
+				t.Fatalf("EncodeTo error = %v, want %v", err, tt.err)
+			}
+
+			if !bytes.Equal(b.Bytes(), []byte{0xaa}) {
+				t.Fatalf("Buffer = % x after a failed EncodeTo, want aa", b.Bytes())
+			}
+		})
+	}
+
+	// A ServerHello is not a ClientHello.
+	b := bytebuilder.NewEmpty()
+
+	if err := (&ServerHello{SessionID: []byte{}}).EncodeTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := new(ClientHello).DecodeFrom(&b); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("DecodeFrom error = %v, want %v", err, ErrUnexpectedMessage)
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+
+	r := Record{Type: ContentTypeHandshake, Version: VersionTLS10, Fragment: []byte{1, 2, 3}}
+
+	b := bytebuilder.NewBufferWithEndianness(nil, bytebuilder.LittleEndian)
+
+	if err := r.EncodeTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []byte{22, 3, 1, 0, 3, 1, 2, 3}; !bytes.Equal(b.Bytes(), want) {
+		t.Fatalf("EncodeTo = % x, want % x", b.Bytes(), want)
+	}
+
+	s := bytebuilder.NewStreamReaderWithEndianness(bytes.NewReader(b.Bytes()), bytebuilder.LittleEndian)
+
+	v, err := ReadRecord(s)
+	if err != nil || !reflect.DeepEqual(v, r) {
+		t.Fatalf("ReadRecord = %+v, %v, want %+v", v, err, r)
+	}
+
+	var d Record
+
+	if err := d.DecodeFrom(&b); err != nil || !reflect.DeepEqual(d, r) {
+		t.Fatalf("DecodeFrom = %+v, %v, want %+v", d, err, r)
+	}
+}
+
+// TestHelloOffsets checks the read cursor after a message and the offsets of the errors in a truncated message.
+func TestHelloOffsets(t *testing.T) {
+
+	m := ClientHello{Version: VersionTLS12, SessionID: []byte{1, 2}, CipherSuites: []uint16{0x1301}, CompressionMethods: []uint8{0}}
+
+	b := bytebuilder.NewBuffer([]byte{0xaa})
+
+	if err := m.EncodeTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	b.WriteUint8(0xbb)
+	b.Skip(1)
+
+	if err := new(ClientHello).DecodeFrom(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := b.ReadUint8(); v != 0xbb || !b.Empty() {
+		t.Fatalf("read cursor is not after the message: next byte %#x, %d bytes left", v, b.Remaining())
+	}
+
+	// Set the length of CipherSuites larger than the rest of the body.
+	// The error reports the offset of the vector in the Buffer: 0xaa, header (4), Version (2), Random (32),
+	// SessionID (3) and the length (2).
+	data := append([]byte{}, *b.BytesPointer()...)
+	data[42], data[43] = 0x00, 0xff
+
+	bad := bytebuilder.NewBuffer(data)
+	bad.Skip(1)
+
+	err := new(ClientHello).DecodeFrom(&bad)
+
+	var de *bytebuilder.DecodeError
+
+	if !errors.As(err, &de) || de.Offset != 44 {
+		t.Fatalf("DecodeFrom error = %v, want offset 44", err)
+	}
+
+	if bad.Offset() != 1 {
+		t.Fatalf("Offset = %d after error, want 1", bad.Offset())
+	}
+}
+
+func TestReadRecordEOF(t *testing.T) {
+
+	r := Record{Type: ContentTypeHandshake, Version: VersionTLS12, Fragment: []byte{1, 2, 3}}
+
+	b := bytebuilder.NewEmpty()
+
+	if err := r.EncodeTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	data := b.Bytes()
+
+	if _, err := ReadRecord(bytebuilder.NewStreamReader(bytes.NewReader(nil))); err != io.EOF {
+		t.Fatalf("ReadRecord of an empty stream error = %v, want %v", err, io.EOF)
+	}
+
+	for n := 1; n < len(data); n++ {
+
+		_, err := ReadRecord(bytebuilder.NewStreamReader(bytes.NewReader(data[:n])))
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("ReadRecord of %d bytes error = %v, want %v", n, err, io.ErrUnexpectedEOF)
+		}
+	}
+}
+
+// fuzzHello decodes data with m and checks that a decoded message encodes to the same bytes.
+func fuzzHello(t *testing.T, data []byte, m message) {
+
+	b := bytebuilder.NewBuffer(data)
+	b.SetLimits(bytebuilder.Limits{MaxAllocation: 1 << 20})
+
+	if err := m.DecodeFrom(&b); err != nil {
+		return
+	}
+
+	out := bytebuilder.NewEmpty()
+
+	if err := m.EncodeTo(&out); err != nil {
+		t.Fatalf("EncodeTo of a decoded message: %s", err)
+	}
+
+	if n := len(data) - b.Remaining(); !bytes.Equal(out.Bytes(), data[:n]) {
+		t.Fatalf("EncodeTo = % x, want % x", out.Bytes(), data[:n])
+	}
+}
+
+func FuzzClientHello(f *testing.F) {
+
+	for _, m := range []ClientHello{
+		{Version: VersionTLS12, SessionID: []byte{1}, CipherSuites: []uint16{0x1301}, CompressionMethods: []uint8{0}, Extensions: []Extension{{Type: 0, Data: []byte{0, 0}}}},
+		{Version: VersionSSL30, SessionID: []byte{}, CipherSuites: []uint16{0x000a}, CompressionMethods: []uint8{0}},
+	} {
+		b := bytebuilder.NewEmpty()
+		if err := m.EncodeTo(&b); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b.Bytes())
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzHello(t, data, new(ClientHello))
+	})
+}
+
+func FuzzServerHello(f *testing.F) {
+
+	m := ServerHello{Version: VersionTLS12, SessionID: []byte{}, CipherSuite: 0x1301, Extensions: []Extension{{Type: 43, Data: []byte{3, 4}}}}
+
+	b := bytebuilder.NewEmpty()
+	if err := m.EncodeTo(&b); err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(b.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzHello(t, data, new(ServerHello))
+	})
+}
@@ -0,0 +1,138 @@
+package tls
+
+import (
+	"io"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+const (
+	// MaxPlaintextLength is the maximum length of the fragment of a plaintext record.
+	MaxPlaintextLength = 1 << 14
+
+	// MaxCiphertextLength is the maximum length of the fragment of a protected record.
+	MaxCiphertextLength = MaxPlaintextLength + 2048
+
+	// RecordHeaderLength is the size of the record header: type, version and length.
+	RecordHeaderLength = 5
+)
+
+// Record is a TLS record.
+type Record struct {
+	Type     ContentType
+	Version  uint16 // legacy_record_version
+	Fragment []byte
+}
+
+// EncodeTo appends the record to b.
+// If Fragment is longer than MaxCiphertextLength, returns ErrRecordOverflow and b is left unchanged.
+func (r *Record) EncodeTo(b *bytebuilder.Buffer) error {
+
+	if len(r.Fragment) > MaxCiphertextLength {
+		return ErrRecordOverflow
+	}
+
+	b.WriteUint8(uint8(r.Type))
+	b.WriteBigUint16(r.Version)
+	b.WriteBigUint16(uint16(len(r.Fragment)))
+	b.WriteBytes(r.Fragment...)
+
+	return nil
+}
+
+// DecodeFrom reads a record from b. The Fragment points into b.
+// If the length is larger than MaxCiphertextLength, returns ErrRecordOverflow.
+func (r *Record) DecodeFrom(b *bytebuilder.Buffer) error {
+
+	typ, err := b.ReadUint8E()
+	if err != nil {
+		return bytebuilder.WrapFieldError("Record.Type", err)
+	}
+
+	version, err := b.ReadBigUint16E()
+	if err != nil {
+		return bytebuilder.WrapFieldError("Record.Version", err)
+	}
+
+	n, err := b.ReadBigUint16E()
+	if err != nil {
+		return bytebuilder.WrapFieldError("Record.Fragment", err)
+	}
+
+	if n > MaxCiphertextLength {
+		return ErrRecordOverflow
+	}
+
+	fragment, err := b.ReadBytesE(int(n))
+	if err != nil {
+		return bytebuilder.WrapFieldError("Record.Fragment", err)
+	}
+
+	*r = Record{Type: ContentType(typ), Version: version, Fragment: fragment}
+
+	return nil
+}
+
+// ReadRecord reads the next record from s (eg.: from a net.Conn).
+// Returns io.EOF only if s ended before the record, a record cut short returns an error wrapping io.ErrUnexpectedEOF.
+// If the length is larger than MaxCiphertextLength, returns ErrRecordOverflow.
+func ReadRecord(s *bytebuilder.StreamReader) (Record, error) {
+
+	typ, err := s.ReadUint8()
+	if err != nil {
+		return Record{}, err
+	}
+
+	version, err := s.ReadBigUint16()
+	if err != nil {
+		return Record{}, unexpectedEOF(err)
+	}
+
+	n, err := s.ReadBigUint16()
+	if err != nil {
+		return Record{}, unexpectedEOF(err)
+	}
+
+	if n > MaxCiphertextLength {
+		return Record{}, ErrRecordOverflow
+	}
+
+	fragment, err := s.ReadBytes(int(n))
+	if err != nil {
+		return Record{}, unexpectedEOF(err)
+	}
+
+	return Record{Type: ContentType(typ), Version: version, Fragment: fragment}, nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, for the reads after the first byte of a record.
+func unexpectedEOF(err error) error {
+
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// AppendRecords splits data into records of type with at most MaxPlaintextLength bytes and appends them to b.
+// Empty data is written as a single empty record.
+func AppendRecords(b *bytebuilder.Buffer, typ ContentType, version uint16, data []byte) {
+
+	for {
+
+		n := len(data)
+		if n > MaxPlaintextLength {
+			n = MaxPlaintextLength
+		}
+
+		r := Record{Type: typ, Version: version, Fragment: data[:n]}
+		r.EncodeTo(b)
+
+		data = data[n:]
+
+		if len(data) == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package tls reads and writes the TLS wire format with bytebuilder:
+// records, handshake messages, ClientHello and ServerHello with their extensions (RFC 8446, RFC 5246).
+//
+// It implements only the presentation layer, there is no cryptography or state machine,
+// so it can be used to build and dissect hellos (eg.: fingerprinting, test servers).
+// Unknown extensions, cipher suites and GREASE values are kept as they are.
+//
+// Every message implements bytebuilder.Marshaler and bytebuilder.Unmarshaler.
+// The messages are always encoded in network (big-endian) byte order, independent of the byte order of the Buffers.
+package tls
+
+import (
+	"errors"
+
+	"github.com/g0rbe/go-bytebuilder"
+)
+
+var (
+	// ErrUnexpectedMessage is returned when a handshake message has a different type than expected.
+	ErrUnexpectedMessage = errors.New("tls: unexpected message")
+
+	// ErrUnexpectedExtension is returned when an extension is parsed as a different type.
+	ErrUnexpectedExtension = errors.New("tls: unexpected extension")
+
+	// ErrRecordOverflow is returned when a record is longer than MaxCiphertextLength.
+	ErrRecordOverflow = errors.New("tls: record overflow")
+
+	// ErrMalformed is returned when a message violates the length constraints of the protocol.
+	ErrMalformed = errors.New("tls: malformed message")
+)
+
+// Protocol versions
+const (
+	VersionSSL30 uint16 = 0x0300
+	VersionTLS10 uint16 = 0x0301
+	VersionTLS11 uint16 = 0x0302
+	VersionTLS12 uint16 = 0x0303
+	VersionTLS13 uint16 = 0x0304
+)
+
+// ContentType is the type of a record.
+type ContentType uint8
+
+const (
+	ContentTypeChangeCipherSpec ContentType = 20
+	ContentTypeAlert            ContentType = 21
+	ContentTypeHandshake        ContentType = 22
+	ContentTypeApplicationData  ContentType = 23
+	ContentTypeHeartbeat        ContentType = 24
+)
+
+// HandshakeType is the type of a handshake message.
+type HandshakeType uint8
+
+const (
+	TypeHelloRequest        HandshakeType = 0
+	TypeClientHello         HandshakeType = 1
+	TypeServerHello         HandshakeType = 2
+	TypeNewSessionTicket    HandshakeType = 4
+	TypeEndOfEarlyData      HandshakeType = 5
+	TypeEncryptedExtensions HandshakeType = 8
+	TypeCertificate         HandshakeType = 11
+	TypeServerKeyExchange   HandshakeType = 12
+	TypeCertificateRequest  HandshakeType = 13
+	TypeServerHelloDone     HandshakeType = 14
+	TypeCertificateVerify   HandshakeType = 15
+	TypeClientKeyExchange   HandshakeType = 16
+	TypeFinished            HandshakeType = 20
+	TypeKeyUpdate           HandshakeType = 24
+	TypeMessageHash         HandshakeType = 254
+)
+
+// Extension types
+const (
+	ExtensionServerName           uint16 = 0
+	ExtensionStatusRequest        uint16 = 5
+	ExtensionSupportedGroups      uint16 = 10
+	ExtensionECPointFormats       uint16 = 11
+	ExtensionSignatureAlgorithms  uint16 = 13
+	ExtensionALPN                 uint16 = 16
+	ExtensionSCT                  uint16 = 18
+	ExtensionPadding              uint16 = 21
+	ExtensionExtendedMasterSecret uint16 = 23
+	ExtensionSessionTicket        uint16 = 35
+	ExtensionPreSharedKey         uint16 = 41
+	ExtensionEarlyData            uint16 = 42
+	ExtensionSupportedVersions    uint16 = 43
+	ExtensionCookie               uint16 = 44
+	ExtensionPSKModes             uint16 = 45
+	ExtensionKeyShare             uint16 = 51
+	ExtensionRenegotiationInfo    uint16 = 0xff01
+)
+
+// Named groups
+const (
+	GroupSecp256r1 uint16 = 0x0017
+	GroupSecp384r1 uint16 = 0x0018
+	GroupSecp521r1 uint16 = 0x0019
+	GroupX25519    uint16 = 0x001d
+	GroupX448      uint16 = 0x001e
+)
+
+// helloRetryRequestRandom is the Random of a ServerHello that is a HelloRetryRequest (RFC 8446, 4.1.3).
+var helloRetryRequestRandom = [32]byte{
+	0xcf, 0x21, 0xad, 0x74, 0xe5, 0x9a, 0x61, 0x11, 0xbe, 0x1d, 0x8c, 0x02, 0x1e, 0x65, 0xb8, 0x91,
+	0xc2, 0xa2, 0x11, 0x16, 0x7a, 0xbb, 0x8c, 0x5e, 0x07, 0x9e, 0x09, 0xe2, 0xc8, 0xa8, 0x33, 0x9c,
+}
+
+// network returns a copy of b in big-endian byte order.
+// The copy shares the bytes, the read cursor and the limits of b, so a message can be read or written
+// in network byte order without changing the byte order of b. The child Buffers of the length-prefixed
+// vectors inherit the byte order, so the whole message is big-endian.
+// The new read cursor or the written bytes are copied back to b by the caller on success.
+func network(b *bytebuilder.Buffer) bytebuilder.Buffer {
+
+	c := *b
+	c.SetEndianness(bytebuilder.BigEndian)
+
+	return c
+}
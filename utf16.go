@@ -0,0 +1,763 @@
+package bytebuilder
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UTF-16 strings are converted from and to Go strings (UTF-8).
+// The runes outside the Basic Multilingual Plane are encoded as surrogate pairs,
+// unpaired surrogates are decoded as utf8.RuneError and invalid UTF-8 is encoded as utf8.RuneError.
+//
+// The byte order is given by an Endianness (eg.: from ReadUTF16BOM),
+// it is used for both the code units and the length of the vectors.
+
+// utf16BOM is the byte order mark, encoded as 0xFF 0xFE in little-endian and 0xFE 0xFF in big-endian order.
+const utf16BOM = 0xfeff
+
+// AppendUTF16 appends s encoded as UTF-16 in byte order e at the end of dst and returns the extended slice.
+func AppendUTF16(dst []byte, s string, e Endianness) []byte {
+
+	for _, r := range s {
+
+		if r >= 0x10000 {
+			r1, r2 := utf16.EncodeRune(r)
+			dst = e.AppendUint16(dst, uint16(r1))
+			dst = e.AppendUint16(dst, uint16(r2))
+			continue
+		}
+
+		dst = e.AppendUint16(dst, uint16(r))
+	}
+
+	return dst
+}
+
+// DecodeUTF16 decodes v as UTF-16 in byte order e.
+// If the length of v is odd, the last byte is ignored.
+func DecodeUTF16(v []byte, e Endianness) string {
+
+	s := make([]byte, 0, len(v))
+
+	for i := 0; i+1 < len(v); i += 2 {
+
+		r := rune(e.Uint16(v[i:]))
+
+		if utf16.IsSurrogate(r) && i+3 < len(v) {
+			if rr := utf16.DecodeRune(r, rune(e.Uint16(v[i+2:]))); rr != utf8.RuneError {
+				s = utf8.AppendRune(s, rr)
+				i += 2
+				continue
+			}
+		}
+
+		// An unpaired surrogate is not a valid rune, so it is appended as utf8.RuneError.
+		s = utf8.AppendRune(s, r)
+	}
+
+	return string(s)
+}
+
+// DetectUTF16BOM returns the byte order indicated by the byte order mark at the start of v.
+// The bool indicates whether v starts with a byte order mark.
+func DetectUTF16BOM(v []byte) (Endianness, bool) {
+
+	switch {
+	case len(v) < 2:
+		return BigEndian, false
+	case v[0] == 0xff && v[1] == 0xfe:
+		return LittleEndian, true
+	case v[0] == 0xfe && v[1] == 0xff:
+		return BigEndian, true
+	default:
+		return BigEndian, false
+	}
+}
+
+// indexUTF16NUL returns the index of the first NUL code unit in v, or -1.
+func indexUTF16NUL(v []byte) int {
+
+	for i := 0; i+1 < len(v); i += 2 {
+		if v[i] == 0 && v[i+1] == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// utf16CString truncates s before the first NUL byte.
+func utf16CString(s string) string {
+
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// ReadUTF16BOM removes the byte order mark from the start of b and returns the byte order indicated by it.
+// If b does not start with a byte order mark, b is not modified and def is returned.
+func ReadUTF16BOM(b *[]byte, def Endianness) Endianness {
+
+	e, ok := DetectUTF16BOM(*b)
+	if !ok {
+		return def
+	}
+
+	*b = (*b)[2:]
+
+	return e
+}
+
+// ReadUTF16 removes n UTF-16 code units in byte order e from the start of b and returns them as a string.
+// The bool indicates whether the read was successful.
+func ReadUTF16(b *[]byte, n int, e Endianness) (string, bool) {
+
+	v, err := ReadUTF16E(b, n, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16E is like ReadUTF16, but returns a *DecodeError if the read failed.
+func ReadUTF16E(b *[]byte, n int, e Endianness) (string, error) {
+
+	var v string
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadUTF16E(n, e)
+		return err
+	})
+
+	return v, err
+}
+
+// ReadUTF16CString removes a UTF-16 string in byte order e terminated by a NUL code unit from the start of b
+// and returns it without the NUL code unit.
+// The bool indicates whether the read was successful.
+func ReadUTF16CString(b *[]byte, e Endianness) (string, bool) {
+
+	v, err := ReadUTF16CStringE(b, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16CStringE is like ReadUTF16CString, but returns a *DecodeError if the read failed.
+func ReadUTF16CStringE(b *[]byte, e Endianness) (string, error) {
+
+	var v string
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadUTF16CStringE(e)
+		return err
+	})
+
+	return v, err
+}
+
+// ReadUTF16Vector removes the length of a UTF-16 string in code units then the string itself from the start of b.
+// The length type is depend on bitSize (see Buffer.ReadVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// The bool indicates whether the read was successful.
+func ReadUTF16Vector(b *[]byte, bitSize int, e Endianness) (string, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := ReadUTF16VectorE(b, bitSize, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16VectorE is like ReadUTF16Vector, but returns a *DecodeError if the read failed.
+// An invalid bitSize is reported with ErrInvalidBitSize instead of panicking.
+func ReadUTF16VectorE(b *[]byte, bitSize int, e Endianness) (string, error) {
+
+	var v string
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadUTF16VectorE(bitSize, e)
+		return err
+	})
+
+	return v, err
+}
+
+// ReadUTF16ByteVector removes the length of a UTF-16 string in bytes then the string itself from the start of b.
+// The length type is depend on bitSize (see Buffer.ReadVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// The bool indicates whether the read was successful.
+func ReadUTF16ByteVector(b *[]byte, bitSize int, e Endianness) (string, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := ReadUTF16ByteVectorE(b, bitSize, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16ByteVectorE is like ReadUTF16ByteVector, but returns a *DecodeError if the read failed.
+// An invalid bitSize is reported with ErrInvalidBitSize instead of panicking
+// and an odd length is reported with ErrInvalidLength.
+func ReadUTF16ByteVectorE(b *[]byte, bitSize int, e Endianness) (string, error) {
+
+	var v string
+
+	err := readSlice(b, false, func(buf *Buffer) (err error) {
+		v, err = buf.ReadUTF16ByteVectorE(bitSize, e)
+		return err
+	})
+
+	return v, err
+}
+
+// WriteUTF16BOM appends the byte order mark in byte order e at the end of b.
+func WriteUTF16BOM(b *[]byte, e Endianness) {
+	*b = e.AppendUint16(*b, utf16BOM)
+}
+
+// WriteUTF16 appends s encoded as UTF-16 in byte order e at the end of b.
+func WriteUTF16(b *[]byte, s string, e Endianness) {
+	*b = AppendUTF16(*b, s, e)
+}
+
+// WriteUTF16CString appends s encoded as UTF-16 in byte order e and a NUL code unit at the end of b.
+// If s contains a NUL byte, s is truncated before it, use WriteUTF16CStringE to detect it.
+func WriteUTF16CString(b *[]byte, s string, e Endianness) {
+	writeSlice(b, func(buf *Buffer) error {
+		buf.WriteUTF16CString(s, e)
+		return nil
+	})
+}
+
+// WriteUTF16CStringE is like WriteUTF16CString, but returns an *EncodeError wrapping ErrEmbeddedNUL instead of truncating s.
+// If an error is returned, b is not modified.
+func WriteUTF16CStringE(b *[]byte, s string, e Endianness) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteUTF16CStringE(s, e)
+	})
+}
+
+// WriteUTF16Vector appends the length of s in UTF-16 code units then s encoded as UTF-16 at the end of b.
+// The length type is depend on bitSize (see Buffer.WriteVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// If the length does not fit into the length type, it is truncated, use WriteUTF16VectorE to detect it.
+func WriteUTF16Vector(b *[]byte, s string, bitSize int, e Endianness) {
+	writeSlice(b, func(buf *Buffer) error {
+		buf.WriteUTF16Vector(s, bitSize, e)
+		return nil
+	})
+}
+
+// WriteUTF16VectorE is like WriteUTF16Vector, but returns an *EncodeError instead of truncating the length or panicking.
+// If an error is returned, b is not modified.
+func WriteUTF16VectorE(b *[]byte, s string, bitSize int, e Endianness) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteUTF16VectorE(s, bitSize, e)
+	})
+}
+
+// WriteUTF16ByteVector appends the length of s in bytes then s encoded as UTF-16 at the end of b.
+// The length type is depend on bitSize (see Buffer.WriteVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// If the length does not fit into the length type, it is truncated, use WriteUTF16ByteVectorE to detect it.
+func WriteUTF16ByteVector(b *[]byte, s string, bitSize int, e Endianness) {
+	writeSlice(b, func(buf *Buffer) error {
+		buf.WriteUTF16ByteVector(s, bitSize, e)
+		return nil
+	})
+}
+
+// WriteUTF16ByteVectorE is like WriteUTF16ByteVector, but returns an *EncodeError instead of truncating the length or panicking.
+// If an error is returned, b is not modified.
+func WriteUTF16ByteVectorE(b *[]byte, s string, bitSize int, e Endianness) error {
+	return writeSlice(b, func(buf *Buffer) error {
+		return buf.WriteUTF16ByteVectorE(s, bitSize, e)
+	})
+}
+
+// ReadUTF16BOM reads the byte order mark from b and returns the byte order indicated by it.
+// If the next bytes are not a byte order mark, the read cursor is not advanced and def is returned.
+func (b *Buffer) ReadUTF16BOM(def Endianness) Endianness {
+
+	e, ok := DetectUTF16BOM(b.b[b.off:])
+	if !ok {
+		return def
+	}
+
+	b.off += 2
+
+	return e
+}
+
+// ReadUTF16 reads n UTF-16 code units in byte order e from b and returns them as a string.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUTF16(n int, e Endianness) (string, bool) {
+
+	v, err := b.ReadUTF16E(n, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16E is like ReadUTF16, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUTF16E(n int, e Endianness) (string, error) {
+
+	if n > maxInt/2 {
+		return "", &DecodeError{Op: "ReadUTF16", Offset: b.base + b.off, Err: ErrLengthOverflow}
+	}
+
+	v, err := b.readE(2*n, "ReadUTF16")
+	if err != nil {
+		return "", err
+	}
+
+	return DecodeUTF16(v, e), nil
+}
+
+// ReadUTF16CString reads a UTF-16 string in byte order e terminated by a NUL code unit from b
+// and returns it without the NUL code unit.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUTF16CString(e Endianness) (string, bool) {
+
+	v, err := b.ReadUTF16CStringE(e)
+
+	return v, err == nil
+}
+
+// ReadUTF16CStringE is like ReadUTF16CString, but returns a *DecodeError if the read failed.
+// If there is no NUL code unit in the unread bytes, the error wraps ErrUnterminatedString.
+func (b *Buffer) ReadUTF16CStringE(e Endianness) (string, error) {
+
+	n := indexUTF16NUL(b.b[b.off:])
+	if n < 0 {
+		return "", &DecodeError{Op: "ReadUTF16CString", Offset: b.base + b.off, Err: ErrUnterminatedString}
+	}
+
+	v := b.b[b.off : b.off+n]
+	b.off += n + 2
+
+	return DecodeUTF16(v, e), nil
+}
+
+// readUTF16Vector reads a UTF-16 vector with the length in byte order e.
+// If units is true, the length is in code units, otherwise in bytes.
+// If the read failed, the read cursor is not advanced.
+func (b *Buffer) readUTF16Vector(bitSize int, e Endianness, units bool, op string) (string, error) {
+
+	off := b.off
+	little := b.little
+
+	b.little = e == LittleEndian
+	n, err := b.readLengthE(bitSize, op)
+	b.little = little
+
+	switch {
+	case err != nil:
+	case units && n > maxInt/2:
+		err = &DecodeError{Op: op, Offset: b.base + off, Err: ErrLengthOverflow}
+	case units:
+		n *= 2
+	case n%2 != 0:
+		err = &DecodeError{Op: op, Offset: b.base + off, Want: n, Err: ErrInvalidLength}
+	}
+
+	var v []byte
+
+	if err == nil {
+		v, err = b.readE(n, op)
+	}
+
+	if err != nil {
+		b.off = off
+		return "", err
+	}
+
+	return DecodeUTF16(v, e), nil
+}
+
+// ReadUTF16Vector reads the length of a UTF-16 string in code units then the string itself.
+// The length type is depend on bitSize (see ReadVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUTF16Vector(bitSize int, e Endianness) (string, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := b.ReadUTF16VectorE(bitSize, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16VectorE is like ReadUTF16Vector, but returns a *DecodeError if the read failed.
+// The errors are the same as of ReadVectorE.
+// If the read failed, the read cursor is not advanced.
+func (b *Buffer) ReadUTF16VectorE(bitSize int, e Endianness) (string, error) {
+	return b.readUTF16Vector(bitSize, e, true, "ReadUTF16Vector")
+}
+
+// ReadUTF16ByteVector reads the length of a UTF-16 string in bytes then the string itself.
+// The length type is depend on bitSize (see ReadVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUTF16ByteVector(bitSize int, e Endianness) (string, bool) {
+
+	if !validBitSize(bitSize) {
+		panic("invalid bitSize value")
+	}
+
+	v, err := b.ReadUTF16ByteVectorE(bitSize, e)
+
+	return v, err == nil
+}
+
+// ReadUTF16ByteVectorE is like ReadUTF16ByteVector, but returns a *DecodeError if the read failed.
+// The errors are the same as of ReadVectorE, an odd length is reported with ErrInvalidLength.
+// If the read failed, the read cursor is not advanced.
+func (b *Buffer) ReadUTF16ByteVectorE(bitSize int, e Endianness) (string, error) {
+	return b.readUTF16Vector(bitSize, e, false, "ReadUTF16ByteVector")
+}
+
+// WriteUTF16BOM appends the byte order mark in byte order e at the end of b.
+func (b *Buffer) WriteUTF16BOM(e Endianness) {
+	b.b = e.AppendUint16(b.b, utf16BOM)
+}
+
+// WriteUTF16 appends s encoded as UTF-16 in byte order e at the end of b.
+func (b *Buffer) WriteUTF16(s string, e Endianness) {
+	b.b = AppendUTF16(b.b, s, e)
+}
+
+// WriteUTF16CString appends s encoded as UTF-16 in byte order e and a NUL code unit at the end of b.
+// If s contains a NUL byte, s is truncated before it, use WriteUTF16CStringE to detect it.
+func (b *Buffer) WriteUTF16CString(s string, e Endianness) {
+
+	b.b = AppendUTF16(b.b, utf16CString(s), e)
+	b.b = append(b.b, 0, 0)
+}
+
+// WriteUTF16CStringE is like WriteUTF16CString, but returns an *EncodeError wrapping ErrEmbeddedNUL instead of truncating s.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteUTF16CStringE(s string, e Endianness) error {
+
+	if strings.IndexByte(s, 0) >= 0 {
+		return &EncodeError{Op: "WriteUTF16CString", Length: len(s), Err: ErrEmbeddedNUL}
+	}
+
+	b.WriteUTF16CString(s, e)
+
+	return nil
+}
+
+// writeUTF16Vector appends the length n in byte order e then v.
+func (b *Buffer) writeUTF16Vector(v []byte, n int, bitSize int, e Endianness) {
+
+	little := b.little
+
+	b.little = e == LittleEndian
+	b.writeLength(n, bitSize)
+	b.little = little
+
+	b.b = append(b.b, v...)
+}
+
+// WriteUTF16Vector appends the length of s in UTF-16 code units then s encoded as UTF-16 at the end of b.
+// The length type is depend on bitSize (see WriteVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// If the length does not fit into the length type, it is truncated, use WriteUTF16VectorE to detect it.
+func (b *Buffer) WriteUTF16Vector(s string, bitSize int, e Endianness) {
+
+	v := AppendUTF16(nil, s, e)
+
+	b.writeUTF16Vector(v, len(v)/2, bitSize, e)
+}
+
+// WriteUTF16VectorE is like WriteUTF16Vector, but returns an *EncodeError instead of truncating the length or panicking.
+// The errors are the same as of WriteVectorE.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteUTF16VectorE(s string, bitSize int, e Endianness) error {
+
+	v := AppendUTF16(nil, s, e)
+
+	if err := checkVectorLength(len(v)/2, bitSize); err != nil {
+		return &EncodeError{Op: "WriteUTF16Vector", Length: len(v) / 2, Err: err}
+	}
+
+	b.writeUTF16Vector(v, len(v)/2, bitSize, e)
+
+	return nil
+}
+
+// WriteUTF16ByteVector appends the length of s in bytes then s encoded as UTF-16 at the end of b.
+// The length type is depend on bitSize (see WriteVector), the length and the code units are in byte order e.
+// If bitSize is an invalid number, this function panics.
+// If the length does not fit into the length type, it is truncated, use WriteUTF16ByteVectorE to detect it.
+func (b *Buffer) WriteUTF16ByteVector(s string, bitSize int, e Endianness) {
+
+	v := AppendUTF16(nil, s, e)
+
+	b.writeUTF16Vector(v, len(v), bitSize, e)
+}
+
+// WriteUTF16ByteVectorE is like WriteUTF16ByteVector, but returns an *EncodeError instead of truncating the length or panicking.
+// The errors are the same as of WriteVectorE.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteUTF16ByteVectorE(s string, bitSize int, e Endianness) error {
+
+	v := AppendUTF16(nil, s, e)
+
+	if err := checkVectorLength(len(v), bitSize); err != nil {
+		return &EncodeError{Op: "WriteUTF16ByteVector", Length: len(v), Err: err}
+	}
+
+	b.writeUTF16Vector(v, len(v), bitSize, e)
+
+	return nil
+}
+
+// ReadUTF16 reads n UTF-16 code units in byte order e and returns them as a string.
+func (d *Decoder) ReadUTF16(n int, e Endianness) string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadUTF16E(n, e)
+	*d.err = err
+
+	return v
+}
+
+// ReadUTF16CString reads a UTF-16 string in byte order e terminated by a NUL code unit
+// and returns it without the NUL code unit.
+func (d *Decoder) ReadUTF16CString(e Endianness) string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadUTF16CStringE(e)
+	*d.err = err
+
+	return v
+}
+
+// ReadUTF16Vector reads the length of a UTF-16 string in code units then the string itself.
+// The length type is depend on bitSize (see ReadVector), the length and the code units are in byte order e.
+func (d *Decoder) ReadUTF16Vector(bitSize int, e Endianness) string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadUTF16VectorE(bitSize, e)
+	*d.err = err
+
+	return v
+}
+
+// ReadUTF16ByteVector reads the length of a UTF-16 string in bytes then the string itself.
+// The length type is depend on bitSize (see ReadVector), the length and the code units are in byte order e.
+func (d *Decoder) ReadUTF16ByteVector(bitSize int, e Endianness) string {
+
+	if *d.err != nil {
+		return ""
+	}
+
+	v, err := d.b.ReadUTF16ByteVectorE(bitSize, e)
+	*d.err = err
+
+	return v
+}
+
+// ReadUTF16 reads n UTF-16 code units in byte order e from s and returns them as a string.
+func (s *StreamReader) ReadUTF16(n int, e Endianness) (string, error) {
+
+	if n > maxInt/2 {
+		return "", &DecodeError{Op: "ReadUTF16", Offset: int(s.n), Err: ErrLengthOverflow}
+	}
+
+	v, err := s.ReadBytes(2 * n)
+	if err != nil {
+		return "", withOp(err, "ReadUTF16")
+	}
+
+	return DecodeUTF16(v, e), nil
+}
+
+// ReadUTF16CString reads a UTF-16 string in byte order e terminated by a NUL code unit from s
+// and returns it without the NUL code unit.
+// The string is read code unit by code unit, so s should wrap a buffered reader (eg.: bufio.Reader).
+// The length of the string in bytes is limited by MaxVectorLength.
+// If the end of the stream is reached before the NUL code unit, io.ErrUnexpectedEOF is returned.
+func (s *StreamReader) ReadUTF16CString(e Endianness) (string, error) {
+
+	off := s.n
+
+	var v []byte
+
+	for {
+
+		// A truncated string or code unit is reported at the start of the string.
+		c, err := s.read(2, "ReadUTF16CString")
+		if (err == io.EOF && s.n > off) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return "", &DecodeError{Op: "ReadUTF16CString", Offset: int(off), Have: len(v), Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if c[0] == 0 && c[1] == 0 {
+			break
+		}
+
+		if err := s.alloc.limits.checkVector(uint64(len(v) + 2)); err != nil {
+			return "", &DecodeError{Op: "ReadUTF16CString", Offset: int(off), Err: err}
+		}
+
+		v = append(v, c...)
+	}
+
+	if err := s.alloc.alloc(len(v)); err != nil {
+		return "", &DecodeError{Op: "ReadUTF16CString", Offset: int(off), Err: err}
+	}
+
+	return DecodeUTF16(v, e), nil
+}
+
+// readUTF16Vector reads a UTF-16 vector with the length in byte order e.
+// If units is true, the length is in code units, otherwise in bytes.
+func (s *StreamReader) readUTF16Vector(bitSize int, e Endianness, units bool, op string) (string, error) {
+
+	off := s.n
+	little := s.little
+
+	s.little = e == LittleEndian
+	n, err := s.readLength(bitSize, op)
+	s.little = little
+
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case units && n > maxInt/2:
+		return "", &DecodeError{Op: op, Offset: int(off), Err: ErrLengthOverflow}
+	case units:
+		n *= 2
+	case n%2 != 0:
+		return "", &DecodeError{Op: op, Offset: int(off), Want: n, Err: ErrInvalidLength}
+	}
+
+	v, err := s.ReadBytes(n)
+	if err == io.EOF {
+		err = &DecodeError{Op: op, Offset: int(s.n), Want: n, Err: io.ErrUnexpectedEOF}
+	}
+	if err != nil {
+		return "", withOp(err, op)
+	}
+
+	return DecodeUTF16(v, e), nil
+}
+
+// ReadUTF16Vector reads the length of a UTF-16 string in code units then the string itself from s.
+// The length type is depend on bitSize (see ReadVector), the length and the code units are in byte order e.
+func (s *StreamReader) ReadUTF16Vector(bitSize int, e Endianness) (string, error) {
+	return s.readUTF16Vector(bitSize, e, true, "ReadUTF16Vector")
+}
+
+// ReadUTF16ByteVector reads the length of a UTF-16 string in bytes then the string itself from s.
+// The length type is depend on bitSize (see ReadVector), the length and the code units are in byte order e.
+// An odd length is reported with ErrInvalidLength.
+func (s *StreamReader) ReadUTF16ByteVector(bitSize int, e Endianness) (string, error) {
+	return s.readUTF16Vector(bitSize, e, false, "ReadUTF16ByteVector")
+}
+
+// WriteUTF16BOM writes the byte order mark in byte order e to s.
+func (s *StreamWriter) WriteUTF16BOM(e Endianness) {
+
+	if s.err != nil {
+		return
+	}
+
+	s.buf = e.AppendUint16(s.buf, utf16BOM)
+	s.advance(2)
+}
+
+// WriteUTF16 writes str encoded as UTF-16 in byte order e to s.
+func (s *StreamWriter) WriteUTF16(str string, e Endianness) {
+
+	if s.err != nil {
+		return
+	}
+
+	n := len(s.buf)
+	s.buf = AppendUTF16(s.buf, str, e)
+	s.advance(len(s.buf) - n)
+}
+
+// WriteUTF16CString writes str encoded as UTF-16 in byte order e and a NUL code unit to s.
+// If str contains a NUL byte, an *EncodeError wrapping ErrEmbeddedNUL is recorded.
+func (s *StreamWriter) WriteUTF16CString(str string, e Endianness) {
+
+	if s.err != nil {
+		return
+	}
+
+	if strings.IndexByte(str, 0) >= 0 {
+		s.err = &EncodeError{Op: "WriteUTF16CString", Length: len(str), Err: ErrEmbeddedNUL}
+		return
+	}
+
+	s.WriteUTF16(str, e)
+	s.WriteBytes(0, 0)
+}
+
+// writeUTF16Vector writes the length n in byte order e then v.
+// If bitSize is invalid or n does not fit into the length type, an *EncodeError is recorded.
+func (s *StreamWriter) writeUTF16Vector(v []byte, n int, bitSize int, e Endianness, op string) {
+
+	if s.err != nil {
+		return
+	}
+
+	if err := checkVectorLength(n, bitSize); err != nil {
+		s.err = &EncodeError{Op: op, Length: n, Err: err}
+		return
+	}
+
+	little := s.little
+
+	s.little = e == LittleEndian
+	s.writeLength(n, bitSize)
+	s.little = little
+
+	s.WriteBytes(v...)
+}
+
+// WriteUTF16Vector writes the length of str in UTF-16 code units then str encoded as UTF-16 to s.
+// The length type is depend on bitSize (see WriteVector), the length and the code units are in byte order e.
+// If bitSize is invalid or the length does not fit into the length type, an *EncodeError is recorded.
+func (s *StreamWriter) WriteUTF16Vector(str string, bitSize int, e Endianness) {
+
+	v := AppendUTF16(nil, str, e)
+
+	s.writeUTF16Vector(v, len(v)/2, bitSize, e, "WriteUTF16Vector")
+}
+
+// WriteUTF16ByteVector writes the length of str in bytes then str encoded as UTF-16 to s.
+// The length type is depend on bitSize (see WriteVector), the length and the code units are in byte order e.
+// If bitSize is invalid or the length does not fit into the length type, an *EncodeError is recorded.
+func (s *StreamWriter) WriteUTF16ByteVector(str string, bitSize int, e Endianness) {
+
+	v := AppendUTF16(nil, str, e)
+
+	s.writeUTF16Vector(v, len(v), bitSize, e, "WriteUTF16ByteVector")
+}
@@ -0,0 +1,478 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUTF16RoundTrip(t *testing.T) {
+
+	tests := []string{"", "abc", "héllo", "日本語", "\U0001d11e clef", "\U0010ffff"}
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		for _, s := range tests {
+
+			other := LittleEndian
+			if e == LittleEndian {
+				other = BigEndian
+			}
+
+			// The byte order of the Buffer is the opposite of e, it must not be used.
+			b := NewEmpty()
+			b.SetEndianness(other)
+
+			b.WriteUTF16BOM(e)
+			b.WriteUTF16(s, e)
+			b.WriteUTF16CString(s, e)
+			b.WriteUTF16Vector(s, 16, e)
+			b.WriteUTF16ByteVector(s, VarintLength, e)
+
+			units := len(AppendUTF16(nil, s, e)) / 2
+
+			if got := b.ReadUTF16BOM(other); got != e {
+				t.Fatalf("%v %q: ReadUTF16BOM = %v", e, s, got)
+			}
+
+			if v, ok := b.ReadUTF16(units, e); !ok || v != s {
+				t.Fatalf("%v %q: ReadUTF16 = %q, %v", e, s, v, ok)
+			}
+
+			if v, ok := b.ReadUTF16CString(e); !ok || v != s {
+				t.Fatalf("%v %q: ReadUTF16CString = %q, %v", e, s, v, ok)
+			}
+
+			if v, ok := b.ReadUTF16Vector(16, e); !ok || v != s {
+				t.Fatalf("%v %q: ReadUTF16Vector = %q, %v", e, s, v, ok)
+			}
+
+			if v, ok := b.ReadUTF16ByteVector(VarintLength, e); !ok || v != s {
+				t.Fatalf("%v %q: ReadUTF16ByteVector = %q, %v", e, s, v, ok)
+			}
+
+			if !b.Empty() {
+				t.Fatalf("%v %q: %d bytes left", e, s, b.Remaining())
+			}
+		}
+	}
+}
+
+func TestDecodeUTF16(t *testing.T) {
+
+	tests := []struct {
+		name string
+		v    []byte
+		want string
+	}{
+		{"pair", []byte{0xd8, 0x34, 0xdd, 0x1e}, "\U0001d11e"},
+		{"lone high at the end", []byte{0x00, 'a', 0xd8, 0x34}, "a\ufffd"},
+		{"lone high before a character", []byte{0xd8, 0x34, 0x00, 'a'}, "\ufffda"},
+		{"lone low", []byte{0xdd, 0x1e, 0x00, 'a'}, "\ufffda"},
+		{"reversed pair", []byte{0xdd, 0x1e, 0xd8, 0x34}, "\ufffd\ufffd"},
+		{"two high", []byte{0xd8, 0x34, 0xd8, 0x34, 0xdd, 0x1e}, "\ufffd\U0001d11e"},
+		{"odd length", []byte{0x00, 'a', 0x00}, "a"},
+		{"BOM is kept", []byte{0xfe, 0xff, 0x00, 'a'}, "\ufeffa"},
+		{"NUL is kept", []byte{0x00, 0x00, 0x00, 'a'}, "\x00a"},
+	}
+
+	for _, tt := range tests {
+
+		if got := DecodeUTF16(tt.v, BigEndian); got != tt.want {
+			t.Fatalf("%s: DecodeUTF16 = %q, want %q", tt.name, got, tt.want)
+		}
+
+		// The same in little-endian order.
+		v := make([]byte, len(tt.v))
+		for i := 0; i+1 < len(v); i += 2 {
+			v[i], v[i+1] = tt.v[i+1], tt.v[i]
+		}
+
+		if got := DecodeUTF16(v, LittleEndian); got != tt.want {
+			t.Fatalf("%s: little-endian DecodeUTF16 = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Invalid UTF-8 is encoded as utf8.RuneError.
+	if got := AppendUTF16(nil, "a\xff", BigEndian); !bytes.Equal(got, []byte{0x00, 'a', 0xff, 0xfd}) {
+		t.Fatalf("AppendUTF16 = % x", got)
+	}
+}
+
+func TestUTF16BOM(t *testing.T) {
+
+	tests := []struct {
+		v  []byte
+		e  Endianness
+		ok bool
+	}{
+		{[]byte{0xfe, 0xff, 0x00, 'a'}, BigEndian, true},
+		{[]byte{0xff, 0xfe, 'a', 0x00}, LittleEndian, true},
+		{[]byte{0xff}, BigEndian, false},
+		{[]byte{0x00, 'a'}, BigEndian, false},
+		{nil, BigEndian, false},
+	}
+
+	for _, tt := range tests {
+
+		if e, ok := DetectUTF16BOM(tt.v); e != tt.e || ok != tt.ok {
+			t.Fatalf("DetectUTF16BOM(% x) = %v, %v", tt.v, e, ok)
+		}
+
+		// Without a byte order mark, the default is returned and the input is not consumed.
+		v := tt.v
+		want := tt.e
+		if !tt.ok {
+			want = LittleEndian
+		}
+
+		if e := ReadUTF16BOM(&v, LittleEndian); e != want || (len(v) == len(tt.v)) == tt.ok {
+			t.Fatalf("ReadUTF16BOM(% x) = %v, % x left", tt.v, e, v)
+		}
+
+		b := NewBuffer(tt.v)
+
+		if e := b.ReadUTF16BOM(LittleEndian); e != want || (b.Offset() == 0) == tt.ok {
+			t.Fatalf("Buffer.ReadUTF16BOM(% x) = %v, offset %d", tt.v, e, b.Offset())
+		}
+	}
+}
+
+func TestReadUTF16Errors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		read   func(b *Buffer) error
+		op     string
+		offset int
+		err    error
+	}{
+		{"UTF16/short", []byte{0x00, 'a', 0x00}, readUTF16(2), "ReadUTF16", 1, ErrShortBuffer},
+		{"UTF16/overflow", nil, readUTF16(maxInt/2 + 1), "ReadUTF16", 1, ErrLengthOverflow},
+		{"CString/unterminated", []byte{0x00, 'a'}, readUTF16CString, "ReadUTF16CString", 1, ErrUnterminatedString},
+		{"CString/unaligned NUL", []byte{'a', 0x00, 0x00, 'b'}, readUTF16CString, "ReadUTF16CString", 1, ErrUnterminatedString},
+		{"Vector/short", []byte{0x00, 0x02, 0x00, 'a'}, readUTF16Vector(16), "ReadUTF16Vector", 3, ErrShortBuffer},
+		{"Vector/bitSize", []byte{0x00}, readUTF16Vector(12), "ReadUTF16Vector", 1, ErrInvalidBitSize},
+		{"ByteVector/odd", []byte{0x03, 0x00, 'a', 0x00}, readUTF16ByteVector(8), "ReadUTF16ByteVector", 1, ErrInvalidLength},
+		{"ByteVector/short", []byte{0x04, 0x00, 'a'}, readUTF16ByteVector(8), "ReadUTF16ByteVector", 2, ErrShortBuffer},
+	}
+
+	for _, tt := range tests {
+
+		// The string is read from a child, so the offsets include its base.
+		p := NewBuffer(append([]byte{byte(len(tt.data))}, tt.data...))
+
+		var b Buffer
+
+		if err := p.ReadLengthPrefixed(8, &b); err != nil {
+			t.Fatalf("%s: ReadLengthPrefixed: %s", tt.name, err)
+		}
+
+		err := tt.read(&b)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+
+		if b.Offset() != 0 {
+			t.Fatalf("%s: read cursor advanced to %d", tt.name, b.Offset())
+		}
+	}
+}
+
+func readUTF16(n int) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := b.ReadUTF16E(n, BigEndian)
+		return err
+	}
+}
+
+func readUTF16CString(b *Buffer) error {
+	_, err := b.ReadUTF16CStringE(BigEndian)
+	return err
+}
+
+func readUTF16Vector(bitSize int) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := b.ReadUTF16VectorE(bitSize, BigEndian)
+		return err
+	}
+}
+
+func readUTF16ByteVector(bitSize int) func(b *Buffer) error {
+	return func(b *Buffer) error {
+		_, err := b.ReadUTF16ByteVectorE(bitSize, BigEndian)
+		return err
+	}
+}
+
+func TestWriteUTF16Errors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		write  func(b *Buffer) error
+		op     string
+		length int
+		err    error
+	}{
+		{"CString/embedded NUL", func(b *Buffer) error { return b.WriteUTF16CStringE("a\x00", BigEndian) }, "WriteUTF16CString", 2, ErrEmbeddedNUL},
+		{"Vector/overflow", func(b *Buffer) error { return b.WriteUTF16VectorE(strings.Repeat("a", 256), 8, BigEndian) }, "WriteUTF16Vector", 256, ErrLengthOverflow},
+		{"Vector/surrogates", func(b *Buffer) error { return b.WriteUTF16VectorE(strings.Repeat("\U0001d11e", 128), 8, BigEndian) }, "WriteUTF16Vector", 256, ErrLengthOverflow},
+		{"ByteVector/overflow", func(b *Buffer) error { return b.WriteUTF16ByteVectorE(strings.Repeat("a", 128), 8, BigEndian) }, "WriteUTF16ByteVector", 256, ErrLengthOverflow},
+		{"ByteVector/bitSize", func(b *Buffer) error { return b.WriteUTF16ByteVectorE("a", 12, BigEndian) }, "WriteUTF16ByteVector", 2, ErrInvalidBitSize},
+	}
+
+	for _, tt := range tests {
+
+		b := NewBuffer([]byte{0x01})
+
+		err := tt.write(&b)
+
+		var ee *EncodeError
+
+		if !errors.As(err, &ee) || ee.Op != tt.op || ee.Length != tt.length || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s with length %d: %v", tt.name, err, tt.op, tt.length, tt.err)
+		}
+
+		if !bytes.Equal(b.Bytes(), []byte{0x01}) {
+			t.Fatalf("%s: b modified to % x", tt.name, b.Bytes())
+		}
+	}
+}
+
+func TestUTF16Slice(t *testing.T) {
+
+	var b []byte
+
+	WriteUTF16BOM(&b, LittleEndian)
+	WriteUTF16(&b, "ab", LittleEndian)
+	WriteUTF16CString(&b, "c\x00d", LittleEndian)
+	WriteUTF16Vector(&b, "\U0001d11e", 8, LittleEndian)
+	WriteUTF16ByteVector(&b, "é", 16, LittleEndian)
+
+	if err := WriteUTF16CStringE(&b, "\x00", LittleEndian); !errors.Is(err, ErrEmbeddedNUL) {
+		t.Fatalf("WriteUTF16CStringE = %v, want ErrEmbeddedNUL", err)
+	}
+
+	e := ReadUTF16BOM(&b, BigEndian)
+
+	if v, ok := ReadUTF16(&b, 2, e); !ok || v != "ab" {
+		t.Fatalf("ReadUTF16 = %q, %v", v, ok)
+	}
+
+	// WriteUTF16CString truncates before the NUL byte.
+	if v, ok := ReadUTF16CString(&b, e); !ok || v != "c" {
+		t.Fatalf("ReadUTF16CString = %q, %v", v, ok)
+	}
+
+	if v, ok := ReadUTF16Vector(&b, 8, e); !ok || v != "\U0001d11e" {
+		t.Fatalf("ReadUTF16Vector = %q, %v", v, ok)
+	}
+
+	if v, ok := ReadUTF16ByteVector(&b, 16, e); !ok || v != "é" {
+		t.Fatalf("ReadUTF16ByteVector = %q, %v", v, ok)
+	}
+
+	if len(b) != 0 {
+		t.Fatalf("%d bytes left", len(b))
+	}
+
+	// The failed reads do not modify b and report offset -1.
+	b = []byte{0x03, 0x00, 'a'}
+
+	var de *DecodeError
+
+	if _, err := ReadUTF16ByteVectorE(&b, 8, BigEndian); !errors.As(err, &de) || de.Offset != -1 || !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("ReadUTF16ByteVectorE = %v, want ErrInvalidLength at offset -1", err)
+	}
+
+	if len(b) != 3 {
+		t.Fatalf("b modified to % x", b)
+	}
+}
+
+func TestDecoderUTF16(t *testing.T) {
+
+	b := NewBuffer([]byte{0x00, 'a', 0x00, 'b', 0x00, 0x00, 0x01, 0x00, 'c', 0x04, 0x00, 'd', 0x00})
+	d := NewDecoder(&b)
+
+	if v := d.ReadUTF16(1, BigEndian); v != "a" {
+		t.Fatalf("ReadUTF16 = %q", v)
+	}
+
+	if v := d.ReadUTF16CString(BigEndian); v != "b" {
+		t.Fatalf("ReadUTF16CString = %q", v)
+	}
+
+	if v := d.ReadUTF16Vector(8, BigEndian); v != "c" {
+		t.Fatalf("ReadUTF16Vector = %q", v)
+	}
+
+	if v := d.ReadUTF16ByteVector(8, BigEndian); v != "" || !errors.Is(d.Err(), ErrShortBuffer) {
+		t.Fatalf("ReadUTF16ByteVector = %q, %v, want ErrShortBuffer", v, d.Err())
+	}
+
+	// The error is sticky.
+	if v := d.ReadUTF16(0, BigEndian); v != "" || !errors.Is(d.Err(), ErrShortBuffer) {
+		t.Fatalf("ReadUTF16 after error = %q, %v", v, d.Err())
+	}
+}
+
+func TestStreamUTF16(t *testing.T) {
+
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+
+		s := "héllo \U0001d11e"
+
+		var w bytes.Buffer
+
+		sw := NewStreamWriter(&w)
+		sw.WriteUTF16BOM(e)
+		sw.WriteUTF16(s, e)
+		sw.WriteUTF16CString(s, e)
+		sw.WriteUTF16Vector(s, 16, e)
+		sw.WriteUTF16ByteVector(s, QUICVarintLength, e)
+
+		if err := sw.Flush(); err != nil {
+			t.Fatalf("%v: Flush: %s", e, err)
+		}
+
+		// The stream encoding is the same as of Buffer.
+		b := NewEmpty()
+		b.WriteUTF16BOM(e)
+		b.WriteUTF16(s, e)
+		b.WriteUTF16CString(s, e)
+		b.WriteUTF16Vector(s, 16, e)
+		b.WriteUTF16ByteVector(s, QUICVarintLength, e)
+
+		if !bytes.Equal(w.Bytes(), b.Bytes()) {
+			t.Fatalf("%v: wrote % x, want % x", e, w.Bytes(), b.Bytes())
+		}
+
+		r := NewStreamReader(&w)
+
+		if _, err := r.ReadBytes(2); err != nil {
+			t.Fatalf("%v: ReadBytes: %s", e, err)
+		}
+
+		if v, err := r.ReadUTF16(8, e); err != nil || v != s {
+			t.Fatalf("%v: ReadUTF16 = %q, %v", e, v, err)
+		}
+
+		if v, err := r.ReadUTF16CString(e); err != nil || v != s {
+			t.Fatalf("%v: ReadUTF16CString = %q, %v", e, v, err)
+		}
+
+		if v, err := r.ReadUTF16Vector(16, e); err != nil || v != s {
+			t.Fatalf("%v: ReadUTF16Vector = %q, %v", e, v, err)
+		}
+
+		if v, err := r.ReadUTF16ByteVector(QUICVarintLength, e); err != nil || v != s {
+			t.Fatalf("%v: ReadUTF16ByteVector = %q, %v", e, v, err)
+		}
+	}
+}
+
+func TestStreamReadUTF16Errors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		limits Limits
+		read   func(s *StreamReader) error
+		op     string
+		offset int
+		err    error
+	}{
+		{"UTF16/short", []byte{'x', 0x00, 'a', 0x00}, Limits{}, streamUTF16(2), "ReadUTF16", 1, io.ErrUnexpectedEOF},
+		{"CString/unterminated", []byte{'x', 0x00, 'a'}, Limits{}, streamUTF16CString, "ReadUTF16CString", 1, io.ErrUnexpectedEOF},
+		{"CString/odd", []byte{'x', 0x00, 'a', 0x00}, Limits{}, streamUTF16CString, "ReadUTF16CString", 1, io.ErrUnexpectedEOF},
+		{"CString/limit", []byte{'x', 0x00, 'a', 0x00, 'b', 0x00, 0x00}, Limits{MaxVectorLength: 3}, streamUTF16CString, "ReadUTF16CString", 1, ErrLimitExceeded},
+		{"Vector/short", []byte{'x', 0x02, 0x00, 'a'}, Limits{}, streamUTF16Vector(8), "ReadUTF16Vector", 2, io.ErrUnexpectedEOF},
+		{"Vector/bitSize", []byte{'x', 0x00}, Limits{}, streamUTF16Vector(12), "ReadUTF16Vector", 1, ErrInvalidBitSize},
+		{"ByteVector/odd", []byte{'x', 0x03, 0x00, 'a', 0x00}, Limits{}, streamUTF16ByteVector(8), "ReadUTF16ByteVector", 1, ErrInvalidLength},
+		{"ByteVector/limit", []byte{'x', 0x04, 0x00, 'a', 0x00, 'b'}, Limits{MaxVectorLength: 2}, streamUTF16ByteVector(8), "ReadUTF16ByteVector", 1, ErrLimitExceeded},
+	}
+
+	for _, tt := range tests {
+
+		s := NewStreamReader(bytes.NewReader(tt.data))
+		s.SetLimits(tt.limits)
+
+		if _, err := s.ReadUint8(); err != nil {
+			t.Fatalf("%s: ReadUint8: %s", tt.name, err)
+		}
+
+		err := tt.read(s)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) || de.Op != tt.op || de.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s at offset %d: %v", tt.name, err, tt.op, tt.offset, tt.err)
+		}
+	}
+}
+
+func streamUTF16(n int) func(s *StreamReader) error {
+	return func(s *StreamReader) error {
+		_, err := s.ReadUTF16(n, BigEndian)
+		return err
+	}
+}
+
+func streamUTF16CString(s *StreamReader) error {
+	_, err := s.ReadUTF16CString(BigEndian)
+	return err
+}
+
+func streamUTF16Vector(bitSize int) func(s *StreamReader) error {
+	return func(s *StreamReader) error {
+		_, err := s.ReadUTF16Vector(bitSize, BigEndian)
+		return err
+	}
+}
+
+func streamUTF16ByteVector(bitSize int) func(s *StreamReader) error {
+	return func(s *StreamReader) error {
+		_, err := s.ReadUTF16ByteVector(bitSize, BigEndian)
+		return err
+	}
+}
+
+func TestStreamWriteUTF16Errors(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		write func(s *StreamWriter)
+		op    string
+		err   error
+	}{
+		{"CString/embedded NUL", func(s *StreamWriter) { s.WriteUTF16CString("a\x00", BigEndian) }, "WriteUTF16CString", ErrEmbeddedNUL},
+		{"Vector/overflow", func(s *StreamWriter) { s.WriteUTF16Vector(strings.Repeat("a", 256), 8, BigEndian) }, "WriteUTF16Vector", ErrLengthOverflow},
+		{"ByteVector/bitSize", func(s *StreamWriter) { s.WriteUTF16ByteVector("a", 12, BigEndian) }, "WriteUTF16ByteVector", ErrInvalidBitSize},
+	}
+
+	for _, tt := range tests {
+
+		var w bytes.Buffer
+
+		s := NewStreamWriter(&w)
+		tt.write(s)
+		s.WriteUTF16("ok", BigEndian)
+
+		var ee *EncodeError
+
+		if err := s.Flush(); !errors.As(err, &ee) || ee.Op != tt.op || !errors.Is(err, tt.err) {
+			t.Fatalf("%s: error = %v, want %s: %v", tt.name, err, tt.op, tt.err)
+		}
+
+		if w.Len() != 0 {
+			t.Fatalf("%s: wrote % x after the error", tt.name, w.Bytes())
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package bytebuilder
+
+import (
+	"errors"
+	"io"
+)
+
+// MaxVarintLen64 is the maximum length of a LEB128 encoded 64-bit integer.
+const MaxVarintLen64 = 10
+
+// VarintLength can be used as the bitSize of the vector functions (eg.: Buffer.WriteVector, Buffer.ReadVector)
+// to encode the length as an unsigned LEB128 varint.
+const VarintLength = -1
+
+var (
+	// ErrVarintOverflow is returned when a varint does not fit into 64 bits.
+	ErrVarintOverflow = errors.New("varint overflows a 64-bit integer")
+
+	// ErrVarintOverlong is returned by the strict reads when a varint is not encoded in the minimal number of bytes.
+	ErrVarintOverlong = errors.New("varint is not minimally encoded")
+)
+
+// ZigZagEncode maps a signed integer to an unsigned integer,
+// so small absolute values get small encodings (0, -1, 1, -2, 2, ... -> 0, 1, 2, 3, 4, ...).
+func ZigZagEncode(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// ZigZagDecode is the inverse of ZigZagEncode.
+func ZigZagDecode(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// AppendUvarint appends the unsigned LEB128 encoding of v to b and returns the extended slice.
+func AppendUvarint(b []byte, v uint64) []byte {
+
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+// UvarintLen returns the number of bytes of the unsigned LEB128 encoding of v.
+func UvarintLen(v uint64) int {
+
+	n := 1
+
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+
+	return n
+}
+
+// decodeUvarint decodes an unsigned LEB128 varint from the start of b.
+// Padded encodings (trailing 0x80 groups ended by 0x00) are accepted up to MaxVarintLen64 bytes,
+// unless strict is true.
+// Returns the value, the number of bytes used and
+// ErrShortBuffer, ErrVarintOverflow or ErrVarintOverlong if the decoding failed.
+func decodeUvarint(b []byte, strict bool) (uint64, int, error) {
+
+	var (
+		v     uint64
+		shift uint
+	)
+
+	for i, c := range b {
+
+		if i == MaxVarintLen64-1 && c > 1 {
+			return 0, 0, ErrVarintOverflow
+		}
+
+		v |= uint64(c&0x7f) << shift
+
+		if c < 0x80 {
+			if strict && c == 0 && i > 0 {
+				return 0, 0, ErrVarintOverlong
+			}
+			return v, i + 1, nil
+		}
+
+		shift += 7
+	}
+
+	return 0, 0, ErrShortBuffer
+}
+
+// ReadUvarint removes an unsigned LEB128 varint from the start of b and returns it.
+// Padded encodings are accepted up to MaxVarintLen64 bytes (eg.: WebAssembly, DWARF),
+// values that overflow 64 bits are rejected.
+// The bool indicates whether the read was successful.
+func ReadUvarint(b *[]byte) (uint64, bool) {
+
+	v, err := ReadUvarintE(b)
+
+	return v, err == nil
+}
+
+// ReadUvarintE is like ReadUvarint, but returns a *DecodeError if the read failed.
+func ReadUvarintE(b *[]byte) (uint64, error) {
+	return readUvarintSlice(b, false, "ReadUvarint")
+}
+
+// ReadUvarintStrict is like ReadUvarint, but rejects the non-minimal encodings.
+// The bool indicates whether the read was successful.
+func ReadUvarintStrict(b *[]byte) (uint64, bool) {
+
+	v, err := ReadUvarintStrictE(b)
+
+	return v, err == nil
+}
+
+// ReadUvarintStrictE is like ReadUvarintStrict, but returns a *DecodeError if the read failed.
+func ReadUvarintStrictE(b *[]byte) (uint64, error) {
+	return readUvarintSlice(b, true, "ReadUvarintStrict")
+}
+
+// ReadVarint removes a zigzag encoded signed LEB128 varint from the start of b and returns it.
+// Padded encodings are accepted up to MaxVarintLen64 bytes.
+// The bool indicates whether the read was successful.
+func ReadVarint(b *[]byte) (int64, bool) {
+
+	v, err := ReadVarintE(b)
+
+	return v, err == nil
+}
+
+// ReadVarintE is like ReadVarint, but returns a *DecodeError if the read failed.
+func ReadVarintE(b *[]byte) (int64, error) {
+
+	v, err := readUvarintSlice(b, false, "ReadVarint")
+
+	return ZigZagDecode(v), err
+}
+
+// ReadVarintStrict is like ReadVarint, but rejects the non-minimal encodings.
+// The bool indicates whether the read was successful.
+func ReadVarintStrict(b *[]byte) (int64, bool) {
+
+	v, err := ReadVarintStrictE(b)
+
+	return v, err == nil
+}
+
+// ReadVarintStrictE is like ReadVarintStrict, but returns a *DecodeError if the read failed.
+func ReadVarintStrictE(b *[]byte) (int64, error) {
+
+	v, err := readUvarintSlice(b, true, "ReadVarintStrict")
+
+	return ZigZagDecode(v), err
+}
+
+// readUvarintSlice removes an unsigned LEB128 varint from the start of b.
+func readUvarintSlice(b *[]byte, strict bool, op string) (uint64, error) {
+
+	v, n, err := decodeUvarint(*b, strict)
+	if err != nil {
+		return 0, varintError(op, -1, *b, err)
+	}
+
+	*b = (*b)[n:]
+
+	return v, nil
+}
+
+// WriteUvarint appends v at the end of b as an unsigned LEB128 varint.
+func WriteUvarint(b *[]byte, v uint64) {
+	*b = AppendUvarint(*b, v)
+}
+
+// WriteVarint appends v at the end of b as a zigzag encoded signed LEB128 varint.
+func WriteVarint(b *[]byte, v int64) {
+	*b = AppendUvarint(*b, ZigZagEncode(v))
+}
+
+// varintError creates a *DecodeError for a varint decoding error err in b.
+func varintError(op string, off int, b []byte, err error) error {
+
+	if err == ErrShortBuffer {
+		return &DecodeError{Op: op, Offset: off, Want: len(b) + 1, Have: len(b), Err: err}
+	}
+
+	return &DecodeError{Op: op, Offset: off, Err: err}
+}
+
+// StrictVarint returns whether b rejects the non-minimal varint encodings.
+func (b *Buffer) StrictVarint() bool {
+	return b.strictVarint
+}
+
+// SetStrictVarint sets whether the varint reads of b (including the VarintLength prefixes)
+// reject the non-minimal encodings with a *DecodeError wrapping ErrVarintOverlong.
+// By default padded encodings are accepted up to MaxVarintLen64 bytes.
+// The mode is inherited by the child Buffers (eg.: ReadLengthPrefixed).
+func (b *Buffer) SetStrictVarint(strict bool) {
+	b.strictVarint = strict
+}
+
+// ReadUvarint reads an unsigned LEB128 varint from b.
+// Values that overflow 64 bits are rejected, non-minimal encodings only in strict mode (see SetStrictVarint).
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadUvarint() (uint64, bool) {
+
+	v, err := b.ReadUvarintE()
+
+	return v, err == nil
+}
+
+// ReadUvarintE is like ReadUvarint, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadUvarintE() (uint64, error) {
+
+	v, n, err := decodeUvarint(b.b[b.off:], b.strictVarint)
+	if err != nil {
+		return 0, varintError("ReadUvarint", b.base+b.off, b.b[b.off:], err)
+	}
+
+	b.off += n
+
+	return v, nil
+}
+
+// ReadVarint reads a zigzag encoded signed LEB128 varint from b.
+// The bool indicates whether the read was successful.
+func (b *Buffer) ReadVarint() (int64, bool) {
+
+	v, err := b.ReadVarintE()
+
+	return v, err == nil
+}
+
+// ReadVarintE is like ReadVarint, but returns a *DecodeError if the read failed.
+func (b *Buffer) ReadVarintE() (int64, error) {
+
+	v, n, err := decodeUvarint(b.b[b.off:], b.strictVarint)
+	if err != nil {
+		return 0, varintError("ReadVarint", b.base+b.off, b.b[b.off:], err)
+	}
+
+	b.off += n
+
+	return ZigZagDecode(v), nil
+}
+
+// WriteUvarint appends v at the end of b as an unsigned LEB128 varint.
+func (b *Buffer) WriteUvarint(v uint64) {
+	b.b = AppendUvarint(b.b, v)
+}
+
+// WriteVarint appends v at the end of b as a zigzag encoded signed LEB128 varint.
+func (b *Buffer) WriteVarint(v int64) {
+	b.b = AppendUvarint(b.b, ZigZagEncode(v))
+}
+
+// ReadUvarint reads an unsigned LEB128 varint.
+func (d *Decoder) ReadUvarint() uint64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadUvarintE()
+	*d.err = err
+
+	return v
+}
+
+// ReadVarint reads a zigzag encoded signed LEB128 varint.
+func (d *Decoder) ReadVarint() int64 {
+
+	if *d.err != nil {
+		return 0
+	}
+
+	v, err := d.b.ReadVarintE()
+	*d.err = err
+
+	return v
+}
+
+// StrictVarint returns whether s rejects the non-minimal varint encodings.
+func (s *StreamReader) StrictVarint() bool {
+	return s.strictVarint
+}
+
+// SetStrictVarint sets whether the varint reads of s (including the VarintLength prefixes)
+// reject the non-minimal encodings with a *DecodeError wrapping ErrVarintOverlong.
+// By default padded encodings are accepted up to MaxVarintLen64 bytes.
+func (s *StreamReader) SetStrictVarint(strict bool) {
+	s.strictVarint = strict
+}
+
+// ReadUvarint reads an unsigned LEB128 varint from s.
+// Values that overflow 64 bits are rejected, non-minimal encodings only in strict mode (see SetStrictVarint).
+func (s *StreamReader) ReadUvarint() (uint64, error) {
+
+	off := s.n
+
+	var (
+		v     uint64
+		shift uint
+	)
+
+	for i := 0; i < MaxVarintLen64; i++ {
+
+		c, err := s.ReadByte()
+		if err == io.EOF && i > 0 {
+			return 0, &DecodeError{Op: "ReadUvarint", Offset: int(off), Want: i + 1, Have: i, Err: io.ErrUnexpectedEOF}
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if i == MaxVarintLen64-1 && c > 1 {
+			break
+		}
+
+		v |= uint64(c&0x7f) << shift
+
+		if c < 0x80 {
+			if s.strictVarint && c == 0 && i > 0 {
+				return 0, &DecodeError{Op: "ReadUvarint", Offset: int(off), Err: ErrVarintOverlong}
+			}
+			return v, nil
+		}
+
+		shift += 7
+	}
+
+	return 0, &DecodeError{Op: "ReadUvarint", Offset: int(off), Err: ErrVarintOverflow}
+}
+
+// ReadVarint reads a zigzag encoded signed LEB128 varint from s.
+func (s *StreamReader) ReadVarint() (int64, error) {
+
+	v, err := s.ReadUvarint()
+
+	return ZigZagDecode(v), err
+}
+
+// WriteUvarint writes v to s as an unsigned LEB128 varint.
+func (s *StreamWriter) WriteUvarint(v uint64) {
+
+	if s.err != nil {
+		return
+	}
+
+	n := len(s.buf)
+	s.buf = AppendUvarint(s.buf, v)
+	s.advance(len(s.buf) - n)
+}
+
+// WriteVarint writes v to s as a zigzag encoded signed LEB128 varint.
+func (s *StreamWriter) WriteVarint(v int64) {
+	s.WriteUvarint(ZigZagEncode(v))
+}
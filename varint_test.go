@@ -0,0 +1,142 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestVarintRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{300, []byte{0xac, 0x02}},
+		{1<<64 - 1, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+
+	for _, tt := range tests {
+
+		data := AppendUvarint(nil, tt.v)
+		if !bytes.Equal(data, tt.want) {
+			t.Fatalf("AppendUvarint(%d) = % x, want % x", tt.v, data, tt.want)
+		}
+
+		if v, err := ReadUvarintStrictE(&data); err != nil || v != tt.v || len(data) != 0 {
+			t.Fatalf("ReadUvarintStrictE(% x) = %d, %v", tt.want, v, err)
+		}
+
+		b := NewEmpty()
+		b.WriteVarint(-int64(tt.v >> 1))
+
+		if v, err := b.ReadVarintE(); err != nil || v != -int64(tt.v>>1) {
+			t.Fatalf("ReadVarintE = %d, %v, want %d", v, err, -int64(tt.v>>1))
+		}
+	}
+}
+
+func TestVarintPadded(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		data   []byte
+		v      uint64
+		err    error // error of the default reads
+		strict error // error of the strict reads
+	}{
+		{"minimal", []byte{0x05}, 5, nil, nil},
+		{"padded", []byte{0x85, 0x80, 0x80, 0x00}, 5, nil, ErrVarintOverlong},
+		{"padded zero", []byte{0x80, 0x00}, 0, nil, ErrVarintOverlong},
+		{"max width", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 0, nil, ErrVarintOverlong},
+		{"too long", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 0, ErrVarintOverflow, ErrVarintOverflow},
+		{"overflow", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02}, 0, ErrVarintOverflow, ErrVarintOverflow},
+		{"truncated", []byte{0x80}, 0, ErrShortBuffer, ErrShortBuffer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			for _, strict := range []bool{false, true} {
+
+				want := tt.err
+				if strict {
+					want = tt.strict
+				}
+
+				b := NewBuffer(tt.data)
+				b.SetStrictVarint(strict)
+
+				v, err := b.ReadUvarintE()
+				if !errors.Is(err, want) || err == nil && v != tt.v {
+					t.Fatalf("strict %t: Buffer.ReadUvarintE = %d, %v, want %d, %v", strict, v, err, tt.v, want)
+				}
+
+				s := NewStreamReader(bytes.NewReader(tt.data))
+				s.SetStrictVarint(strict)
+
+				// The stream reports a truncated varint as io.ErrUnexpectedEOF.
+				if want == ErrShortBuffer {
+					want = io.ErrUnexpectedEOF
+				}
+
+				v, err = s.ReadUvarint()
+				if !errors.Is(err, want) || err == nil && v != tt.v {
+					t.Fatalf("strict %t: StreamReader.ReadUvarint = %d, %v, want %d, %v", strict, v, err, tt.v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestVarintErrorOffset(t *testing.T) {
+
+	b := NewBuffer([]byte{0x01, 0x80, 0x80})
+	b.Skip(1)
+
+	_, err := b.ReadVarintE()
+
+	var de *DecodeError
+
+	if !errors.As(err, &de) || de.Op != "ReadVarint" || de.Offset != 1 || !errors.Is(err, ErrShortBuffer) {
+		t.Fatalf("ReadVarintE error = %v, want ReadVarint at offset 1", err)
+	}
+
+	if b.Offset() != 1 {
+		t.Fatalf("failed ReadVarintE moved the cursor to %d", b.Offset())
+	}
+
+	// ZigZag maps the small negative numbers to small unsigned numbers.
+	for v, want := range map[int64]uint64{0: 0, -1: 1, 1: 2, -2: 3, -1 << 63: 1<<64 - 1} {
+		if ZigZagEncode(v) != want || ZigZagDecode(want) != v {
+			t.Fatalf("ZigZagEncode(%d) = %d, want %d", v, ZigZagEncode(v), want)
+		}
+	}
+}
+
+func TestVarintLengthVector(t *testing.T) {
+
+	data := bytes.Repeat([]byte{0xaa}, 300)
+
+	b := NewEmpty()
+	b.WriteVector(data, VarintLength)
+
+	if b.Size() != 2+len(data) {
+		t.Fatalf("wrote %d bytes, want %d", b.Size(), 2+len(data))
+	}
+
+	s := NewStreamReader(bytes.NewReader(b.Bytes()))
+
+	if v, err := b.ReadVectorE(VarintLength); err != nil || !bytes.Equal(v, data) {
+		t.Fatalf("Buffer.ReadVectorE: %v", err)
+	}
+
+	if v, err := s.ReadVector(VarintLength); err != nil || !bytes.Equal(v, data) {
+		t.Fatalf("StreamReader.ReadVector: %v", err)
+	}
+}
@@ -2,14 +2,80 @@ package bytebuilder
 
 import (
 	"crypto/rand"
+	"io"
+	"math"
 	"time"
 )
 
+// minRead is the minimum free space provided by ReadFrom for a Read call.
+const minRead = 512
+
 // WriteBytes appends bytes at the end of b.
 func (b *Buffer) WriteBytes(bytes ...byte) {
 	b.b = append(b.b, bytes...)
 }
 
+// Write appends the contents of p at the end of b.
+// The returned error is always nil.
+// Write implements the io.Writer interface.
+func (b *Buffer) Write(p []byte) (int, error) {
+
+	b.WriteBytes(p...)
+
+	return len(p), nil
+}
+
+// WriteByte appends c at the end of b.
+// The returned error is always nil.
+// WriteByte implements the io.ByteWriter interface.
+func (b *Buffer) WriteByte(c byte) error {
+
+	b.WriteBytes(c)
+
+	return nil
+}
+
+// WriteString appends the contents of s at the end of b.
+// The returned error is always nil.
+// WriteString implements the io.StringWriter interface.
+func (b *Buffer) WriteString(s string) (int, error) {
+
+	b.b = append(b.b, s...)
+
+	return len(s), nil
+}
+
+// ReadFrom reads data from r until EOF and appends it at the end of b, growing b as needed.
+// The return value n is the number of bytes read.
+// Any error except io.EOF encountered during the read is also returned.
+// ReadFrom implements the io.ReaderFrom interface.
+func (b *Buffer) ReadFrom(r io.Reader) (n int64, err error) {
+
+	for {
+
+		if cap(b.b)-len(b.b) < minRead {
+			nb := make([]byte, len(b.b), 2*cap(b.b)+minRead)
+			copy(nb, b.b)
+			b.b = nb
+		}
+
+		m, e := r.Read(b.b[len(b.b):cap(b.b)])
+		if m < 0 {
+			panic("bytebuilder.Buffer.ReadFrom: reader returned negative count from Read")
+		}
+
+		b.b = b.b[:len(b.b)+m]
+		n += int64(m)
+
+		if e == io.EOF {
+			return n, nil
+		}
+		if e != nil {
+			return n, e
+		}
+	}
+}
+
 // WriteUint8 appends v at the end of b.
 func (b *Buffer) WriteUint8(v uint8) {
 	b.WriteBytes(byte(v))
@@ -20,44 +86,172 @@ func (b *Buffer) WriteInt8(v int8) {
 	b.WriteBytes(byte(v))
 }
 
-// WriteUint16 appends v at the end of b.
+// WriteLittleUint16 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleUint16(v uint16) {
+	b.WriteBytes(byte(v), byte(v>>8))
+}
+
+// WriteBigUint16 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigUint16(v uint16) {
+	b.WriteBytes(byte(v>>8), byte(v))
+}
+
+// WriteUint16 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteUint16(v uint16) {
+
+	if b.little {
+		b.WriteLittleUint16(v)
+		return
+	}
+
+	b.WriteBigUint16(v)
+}
+
+// WriteLittleInt16 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleInt16(v int16) {
+	b.WriteBytes(byte(v), byte(v>>8))
+}
+
+// WriteBigInt16 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigInt16(v int16) {
 	b.WriteBytes(byte(v>>8), byte(v))
 }
 
-// WriteInt16 appends v at the end of b.
+// WriteInt16 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteInt16(v int16) {
-	b.WriteBytes(byte(v>>8), byte(v))
+
+	if b.little {
+		b.WriteLittleInt16(v)
+		return
+	}
+
+	b.WriteBigInt16(v)
 }
 
-// WriteUint24 appends v at the end of b.
+// WriteLittleUint24 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleUint24(v uint32) {
+	b.WriteBytes(byte(v), byte(v>>8), byte(v>>16))
+}
+
+// WriteBigUint24 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigUint24(v uint32) {
+	b.WriteBytes(byte(v>>16), byte(v>>8), byte(v))
+}
+
+// WriteUint24 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteUint24(v uint32) {
+
+	if b.little {
+		b.WriteLittleUint24(v)
+		return
+	}
+
+	b.WriteBigUint24(v)
+}
+
+// WriteLittleInt24 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleInt24(v int32) {
+	b.WriteBytes(byte(v), byte(v>>8), byte(v>>16))
+}
+
+// WriteBigInt24 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigInt24(v int32) {
 	b.WriteBytes(byte(v>>16), byte(v>>8), byte(v))
 }
 
-// WriteInt24 appends v at the end of b.
+// WriteInt24 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteInt24(v int32) {
-	b.WriteBytes(byte(v>>16), byte(v>>8), byte(v))
+
+	if b.little {
+		b.WriteLittleInt24(v)
+		return
+	}
+
+	b.WriteBigInt24(v)
+}
+
+// WriteLittleUint32 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleUint32(v uint32) {
+	b.WriteBytes(byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
 }
 
-// WriteUint32 appends v at the end of b.
+// WriteBigUint32 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigUint32(v uint32) {
+	b.WriteBytes(byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// WriteUint32 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteUint32(v uint32) {
+
+	if b.little {
+		b.WriteLittleUint32(v)
+		return
+	}
+
+	b.WriteBigUint32(v)
+}
+
+// WriteLittleInt32 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleInt32(v int32) {
+	b.WriteBytes(byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// WriteBigInt32 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigInt32(v int32) {
 	b.WriteBytes(byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
 }
 
-// WriteInt32 appends v at the end of b.
+// WriteInt32 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteInt32(v int32) {
-	b.WriteBytes(byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+
+	if b.little {
+		b.WriteLittleInt32(v)
+		return
+	}
+
+	b.WriteBigInt32(v)
 }
 
-// WriteUint64 appends v at the end of b.
+// WriteLittleUint64 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleUint64(v uint64) {
+	b.WriteBytes(byte(v), byte(v>>8), byte(v>>16), byte(v>>24), byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56))
+}
+
+// WriteBigUint64 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigUint64(v uint64) {
+	b.WriteBytes(byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// WriteUint64 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteUint64(v uint64) {
+
+	if b.little {
+		b.WriteLittleUint64(v)
+		return
+	}
+
+	b.WriteBigUint64(v)
+}
+
+// WriteLittleInt64 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleInt64(v int64) {
+	b.WriteBytes(byte(v), byte(v>>8), byte(v>>16), byte(v>>24), byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56))
+}
+
+// WriteBigInt64 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigInt64(v int64) {
 	b.WriteBytes(byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
 }
 
-// WriteInt64 appends v at the end of b.
+// WriteInt64 appends v at the end of b in the byte order of b.
 func (b *Buffer) WriteInt64(v int64) {
-	b.WriteBytes(byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+
+	if b.little {
+		b.WriteLittleInt64(v)
+		return
+	}
+
+	b.WriteBigInt64(v)
 }
 
 // WriteInt append v at the end of b with size bitSize.
@@ -94,24 +288,168 @@ func (b *Buffer) WriteRandom(n int) {
 
 // WriteVector appends the length of bytes then the bytes itself.
 // The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64).
-// Therefore, bitSize must be 8/16/24/32/64.
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength.
+// The length is written in the byte order of b.
 // If bitSize is an invalid number, this function panics.
+// If len(v) does not fit into the length type, the length is truncated, use WriteVectorE to detect it.
 func (b *Buffer) WriteVector(v []byte, bitSize int) {
 
+	b.writeLength(len(v), bitSize)
+	b.WriteBytes(v...)
+}
+
+// writeLength appends a vector length n with type depending on bitSize.
+// If bitSize is an invalid number, this function panics.
+func (b *Buffer) writeLength(n int, bitSize int) {
+
 	switch bitSize {
 	case 8:
-		b.WriteUint8(uint8(len(v)))
+		b.WriteUint8(uint8(n))
 	case 16:
-		b.WriteUint16(uint16(len(v)))
+		b.WriteUint16(uint16(n))
 	case 24:
-		b.WriteUint24(uint32(len(v)))
+		b.WriteUint24(uint32(n))
 	case 32:
-		b.WriteUint32(uint32(len(v)))
+		b.WriteUint32(uint32(n))
 	case 64:
-		b.WriteUint64(uint64(len(v)))
+		b.WriteUint64(uint64(n))
+	case VarintLength:
+		b.WriteUvarint(uint64(n))
+	case QUICVarintLength:
+		b.WriteQUICVarint(uint64(n))
 	default:
 		panic("invalid bitSize value")
 	}
+}
+
+// WriteLittleFloat32 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleFloat32(v float32) {
+	b.WriteLittleUint32(math.Float32bits(v))
+}
+
+// WriteBigFloat32 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigFloat32(v float32) {
+	b.WriteBigUint32(math.Float32bits(v))
+}
+
+// WriteFloat32 appends v at the end of b in the byte order of b.
+func (b *Buffer) WriteFloat32(v float32) {
+
+	if b.little {
+		b.WriteLittleFloat32(v)
+		return
+	}
 
-	b.WriteBytes(v...)
+	b.WriteBigFloat32(v)
+}
+
+// WriteLittleFloat64 appends v at the end of b in little-endian order.
+func (b *Buffer) WriteLittleFloat64(v float64) {
+	b.WriteLittleUint64(math.Float64bits(v))
+}
+
+// WriteBigFloat64 appends v at the end of b in big-endian order.
+func (b *Buffer) WriteBigFloat64(v float64) {
+	b.WriteBigUint64(math.Float64bits(v))
+}
+
+// WriteFloat64 appends v at the end of b in the byte order of b.
+func (b *Buffer) WriteFloat64(v float64) {
+
+	if b.little {
+		b.WriteLittleFloat64(v)
+		return
+	}
+
+	b.WriteBigFloat64(v)
+}
+
+// WriteLittleComplex64 appends v at the end of b in little-endian order (real part first).
+func (b *Buffer) WriteLittleComplex64(v complex64) {
+	b.WriteLittleUint32(math.Float32bits(real(v)))
+	b.WriteLittleUint32(math.Float32bits(imag(v)))
+}
+
+// WriteBigComplex64 appends v at the end of b in big-endian order (real part first).
+func (b *Buffer) WriteBigComplex64(v complex64) {
+	b.WriteBigUint32(math.Float32bits(real(v)))
+	b.WriteBigUint32(math.Float32bits(imag(v)))
+}
+
+// WriteComplex64 appends v at the end of b in the byte order of b (real part first).
+func (b *Buffer) WriteComplex64(v complex64) {
+
+	if b.little {
+		b.WriteLittleComplex64(v)
+		return
+	}
+
+	b.WriteBigComplex64(v)
+}
+
+// WriteLittleComplex128 appends v at the end of b in little-endian order (real part first).
+func (b *Buffer) WriteLittleComplex128(v complex128) {
+	b.WriteLittleUint64(math.Float64bits(real(v)))
+	b.WriteLittleUint64(math.Float64bits(imag(v)))
+}
+
+// WriteBigComplex128 appends v at the end of b in big-endian order (real part first).
+func (b *Buffer) WriteBigComplex128(v complex128) {
+	b.WriteBigUint64(math.Float64bits(real(v)))
+	b.WriteBigUint64(math.Float64bits(imag(v)))
+}
+
+// WriteComplex128 appends v at the end of b in the byte order of b (real part first).
+func (b *Buffer) WriteComplex128(v complex128) {
+
+	if b.little {
+		b.WriteLittleComplex128(v)
+		return
+	}
+
+	b.WriteBigComplex128(v)
+}
+
+// WriteVectorE is like WriteVector, but returns an *EncodeError instead of truncating the length or panicking.
+// If len(v) does not fit into the length type, the error wraps ErrLengthOverflow.
+// If bitSize is an invalid number, the error wraps ErrInvalidBitSize.
+// If an error is returned, b is not modified.
+func (b *Buffer) WriteVectorE(v []byte, bitSize int) error {
+
+	if err := checkVectorLength(len(v), bitSize); err != nil {
+		return &EncodeError{Op: "WriteVector", Length: len(v), Err: err}
+	}
+
+	b.WriteVector(v, bitSize)
+
+	return nil
+}
+
+// validBitSize returns whether bitSize is a valid length type for vectors.
+func validBitSize(bitSize int) bool {
+
+	switch bitSize {
+	case 8, 16, 24, 32, 64, VarintLength, QUICVarintLength:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkVectorLength returns ErrInvalidBitSize if bitSize is invalid
+// or ErrLengthOverflow if n does not fit into the length type.
+func checkVectorLength(n int, bitSize int) error {
+
+	if !validBitSize(bitSize) {
+		return ErrInvalidBitSize
+	}
+
+	switch {
+	case bitSize == QUICVarintLength && uint64(n) > MaxQUICVarint:
+		return ErrLengthOverflow
+	case bitSize > 0 && bitSize < 64 && uint64(n) >= 1<<bitSize:
+		return ErrLengthOverflow
+	}
+
+	return nil
 }
@@ -1,6 +1,9 @@
 package bytebuilder
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // WriteByte appends v at the end of b.
 func WriteByte(b *[]byte, v byte) {
@@ -197,3 +200,95 @@ func WriteInt64(b *[]byte, v int64) {
 		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
 	}
 }
+
+// WriteLittleFloat32 appends v at the end of b in little-endian order.
+func WriteLittleFloat32(b *[]byte, v float32) {
+	WriteLittleUint32(b, math.Float32bits(v))
+}
+
+// WriteBigFloat32 appends v at the end of b in big-endian order.
+func WriteBigFloat32(b *[]byte, v float32) {
+	WriteBigUint32(b, math.Float32bits(v))
+}
+
+// WriteFloat32 appends v at the end of b in native-endian order.
+func WriteFloat32(b *[]byte, v float32) {
+	switch NativeEndian {
+	case LittleEndian:
+		WriteLittleFloat32(b, v)
+	case BigEndian:
+		WriteBigFloat32(b, v)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// WriteLittleFloat64 appends v at the end of b in little-endian order.
+func WriteLittleFloat64(b *[]byte, v float64) {
+	WriteLittleUint64(b, math.Float64bits(v))
+}
+
+// WriteBigFloat64 appends v at the end of b in big-endian order.
+func WriteBigFloat64(b *[]byte, v float64) {
+	WriteBigUint64(b, math.Float64bits(v))
+}
+
+// WriteFloat64 appends v at the end of b in native-endian order.
+func WriteFloat64(b *[]byte, v float64) {
+	switch NativeEndian {
+	case LittleEndian:
+		WriteLittleFloat64(b, v)
+	case BigEndian:
+		WriteBigFloat64(b, v)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// WriteLittleComplex64 appends v at the end of b in little-endian order (real part first).
+func WriteLittleComplex64(b *[]byte, v complex64) {
+	WriteLittleUint32(b, math.Float32bits(real(v)))
+	WriteLittleUint32(b, math.Float32bits(imag(v)))
+}
+
+// WriteBigComplex64 appends v at the end of b in big-endian order (real part first).
+func WriteBigComplex64(b *[]byte, v complex64) {
+	WriteBigUint32(b, math.Float32bits(real(v)))
+	WriteBigUint32(b, math.Float32bits(imag(v)))
+}
+
+// WriteComplex64 appends v at the end of b in native-endian order (real part first).
+func WriteComplex64(b *[]byte, v complex64) {
+	switch NativeEndian {
+	case LittleEndian:
+		WriteLittleComplex64(b, v)
+	case BigEndian:
+		WriteBigComplex64(b, v)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
+
+// WriteLittleComplex128 appends v at the end of b in little-endian order (real part first).
+func WriteLittleComplex128(b *[]byte, v complex128) {
+	WriteLittleUint64(b, math.Float64bits(real(v)))
+	WriteLittleUint64(b, math.Float64bits(imag(v)))
+}
+
+// WriteBigComplex128 appends v at the end of b in big-endian order (real part first).
+func WriteBigComplex128(b *[]byte, v complex128) {
+	WriteBigUint64(b, math.Float64bits(real(v)))
+	WriteBigUint64(b, math.Float64bits(imag(v)))
+}
+
+// WriteComplex128 appends v at the end of b in native-endian order (real part first).
+func WriteComplex128(b *[]byte, v complex128) {
+	switch NativeEndian {
+	case LittleEndian:
+		WriteLittleComplex128(b, v)
+	case BigEndian:
+		WriteBigComplex128(b, v)
+	default:
+		panic(fmt.Sprintf("Invalid NativeEndian: %d", NativeEndian))
+	}
+}
@@ -1,6 +1,9 @@
 package bytebuilder
 
-import "io"
+import (
+	"io"
+	"math"
+)
 
 // WriteWriterBytes appends v at the end of in.
 func WriteWriterBytes(in io.Writer, bytes ...byte) error {
@@ -29,3 +32,548 @@ func WriteWriterUint24(in io.Writer, v uint32) error {
 func WriteWriterUint32(in io.Writer, v uint32) error {
 	return WriteWriterBytes(in, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
 }
+
+// defaultStreamWriterSize is the default size of the StreamWriter buffer.
+const defaultStreamWriterSize = 4096
+
+// StreamWriter encodes values into an internal buffer and writes it to an io.Writer
+// when the buffer is full or Flush is called.
+// The first error is recorded, every subsequent write is a no-op, and the error is returned by Err and Flush.
+//
+// Multi-byte integers are written in big-endian order by default,
+// the byte order can be changed with SetEndianness.
+type StreamWriter struct {
+	w      io.Writer
+	buf    []byte
+	size   int   // flush threshold
+	n      int64 // number of bytes written to s
+	err    error
+	little bool // use little-endian byte order
+}
+
+// NewStreamWriter creates a StreamWriter that writes to w with the default buffer size.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return NewStreamWriterSize(w, defaultStreamWriterSize)
+}
+
+// NewStreamWriterSize creates a StreamWriter that writes to w with a buffer of at least size bytes.
+// If size is less than or equal to zero, the default size is used.
+func NewStreamWriterSize(w io.Writer, size int) *StreamWriter {
+
+	if size <= 0 {
+		size = defaultStreamWriterSize
+	}
+
+	return &StreamWriter{w: w, buf: make([]byte, 0, size), size: size}
+}
+
+// Endianness returns the byte order used by s.
+func (s *StreamWriter) Endianness() Endianness {
+
+	if s.little {
+		return LittleEndian
+	}
+
+	return BigEndian
+}
+
+// SetEndianness sets the byte order used by s to e.
+func (s *StreamWriter) SetEndianness(e Endianness) {
+	s.little = e == LittleEndian
+}
+
+// Err returns the first error encountered by s, or nil.
+func (s *StreamWriter) Err() error {
+	return s.err
+}
+
+// Written returns the number of bytes written to s, including the buffered bytes.
+func (s *StreamWriter) Written() int64 {
+	return s.n
+}
+
+// Buffered returns the number of bytes that have been written to s but not yet to the underlying writer.
+func (s *StreamWriter) Buffered() int {
+	return len(s.buf)
+}
+
+// Flush writes the buffered bytes to the underlying writer.
+// Returns the first error encountered by s.
+func (s *StreamWriter) Flush() error {
+
+	if s.err != nil {
+		return s.err
+	}
+
+	if len(s.buf) == 0 {
+		return nil
+	}
+
+	n, err := s.w.Write(s.buf)
+	if n < len(s.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+
+	if err != nil {
+		s.buf = s.buf[:copy(s.buf, s.buf[n:])]
+		s.err = err
+		return err
+	}
+
+	s.buf = s.buf[:0]
+
+	return nil
+}
+
+// advance records that n bytes were appended to the buffer and flushes it if it is full.
+func (s *StreamWriter) advance(n int) {
+
+	s.n += int64(n)
+
+	if len(s.buf) >= s.size {
+		s.Flush()
+	}
+}
+
+// Write writes the contents of p to s.
+// Write implements the io.Writer interface.
+func (s *StreamWriter) Write(p []byte) (int, error) {
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	s.buf = append(s.buf, p...)
+	s.advance(len(p))
+
+	return len(p), nil
+}
+
+// WriteByte writes c to s.
+// WriteByte implements the io.ByteWriter interface.
+func (s *StreamWriter) WriteByte(c byte) error {
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.buf = append(s.buf, c)
+	s.advance(1)
+
+	return nil
+}
+
+// WriteBytes writes bytes to s.
+func (s *StreamWriter) WriteBytes(bytes ...byte) {
+
+	if s.err != nil {
+		return
+	}
+
+	s.buf = append(s.buf, bytes...)
+	s.advance(len(bytes))
+}
+
+// WriteUint8 writes v to s.
+func (s *StreamWriter) WriteUint8(v uint8) {
+	s.WriteBytes(byte(v))
+}
+
+// WriteInt8 writes v to s.
+func (s *StreamWriter) WriteInt8(v int8) {
+	s.WriteBytes(byte(v))
+}
+
+// WriteLittleUint16 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleUint16(v uint16) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleUint16(&s.buf, v)
+	s.advance(2)
+}
+
+// WriteBigUint16 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigUint16(v uint16) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigUint16(&s.buf, v)
+	s.advance(2)
+}
+
+// WriteUint16 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteUint16(v uint16) {
+
+	if s.little {
+		s.WriteLittleUint16(v)
+		return
+	}
+
+	s.WriteBigUint16(v)
+}
+
+// WriteLittleInt16 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleInt16(v int16) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleInt16(&s.buf, v)
+	s.advance(2)
+}
+
+// WriteBigInt16 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigInt16(v int16) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigInt16(&s.buf, v)
+	s.advance(2)
+}
+
+// WriteInt16 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteInt16(v int16) {
+
+	if s.little {
+		s.WriteLittleInt16(v)
+		return
+	}
+
+	s.WriteBigInt16(v)
+}
+
+// WriteLittleUint24 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleUint24(v uint32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleUint24(&s.buf, v)
+	s.advance(3)
+}
+
+// WriteBigUint24 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigUint24(v uint32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigUint24(&s.buf, v)
+	s.advance(3)
+}
+
+// WriteUint24 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteUint24(v uint32) {
+
+	if s.little {
+		s.WriteLittleUint24(v)
+		return
+	}
+
+	s.WriteBigUint24(v)
+}
+
+// WriteLittleInt24 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleInt24(v int32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleInt24(&s.buf, v)
+	s.advance(3)
+}
+
+// WriteBigInt24 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigInt24(v int32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigInt24(&s.buf, v)
+	s.advance(3)
+}
+
+// WriteInt24 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteInt24(v int32) {
+
+	if s.little {
+		s.WriteLittleInt24(v)
+		return
+	}
+
+	s.WriteBigInt24(v)
+}
+
+// WriteLittleUint32 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleUint32(v uint32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleUint32(&s.buf, v)
+	s.advance(4)
+}
+
+// WriteBigUint32 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigUint32(v uint32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigUint32(&s.buf, v)
+	s.advance(4)
+}
+
+// WriteUint32 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteUint32(v uint32) {
+
+	if s.little {
+		s.WriteLittleUint32(v)
+		return
+	}
+
+	s.WriteBigUint32(v)
+}
+
+// WriteLittleInt32 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleInt32(v int32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleInt32(&s.buf, v)
+	s.advance(4)
+}
+
+// WriteBigInt32 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigInt32(v int32) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigInt32(&s.buf, v)
+	s.advance(4)
+}
+
+// WriteInt32 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteInt32(v int32) {
+
+	if s.little {
+		s.WriteLittleInt32(v)
+		return
+	}
+
+	s.WriteBigInt32(v)
+}
+
+// WriteLittleUint64 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleUint64(v uint64) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleUint64(&s.buf, v)
+	s.advance(8)
+}
+
+// WriteBigUint64 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigUint64(v uint64) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigUint64(&s.buf, v)
+	s.advance(8)
+}
+
+// WriteUint64 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteUint64(v uint64) {
+
+	if s.little {
+		s.WriteLittleUint64(v)
+		return
+	}
+
+	s.WriteBigUint64(v)
+}
+
+// WriteLittleInt64 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleInt64(v int64) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteLittleInt64(&s.buf, v)
+	s.advance(8)
+}
+
+// WriteBigInt64 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigInt64(v int64) {
+
+	if s.err != nil {
+		return
+	}
+
+	WriteBigInt64(&s.buf, v)
+	s.advance(8)
+}
+
+// WriteInt64 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteInt64(v int64) {
+
+	if s.little {
+		s.WriteLittleInt64(v)
+		return
+	}
+
+	s.WriteBigInt64(v)
+}
+
+// WriteVector writes the length of bytes then the bytes itself.
+// The length type is depend on bitSize (eg.: uint8, uint16, uint24, uint32, uint64)
+// and it is written in the byte order of s.
+// Therefore, bitSize must be 8/16/24/32/64, VarintLength or QUICVarintLength.
+// If bitSize is invalid or len(v) does not fit into the length type, an *EncodeError is recorded.
+func (s *StreamWriter) WriteVector(v []byte, bitSize int) {
+
+	if s.err != nil {
+		return
+	}
+
+	if err := checkVectorLength(len(v), bitSize); err != nil {
+		s.err = &EncodeError{Op: "WriteVector", Length: len(v), Err: err}
+		return
+	}
+
+	s.writeLength(len(v), bitSize)
+	s.WriteBytes(v...)
+}
+
+// writeLength writes a vector length n with type depending on bitSize.
+// bitSize must be checked by the caller.
+func (s *StreamWriter) writeLength(n int, bitSize int) {
+
+	switch bitSize {
+	case 8:
+		s.WriteUint8(uint8(n))
+	case 16:
+		s.WriteUint16(uint16(n))
+	case 24:
+		s.WriteUint24(uint32(n))
+	case 32:
+		s.WriteUint32(uint32(n))
+	case 64:
+		s.WriteUint64(uint64(n))
+	case VarintLength:
+		s.WriteUvarint(uint64(n))
+	case QUICVarintLength:
+		s.WriteQUICVarint(uint64(n))
+	}
+}
+
+// WriteLittleFloat32 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleFloat32(v float32) {
+	s.WriteLittleUint32(math.Float32bits(v))
+}
+
+// WriteBigFloat32 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigFloat32(v float32) {
+	s.WriteBigUint32(math.Float32bits(v))
+}
+
+// WriteFloat32 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteFloat32(v float32) {
+
+	if s.little {
+		s.WriteLittleFloat32(v)
+		return
+	}
+
+	s.WriteBigFloat32(v)
+}
+
+// WriteLittleFloat64 writes v to s in little-endian order.
+func (s *StreamWriter) WriteLittleFloat64(v float64) {
+	s.WriteLittleUint64(math.Float64bits(v))
+}
+
+// WriteBigFloat64 writes v to s in big-endian order.
+func (s *StreamWriter) WriteBigFloat64(v float64) {
+	s.WriteBigUint64(math.Float64bits(v))
+}
+
+// WriteFloat64 writes v to s in the byte order of s.
+func (s *StreamWriter) WriteFloat64(v float64) {
+
+	if s.little {
+		s.WriteLittleFloat64(v)
+		return
+	}
+
+	s.WriteBigFloat64(v)
+}
+
+// WriteLittleComplex64 writes v to s in little-endian order (real part first).
+func (s *StreamWriter) WriteLittleComplex64(v complex64) {
+	s.WriteLittleUint32(math.Float32bits(real(v)))
+	s.WriteLittleUint32(math.Float32bits(imag(v)))
+}
+
+// WriteBigComplex64 writes v to s in big-endian order (real part first).
+func (s *StreamWriter) WriteBigComplex64(v complex64) {
+	s.WriteBigUint32(math.Float32bits(real(v)))
+	s.WriteBigUint32(math.Float32bits(imag(v)))
+}
+
+// WriteComplex64 writes v to s in the byte order of s (real part first).
+func (s *StreamWriter) WriteComplex64(v complex64) {
+
+	if s.little {
+		s.WriteLittleComplex64(v)
+		return
+	}
+
+	s.WriteBigComplex64(v)
+}
+
+// WriteLittleComplex128 writes v to s in little-endian order (real part first).
+func (s *StreamWriter) WriteLittleComplex128(v complex128) {
+	s.WriteLittleUint64(math.Float64bits(real(v)))
+	s.WriteLittleUint64(math.Float64bits(imag(v)))
+}
+
+// WriteBigComplex128 writes v to s in big-endian order (real part first).
+func (s *StreamWriter) WriteBigComplex128(v complex128) {
+	s.WriteBigUint64(math.Float64bits(real(v)))
+	s.WriteBigUint64(math.Float64bits(imag(v)))
+}
+
+// WriteComplex128 writes v to s in the byte order of s (real part first).
+func (s *StreamWriter) WriteComplex128(v complex128) {
+
+	if s.little {
+		s.WriteLittleComplex128(v)
+		return
+	}
+
+	s.WriteBigComplex128(v)
+}
@@ -0,0 +1,110 @@
+package bytebuilder
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// failWriter accepts n bytes, then fails with err.
+type failWriter struct {
+	n   int
+	err error
+	bytes.Buffer
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+
+	if len(p) > w.n {
+		n, _ := w.Buffer.Write(p[:w.n])
+		w.n = 0
+		return n, w.err
+	}
+
+	w.n -= len(p)
+
+	return w.Buffer.Write(p)
+}
+
+func TestStreamWriter(t *testing.T) {
+
+	tests := []struct {
+		e    Endianness
+		want []byte
+	}{
+		{BigEndian, []byte{0x01, 0x01, 0x02, 0xff, 0xff, 0xff, 0xfe, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x00, 0x02, 'a', 'b', 0x01, 0x02}},
+		{LittleEndian, []byte{0x01, 0x02, 0x01, 0xfe, 0xff, 0xff, 0xff, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0x02, 0x00, 'a', 'b', 0x01, 0x02}},
+	}
+
+	for _, tt := range tests {
+
+		var w bytes.Buffer
+
+		s := NewStreamWriterSize(&w, 4)
+		s.SetEndianness(tt.e)
+		s.WriteUint8(1)
+		s.WriteUint16(0x0102)
+		s.WriteInt32(-2)
+		s.WriteUint64(0x0102030405060708)
+		s.WriteVector([]byte("ab"), 16)
+		s.WriteBigUint16(0x0102)
+
+		// The buffer is written to w once it reaches the size.
+		if s.Written() != int64(len(tt.want)) || s.Buffered() >= 4 || w.Len()+s.Buffered() != len(tt.want) {
+			t.Fatalf("%v: Written = %d, Buffered = %d, w has %d bytes", tt.e, s.Written(), s.Buffered(), w.Len())
+		}
+
+		if err := s.Flush(); err != nil {
+			t.Fatalf("%v: Flush: %s", tt.e, err)
+		}
+
+		if !bytes.Equal(w.Bytes(), tt.want) || s.Buffered() != 0 {
+			t.Fatalf("%v: wrote % x, want % x", tt.e, w.Bytes(), tt.want)
+		}
+	}
+}
+
+func TestStreamWriterStickyError(t *testing.T) {
+
+	errWrite := errors.New("write failed")
+
+	tests := []struct {
+		name string
+		w    io.Writer
+		err  error
+	}{
+		{"error", &failWriter{n: 3, err: errWrite}, errWrite},
+		{"short write", &failWriter{n: 3}, io.ErrShortWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := NewStreamWriterSize(tt.w, 4)
+			s.WriteUint32(0x01020304)
+
+			if err := s.Err(); !errors.Is(err, tt.err) {
+				t.Fatalf("Err = %v, want %v", err, tt.err)
+			}
+
+			// The unwritten bytes stay in the buffer and every later write is a no-op.
+			s.WriteUint64(1)
+
+			if _, err := s.Write([]byte{1}); !errors.Is(err, tt.err) || s.Buffered() != 1 || s.Written() != 4 {
+				t.Fatalf("Write after error = %v, Buffered = %d, Written = %d", err, s.Buffered(), s.Written())
+			}
+
+			if err := s.Flush(); !errors.Is(err, tt.err) {
+				t.Fatalf("Flush = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	s := NewStreamWriter(io.Discard)
+	s.WriteVector([]byte{1}, 12)
+
+	if err := s.Flush(); !errors.Is(err, ErrInvalidBitSize) || s.Written() != 0 {
+		t.Fatalf("WriteVector with invalid bitSize: Flush = %v, Written = %d", err, s.Written())
+	}
+}